@@ -2,7 +2,9 @@ package main
 
 import (
 	"bytes"
-	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
@@ -16,6 +18,8 @@ import (
 	"sync"
 	"syscall"
 	"time"
+
+	fl "file-uploader/pkg/file"
 )
 
 const LIMIT = 5
@@ -59,12 +63,99 @@ func (up *UploadProgress) GetProgress() (uploaded, failed, total int) {
 	return up.uploaded, up.failed, up.totalChunks
 }
 
-// Gerçekten benzersiz ID üretir
-func generateUniqueUploadID() string {
-	timestamp := time.Now().UnixNano()
-	randomBytes := make([]byte, 8)
-	rand.Read(randomBytes)
-	return fmt.Sprintf("upload-%d-%x", timestamp, randomBytes)
+type initUploadResponse struct {
+	UploadID    string    `json:"upload_id"`
+	ChunkSize   int64     `json:"chunk_size"`
+	TotalChunks int       `json:"total_chunks"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// Sunucudan upload ID alır, chunk boyutu ve sayısı oturumda sabitlenir
+func initUpload(server, filename string, totalSize, chunkSize int64) (*initUploadResponse, error) {
+	payload, err := json.Marshal(map[string]interface{}{
+		"filename":   filename,
+		"total_size": totalSize,
+		"chunk_size": chunkSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.Post(strings.TrimRight(server, "/")+"/upload/init", "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("HTTP %d %s", resp.StatusCode, string(respBody))
+	}
+
+	var session initUploadResponse
+	if err := json.Unmarshal(respBody, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+type chunkQueryItem struct {
+	Index int    `json:"index"`
+	Hash  string `json:"hash"`
+	Size  int64  `json:"size"`
+}
+
+type chunkQueryResponse struct {
+	Existing []int `json:"existing"`
+	Missing  []int `json:"missing"`
+}
+
+// Chunk'ların SHA-256'larını hesaplar (chunk_hash olarak gönderilir ve havuz sorgusunda kullanılır)
+func hashChunks(file *os.File, totalSize, chunkSize int64, totalChunks int) ([]chunkQueryItem, error) {
+	items := make([]chunkQueryItem, 0, totalChunks)
+	for i := 1; i <= totalChunks; i++ {
+		start := int64(i-1) * chunkSize
+		length := chunkSize
+		if start+length > totalSize {
+			length = totalSize - start
+		}
+
+		h := sha256.New()
+		if _, err := io.Copy(h, io.NewSectionReader(file, start, length)); err != nil {
+			return nil, fmt.Errorf("chunk %d okunamadı: %w", i, err)
+		}
+		items = append(items, chunkQueryItem{Index: i, Hash: hex.EncodeToString(h.Sum(nil)), Size: length})
+	}
+	return items, nil
+}
+
+// Sunucunun havuzunda zaten bulunan chunk'ları sorar, bunlar tekrar gönderilmez
+func queryChunks(server, uploadID string, items []chunkQueryItem) (map[int]bool, error) {
+	payload, err := json.Marshal(map[string]interface{}{"chunks": items})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.Post(strings.TrimRight(server, "/")+"/upload/"+uploadID+"/chunks/query", "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d %s", resp.StatusCode, string(respBody))
+	}
+
+	var result chunkQueryResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, err
+	}
+	existing := make(map[int]bool, len(result.Existing))
+	for _, index := range result.Existing {
+		existing[index] = true
+	}
+	return existing, nil
 }
 
 func main() {
@@ -75,7 +166,7 @@ func main() {
 		"Yüklenecek dosyanın yolu",
 	)
 	chunkSize := flag.Int64("chunk-size", 10*1024*1024, "Chunk size in bytes (default 10MB)")
-	uploadID := flag.String("upload-id", "", "Upload session ID")
+	uploadID := flag.String("upload-id", "", "Devam ettirilecek upload session ID (boşsa /upload/init ile yeni oturum açılır)")
 	flag.Parse()
 
 	file, err := os.Open(*filePath)
@@ -97,7 +188,13 @@ func main() {
 	totalChunks := int((totalSize + *chunkSize - 1) / *chunkSize)
 
 	if strings.TrimSpace(*uploadID) == "" {
-		*uploadID = generateUniqueUploadID()
+		session, err := initUpload(*server, filename, totalSize, *chunkSize)
+		if err != nil {
+			log.Fatalf("Upload oturumu açılamadı: %v\n", err)
+		}
+		*uploadID = session.UploadID
+		totalChunks = session.TotalChunks
+		fmt.Printf("Oturum bitiş zamanı: %s\n", session.ExpiresAt.Format(time.RFC3339))
 	}
 
 	fmt.Printf("Sunucu: %s\n", *server)
@@ -106,13 +203,25 @@ func main() {
 	fmt.Printf("Chunk size: %d bytes | Total chunks: %d\n", *chunkSize, totalChunks)
 	fmt.Println("Ctrl+C ile iptal edebilirsiniz...")
 
+	chunkHashes, err := hashChunks(file, totalSize, *chunkSize, totalChunks)
+	if err != nil {
+		log.Fatalf("Chunk hash'leri hesaplanamadı: %v\n", err)
+	}
+	existing, err := queryChunks(*server, *uploadID, chunkHashes)
+	if err != nil {
+		log.Printf("Chunk sorgusu başarısız, tüm chunk'lar gönderilecek: %v\n", err)
+		existing = map[int]bool{}
+	} else if len(existing) > 0 {
+		fmt.Printf("Sunucuda mevcut %d chunk atlanacak\n", len(existing))
+	}
+
 	// İptal sinyalini yakala
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
 
 	sem := make(chan struct{}, LIMIT)
 	var wg sync.WaitGroup
-	progress := &UploadProgress{totalChunks: totalChunks, startTime: time.Now()}
+	progress := &UploadProgress{totalChunks: totalChunks, uploaded: len(existing), startTime: time.Now()}
 
 	// Progress gösterimi için ayrı goroutine
 	done := make(chan bool)
@@ -138,6 +247,9 @@ func main() {
 			log.Println("\nUpload iptal edildi, chunk gönderme durduruldu.")
 			break
 		}
+		if existing[i] {
+			continue
+		}
 
 		wg.Add(1)
 		go func(chunkNum int) {
@@ -170,6 +282,7 @@ func main() {
 			writer.WriteField("upload_id", *uploadID)
 			writer.WriteField("chunk_index", fmt.Sprintf("%d", chunkNum))
 			writer.WriteField("filename", filename)
+			writer.WriteField("chunk_hash", chunkHashes[chunkNum-1].Hash)
 
 			part, err := writer.CreateFormFile("file", filename)
 			if err != nil {
@@ -232,11 +345,18 @@ func main() {
 		} else {
 			// Tamamlama isteği sadece başarılı olursa gönderilir
 			fmt.Println("Dosya birleştiriliyor...")
+			// Sunucu birleşik dosyayı bu hash ile doğrular
+			fileHash, err := fl.CalculateFileHash(*filePath)
+			if err != nil {
+				log.Fatalf("Dosya hash'i hesaplanamadı: %v\n", err)
+			}
+
 			var completeBody bytes.Buffer
 			cw := multipart.NewWriter(&completeBody)
 			cw.WriteField("upload_id", *uploadID)
 			cw.WriteField("total_chunks", fmt.Sprintf("%d", totalChunks))
 			cw.WriteField("filename", filename)
+			cw.WriteField("sha256", fileHash)
 			cw.Close()
 
 			resp, err := http.Post(
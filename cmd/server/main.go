@@ -16,8 +16,12 @@ import (
 	"file-uploader/pkg/config"
 	consts "file-uploader/pkg/constants"
 
+	"file-uploader/internal/delivery/http/middleware"
 	"file-uploader/internal/delivery/http/routers"
+	"file-uploader/internal/domain/repositories"
+	"file-uploader/internal/infrastructure/auth"
 	"file-uploader/internal/infrastructure/db"
+	"file-uploader/internal/infrastructure/events"
 	"file-uploader/internal/infrastructure/queue"
 	infra_repo "file-uploader/internal/infrastructure/repositories"
 	"file-uploader/internal/infrastructure/storage"
@@ -43,9 +47,15 @@ func main() {
 		log.Fatalf("DB bağlantısı başarısız: %v", err)
 	}
 
-	rdb := redis.NewClient(&redis.Options{
-		Addr: fmt.Sprintf("%s:%s", os.Getenv("REDIS_HOST"), os.Getenv("REDIS_PORT")),
-	})
+	if cfg.Queue.Backend == queue.BackendMemory && cfg.Queue.InProcessWorkers < 1 {
+		log.Fatal("memory kuyruğu yalnızca server içi worker'larla çalışır (QUEUE_INPROCESS_WORKERS > 0)")
+	}
+	var rdb *redis.Client
+	if cfg.Queue.Backend == queue.BackendRedis || cfg.Events.Backend == events.BackendRedis {
+		rdb = redis.NewClient(&redis.Options{
+			Addr: fmt.Sprintf("%s:%s", os.Getenv("REDIS_HOST"), os.Getenv("REDIS_PORT")),
+		})
+	}
 
 	sqlDB, err := database.DB()
 	if err != nil {
@@ -58,34 +68,108 @@ func main() {
 		}
 	}
 
-	config.EnsureDirs()
 	goose.SetBaseFS(nil)
 
 	app := fiber.New(fiber.Config{
 		BodyLimit: int(cfg.Upload.MaxFileSize),
+		// Gövde bellekte biriktirilmeden okunur, multipart chunk'lar doğrudan staging'e stream edilir
+		StreamRequestBody: true,
 	})
 
 	// Middleware
 	app.Use(logger.New())
-	app.Use(cors.New())
+	app.Use(cors.New(cors.Config{
+		// tus ve S3 istemcilerinin (tarayıcı) okuyabilmesi gereken header'lar
+		ExposeHeaders: "Location,Upload-Offset,Upload-Length,Upload-Expires,Upload-Metadata,Tus-Resumable,Tus-Version,Tus-Extension,Tus-Max-Size,Tus-Checksum-Algorithm,ETag",
+	}))
 
 	// Swagger UI
 	app.Get("/swagger/*", swagger.HandlerDefault)
 
 	// Repositories & Services
-	fileRepo := infra_repo.NewFileUploadRepository(cfg.Upload.TempDir, cfg.Upload.UploadsDir, database)
-	localStorage := storage.NewLocalStorage(cfg.Upload.UploadsDir)
+	fileStorage, err := storage.New(cfg.Storage, cfg.Upload.UploadsDir)
+	if err != nil {
+		log.Fatalf("Storage oluşturulamadı: %v", err)
+	}
+	fileStorage, err = storage.WithEncryption(fileStorage, cfg.Storage, cfg.Encryption, infra_repo.NewEncryptedObjectRepository(database))
+	if err != nil {
+		log.Fatalf("Storage şifrelemesi başlatılamadı: %v", err)
+	}
+	fileRepo := infra_repo.NewFileUploadRepository(cfg.Upload.TempDir, cfg.Upload.UploadsDir, cfg.Upload.ChunkPoolDir, fileStorage, database)
+	sessionStore := infra_repo.NewUploadSessionRepository(database)
 	mediaRepo := infra_repo.NewMediaRepository(database)
 	variantRepo := infra_repo.NewMediaVariantRepository(database, mediaRepo)
 	sizeRepo := infra_repo.NewMediaSizeRepository(database)
 	videoRepo := infra_repo.NewVideoRepository(database)
-	mediaService := usecases.NewMediaService(mediaRepo, variantRepo, sizeRepo, localStorage, videoRepo)
+	blobRepo := infra_repo.NewBlobRepository(database)
+	mediaJobRepo := infra_repo.NewMediaJobRepository(database)
+	quotaService := usecases.NewQuotaService(infra_repo.NewUsageRepository(database), fileStorage, cfg.Quota)
+	mediaService := usecases.NewMediaService(mediaRepo, variantRepo, sizeRepo, fileStorage, videoRepo, blobRepo, mediaJobRepo, quotaService)
+
+	jobQueue, err := queue.New(cfg.Queue, queue.JobStream, queue.WorkerGroup, queue.ConsumerName("server"), rdb, database)
+	if err != nil {
+		log.Fatalf("Job kuyruğu oluşturulamadı: %v", err)
+	}
+	processedQueue, err := queue.New(cfg.Queue, queue.ProcessedStream, queue.ServerGroup, queue.ConsumerName("server"), rdb, database)
+	if err != nil {
+		log.Fatalf("Processed kuyruğu oluşturulamadı: %v", err)
+	}
+
+	jobRepo := infra_repo.NewJobRepository(database)
+	webhookRepo := infra_repo.NewWebhookRepository(database)
+	eventBus, err := events.New(cfg.Events.Backend, rdb)
+	if err != nil {
+		log.Fatalf("Olay yolu oluşturulamadı: %v", err)
+	}
 
-	uploadService := usecases.NewUploadService(fileRepo, localStorage, rdb, mediaService)
+	// Tek binary kurulum: worker havuzu ve temizlik job'ları server içinde çalışır
+	var workerPool *queue.WorkerPool
+	if cfg.Queue.InProcessWorkers > 0 {
+		workerPool = queue.NewWorkerPool(cfg.Queue.InProcessWorkers, queue.HeartbeatInterval(cfg.Queue.VisibilityTimeout), jobQueue, processedQueue, fileRepo, sessionStore, jobRepo, eventBus)
+		cleanupCron := usecases.ScheduleCleanup(usecases.NewCleanupService(fileRepo), cfg.Upload)
+		defer cleanupCron.Stop()
+		reconcileCron := usecases.ScheduleUsageReconcile(quotaService, cfg.Quota)
+		defer reconcileCron.Stop()
+		trashCron := usecases.ScheduleTrashPurge(mediaService, cfg.Trash)
+		defer trashCron.Stop()
+		dispatchCtx, stopDispatcher := context.WithCancel(context.Background())
+		defer stopDispatcher()
+		go usecases.NewWebhookDispatcher(infra_repo.NewOutboxRepository(database), webhookRepo, cfg.Webhook).Start(dispatchCtx)
+		log.Printf("%d worker server içinde başlatıldı (kuyruk: %s)", cfg.Queue.InProcessWorkers, cfg.Queue.Backend)
+	}
+
+	uploadService := usecases.NewUploadService(fileRepo, sessionStore, fileStorage, jobQueue, jobRepo, eventBus, mediaService, quotaService, cfg.Upload)
+	tusService := usecases.NewTusService(fileRepo, sessionStore, uploadService, quotaService, cfg.Upload)
+	s3Service := usecases.NewS3GatewayService(fileRepo, sessionStore, uploadService, quotaService, cfg.Upload)
+	deadLetterService := usecases.NewDeadLetterService(infra_repo.NewFailedJobRepository(database), infra_repo.NewAuditLogRepository(database), sessionStore, jobQueue, jobRepo)
+
+	apiKeyRepo := infra_repo.NewAPIKeyRepository(database)
+	authenticator, err := auth.NewAuthenticator(cfg.Auth, apiKeyRepo)
+	if err != nil {
+		log.Fatalf("Kimlik doğrulama yapılandırılamadı: %v", err)
+	}
+	if !authenticator.Enabled() {
+		log.Print("UYARI: AUTH_ENABLED=false, API kimlik doğrulamasız ve tenant ayrımı olmadan çalışıyor")
+	}
+	signer, err := auth.NewURLSigner(cfg.SignedURL)
+	if err != nil {
+		log.Fatalf("İmzalı URL anahtarları yapılandırılamadı: %v", err)
+	}
+
+	// /api/v1 altındaki tüm route'lar API key, JWT ya da imzalı URL ister; S3 gateway kendi SigV4 doğrulamasını kullanır
+	app.Use("/api/v1", middleware.Authenticate(authenticator, signer))
 
 	// Routes
 	routers.SetupUploadRoutes(app, uploadService)
-	routers.SetupMediaRoutes(app, cfg, database)
+	routers.SetupTusRoutes(app, tusService)
+	routers.SetupS3Routes(app, s3Service, cfg.S3Gateway)
+	routers.SetupMediaRoutes(app, cfg, database, fileStorage)
+	routers.SetupAdminRoutes(app, deadLetterService, usecases.NewAPIKeyService(apiKeyRepo))
+	routers.SetupJobRoutes(app, usecases.NewJobService(jobRepo, sessionStore))
+	routers.SetupEventRoutes(app, usecases.NewProgressService(sessionStore, eventBus))
+	routers.SetupWebhookRoutes(app, usecases.NewWebhookService(webhookRepo))
+	routers.SetupUsageRoutes(app, quotaService)
+	routers.SetupSignedURLRoutes(app, usecases.NewSignedURLService(signer, sessionStore, mediaService, cfg.SignedURL, cfg.Upload), cfg.Content)
 
 	// Health check
 	app.Get("/health", func(c *fiber.Ctx) error {
@@ -96,7 +180,7 @@ func main() {
 	log.Printf("Server starting on %s", addr)
 
 	// Processed queue listener (callback tetikleyici)
-	go startProcessedQueueListener(rdb, uploadService)
+	go startProcessedQueueListener(processedQueue, queue.HeartbeatInterval(cfg.Queue.VisibilityTimeout), uploadService, jobRepo, eventBus)
 
 	// Graceful shutdown
 	go func() {
@@ -116,32 +200,66 @@ func main() {
 	if err := app.ShutdownWithContext(ctxShut); err != nil {
 		log.Fatalf("Server düzgün kapatılamadı: %v", err)
 	}
+	if workerPool != nil {
+		workerPool.Shutdown()
+	}
 	log.Println("Server düzgün bir şekilde kapatıldı")
 }
 
 // Processed queue listener
-func startProcessedQueueListener(rdb *redis.Client, uploadService usecases.UploadService) {
+func startProcessedQueueListener(processedQueue queue.JobQueue, heartbeat time.Duration, uploadService usecases.UploadService, jobRepo repositories.JobRepository, publisher events.Publisher) {
 	ctx := context.Background()
 	for {
-		val, err := rdb.BRPop(ctx, 0, "processed_queue").Result()
+		msg, err := processedQueue.Dequeue(ctx)
 		if err != nil {
-			log.Println("BRPop failed:", err)
+			log.Println("Dequeue failed:", err)
 			time.Sleep(time.Second)
 			continue
 		}
 
 		var processed queue.ProcessedJob
-		if err := json.Unmarshal([]byte(val[1]), &processed); err != nil {
+		if err := json.Unmarshal(msg.Body, &processed); err != nil {
 			log.Println("Deserialize processed job failed:", err)
+			if err := processedQueue.Ack(ctx, msg); err != nil {
+				log.Println("Ack failed:", err)
+			}
 			continue
 		}
 
-		if err := uploadService.HandleMergeSuccess(processed.UploadID, processed.Filename, processed.MergedFilePath, processed.TotalChunks); err != nil {
+		// Her teslimde çöken (mesajı onaylanmadan devralınan) sonuç tekrar işlenmez, upload failed olur
+		if msg.Attempts > consts.MaxRetryJobs {
+			err = fmt.Errorf("merge sonucu %d kez teslim edildi ve işlenemedi", msg.Attempts)
+		} else {
+			// Uzun süren işleme (video vb.) sırasında mesaj başka bir replikaya tekrar teslim edilmez
+			stop := queue.KeepAlive(processedQueue, msg, heartbeat)
+			err = uploadService.HandleMergeSuccess(processed.UploadID, processed.Filename, processed.MergedFilePath, processed.TotalChunks, processed.SHA256)
+			stop()
+		}
+		if err != nil {
 			log.Printf("HandleMergeSuccess error: %v", err)
 			fmt.Printf("Total chunk sayısı: %d\n", processed.TotalChunks)
+			// Geçici hatalarda (DB vb.) mesaj artan gecikmeyle tekrar denenir
+			if msg.Attempts < consts.MaxRetryJobs {
+				queue.Track(jobRepo, processed.JobID, consts.JobStatusRetrying, err.Error())
+				if err := processedQueue.Nack(ctx, msg, time.Duration(msg.Attempts)*10*time.Second); err != nil {
+					log.Println("Nack failed:", err)
+				}
+				continue
+			}
+			queue.Track(jobRepo, processed.JobID, consts.JobStatusFailed, err.Error())
+			if failErr := uploadService.FailUpload(processed.UploadID, processed.JobID, processed.Filename, err.Error()); failErr != nil {
+				log.Printf("Upload failed olarak işaretlenemedi %s: %v", processed.UploadID, failErr)
+			}
+			events.Publish(publisher, events.Event{Type: events.Failed, UploadID: processed.UploadID, JobID: processed.JobID, Filename: processed.Filename, Error: err.Error()})
 		} else {
 			log.Printf("HandleMergeSuccess executed: %s", processed.Filename)
 			fmt.Printf("Total chunk sayısı: %d\n", processed.TotalChunks)
+			queue.Track(jobRepo, processed.JobID, consts.JobStatusSucceeded, "")
+		}
+
+		// Server HandleMergeSuccess'i bitirmeden çökerse mesaj onaylanmadığı için başka bir replika devralır
+		if err := processedQueue.Ack(ctx, msg); err != nil {
+			log.Println("Ack failed:", err)
 		}
 	}
 }
@@ -0,0 +1,209 @@
+package main //storagectl
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"file-uploader/internal/domain/repositories"
+	"file-uploader/internal/infrastructure/db"
+	infra_repo "file-uploader/internal/infrastructure/repositories"
+	"file-uploader/internal/infrastructure/storage"
+	"file-uploader/internal/usecases"
+	"file-uploader/pkg/config"
+
+	"github.com/joho/godotenv"
+)
+
+const usage = `Kullanım:
+  storagectl migrate -from local -to s3 [-dry-run] [-concurrency 4] [-retry-failed] [-delete-source]
+  storagectl status -id local-to-s3
+  storagectl rotate-keys [-batch 500]`
+
+func main() {
+	if err := godotenv.Load("../../.env"); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+	cfg := config.LoadConfig()
+
+	if len(os.Args) < 2 {
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+	switch os.Args[1] {
+	case "migrate":
+		migrate(cfg, os.Args[2:])
+	case "status":
+		status(os.Args[2:])
+	case "rotate-keys":
+		rotateKeys(cfg, os.Args[2:])
+	default:
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+}
+
+func migrate(cfg *config.Config, args []string) {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	from := fs.String("from", storage.BackendLocal, "kaynak backend (local|s3)")
+	to := fs.String("to", storage.BackendS3, "hedef backend (local|s3)")
+	fromDir := fs.String("from-dir", cfg.Upload.UploadsDir, "local kaynak klasörü")
+	toDir := fs.String("to-dir", cfg.Upload.UploadsDir, "local hedef klasörü")
+	fromBucket := fs.String("from-bucket", cfg.Storage.S3Bucket, "s3 kaynak bucket'ı")
+	toBucket := fs.String("to-bucket", cfg.Storage.S3Bucket, "s3 hedef bucket'ı")
+	id := fs.String("id", "", "taşıma ID'si, aynı ID ile tekrar çalıştırılırsa kaldığı yerden devam eder (varsayılan <from>-to-<to>)")
+	concurrency := fs.Int("concurrency", 4, "aynı anda taşınacak dosya sayısı")
+	dryRun := fs.Bool("dry-run", false, "dosya kopyalamadan ve DB'ye yazmadan yapılacakları listeler")
+	retryFailed := fs.Bool("retry-failed", false, "başarısız olan dosyaları tekrar dener")
+	deleteSource := fs.Bool("delete-source", false, "doğrulanan dosyaları kaynaktan siler")
+	fs.Parse(args)
+
+	database, err := db.NewPostgresDB()
+	if err != nil {
+		log.Fatal("DB bağlantısı kurulamadı:", err)
+	}
+	objects := infra_repo.NewEncryptedObjectRepository(database)
+
+	source, sourceName, sourceNamespace := openStorage(cfg, objects, *from, *fromDir, *fromBucket)
+	destination, destinationName, destinationNamespace := openStorage(cfg, objects, *to, *toDir, *toBucket)
+	if sourceName == destinationName {
+		log.Fatalf("Kaynak ve hedef aynı storage: %s", sourceName)
+	}
+	// Şifreleme anahtarları backend başına tutulur; iki local klasör aynı kayıtları paylaşırdı
+	if sourceNamespace == destinationNamespace && isEncrypted(source) {
+		log.Fatalf("Şifreleme açıkken %s backend'i içinde taşıma desteklenmiyor", sourceNamespace)
+	}
+	if *id == "" {
+		*id = fmt.Sprintf("%s-to-%s", *from, *to)
+	}
+
+	sourceBase := ""
+	if *from == storage.BackendLocal {
+		sourceBase, _ = filepath.Abs(*fromDir)
+	}
+	migrator := usecases.NewStorageMigrator(infra_repo.NewStorageMigrationRepository(database), source, destination, usecases.StorageMigrationOptions{
+		ID:           *id,
+		Source:       sourceName,
+		Destination:  destinationName,
+		SourceBase:   sourceBase,
+		Concurrency:  *concurrency,
+		DryRun:       *dryRun,
+		RetryFailed:  *retryFailed,
+		DeleteSource: *deleteSource,
+	})
+
+	// Ctrl+C ile yeni dosya başlatılmaz, devam eden kopyalar tamamlanır
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	log.Printf("Taşıma %s: %s -> %s", *id, sourceName, destinationName)
+	report, err := migrator.Run(ctx)
+	if err != nil {
+		log.Fatalf("Taşıma başarısız: %v", err)
+	}
+
+	for _, step := range report.Plan {
+		if step.Error != "" {
+			fmt.Printf("HATA  %s: %s\n", step.SourcePath, step.Error)
+		} else {
+			fmt.Printf("%s -> %s (%d byte)\n", step.SourcePath, step.DestinationPath, step.Size)
+		}
+	}
+	if *dryRun {
+		fmt.Printf("dry-run: %d dosya, %d byte, %d dosya kaynakta bulunamadı\n", report.Total, report.Bytes, report.Failed)
+		return
+	}
+	fmt.Printf("%d/%d dosya taşındı (%d byte), %d başarısız\n", report.Migrated, report.Total, report.Bytes, report.Failed)
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func status(args []string) {
+	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	id := fs.String("id", "local-to-s3", "taşıma ID'si")
+	fs.Parse(args)
+
+	database, err := db.NewPostgresDB()
+	if err != nil {
+		log.Fatal("DB bağlantısı kurulamadı:", err)
+	}
+	repo := infra_repo.NewStorageMigrationRepository(database)
+	migration, err := repo.GetMigration(*id)
+	if err != nil {
+		log.Fatalf("Taşıma bulunamadı: %v", err)
+	}
+	summary, err := repo.Summary(*id)
+	if err != nil {
+		log.Fatalf("Taşıma durumu alınamadı: %v", err)
+	}
+	fmt.Printf("%s: %s -> %s [%s]\n", migration.ID, migration.Source, migration.Destination, migration.Status)
+	fmt.Printf("bekleyen: %d, taşınan: %d (%d byte), başarısız: %d\n", summary.Pending, summary.Done, summary.Bytes, summary.Failed)
+}
+
+func rotateKeys(cfg *config.Config, args []string) {
+	fs := flag.NewFlagSet("rotate-keys", flag.ExitOnError)
+	batch := fs.Int("batch", 500, "tek seferde okunan kayıt sayısı")
+	fs.Parse(args)
+
+	keyring, err := storage.NewKeyring(cfg.Encryption)
+	if err != nil {
+		log.Fatalf("Şifreleme anahtarları yüklenemedi: %v", err)
+	}
+	database, err := db.NewPostgresDB()
+	if err != nil {
+		log.Fatal("DB bağlantısı kurulamadı:", err)
+	}
+
+	log.Printf("Veri anahtarları %s ile yeniden sarılıyor", keyring.ActiveKeyID())
+	report, err := usecases.NewKeyRotationService(infra_repo.NewEncryptedObjectRepository(database), keyring).Rotate(*batch)
+	if err != nil {
+		log.Fatalf("Rotasyon başarısız: %v", err)
+	}
+	fmt.Printf("%d anahtar yeniden sarıldı, %d açılamadı\n", report.Rotated, report.Failed)
+	for keyID, count := range report.Keys {
+		fmt.Printf("  %s: %d dosya\n", keyID, count)
+	}
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// Backend ayarları .env'den gelir, klasör/bucket flag'lerle değiştirilebilir.
+// Dönen isim taşıma kaydında tutulur; aynı ID farklı storage'larla devam ettirilemez.
+// Şifreleme anahtarları tanımlıysa her iki taraf da server gibi EncryptedStorage ile sarılır
+func openStorage(base *config.Config, objects repositories.EncryptedObjectRepository, backend, dir, bucket string) (repositories.StorageStrategy, string, string) {
+	cfg := base.Storage
+	cfg.Backend = backend
+	cfg.S3Bucket = bucket
+
+	name := fmt.Sprintf("%s:%s", backend, bucket)
+	if backend == storage.BackendLocal {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			log.Fatalf("Klasör çözümlenemedi %s: %v", dir, err)
+		}
+		dir = abs
+		name = fmt.Sprintf("%s:%s", backend, dir)
+	}
+
+	s, err := storage.New(cfg, dir)
+	if err != nil {
+		log.Fatalf("Storage oluşturulamadı (%s): %v", backend, err)
+	}
+	s, err = storage.WithEncryption(s, cfg, base.Encryption, objects)
+	if err != nil {
+		log.Fatalf("Storage şifrelemesi başlatılamadı: %v", err)
+	}
+	return s, name, storage.Namespace(cfg)
+}
+
+func isEncrypted(s repositories.StorageStrategy) bool {
+	_, ok := s.(*storage.EncryptedStorage)
+	return ok
+}
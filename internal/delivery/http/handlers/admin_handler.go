@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"file-uploader/internal/delivery/http/middleware"
+	"file-uploader/internal/domain/dto"
+	"file-uploader/internal/usecases"
+	fe "file-uploader/pkg/errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AdminHandler struct {
+	deadLetters usecases.DeadLetterService
+}
+
+func NewAdminHandler(deadLetters usecases.DeadLetterService) *AdminHandler {
+	return &AdminHandler{
+		deadLetters: deadLetters,
+	}
+}
+
+// ListFailedJobs
+//
+// @Summary      List Failed Jobs
+// @Description  Lists dead-lettered jobs from failed_jobs, newest first, filtered by job type, status and age
+// @Tags         Admin
+// @Produce      json
+// @Param        page        query     int    false "Page (1-based)"
+// @Param        limit       query     int    false "Page size (max 100)"
+// @Param        job_type    query     string false "Job type (e.g. merge_chunks)"
+// @Param        status      query     string false "Job status (failed, queued, uploaded)"
+// @Param        older_than  query     string false "Only jobs older than this duration (e.g. 24h)"
+// @Param        newer_than  query     string false "Only jobs newer than this duration (e.g. 1h)"
+// @Success      200         {object}  dto.FailedJobListResponse
+// @Failure      400         {object}  dto.ErrorResponse
+// @Router       /admin/jobs/failed [get]
+func (h *AdminHandler) ListFailedJobs(c *fiber.Ctx) error {
+	var req dto.FailedJobListRequestDTO
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	response, err := h.deadLetters.ListFailedJobs(&req)
+	if err != nil {
+		return adminError(c, err)
+	}
+	return c.JSON(response)
+}
+
+// GetFailedJob
+//
+// @Summary      Inspect Failed Job
+// @Description  Returns a dead-lettered job with its payload (JSON, or base64 if the payload is not JSON)
+// @Tags         Admin
+// @Produce      json
+// @Param        id   path      int true "Failed job ID"
+// @Success      200  {object}  dto.FailedJobDetailResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Router       /admin/jobs/failed/{id} [get]
+func (h *AdminHandler) GetFailedJob(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "Geçersiz id",
+		})
+	}
+
+	response, err := h.deadLetters.GetFailedJob(uint(id))
+	if err != nil {
+		return adminError(c, err)
+	}
+	return c.JSON(response)
+}
+
+// RequeueFailedJobs
+//
+// @Summary      Requeue Failed Jobs
+// @Description  Pushes the matching failed jobs back onto the job queue with a fresh retry budget; the action is recorded in the audit log
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        X-Admin-User  header    string                      false "Actor recorded in the audit log"
+// @Param        request       body      dto.FailedJobBulkRequestDTO true  "IDs and/or filters"
+// @Success      200           {object}  dto.FailedJobBulkResponse
+// @Failure      400           {object}  dto.ErrorResponse
+// @Router       /admin/jobs/failed/requeue [post]
+func (h *AdminHandler) RequeueFailedJobs(c *fiber.Ctx) error {
+	var req dto.FailedJobBulkRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+	req.Actor = adminActor(c)
+
+	response, err := h.deadLetters.RequeueFailedJobs(&req)
+	if err != nil {
+		return adminError(c, err)
+	}
+	return c.JSON(response)
+}
+
+// PurgeFailedJobs
+//
+// @Summary      Purge Failed Jobs
+// @Description  Deletes the matching failed jobs; the action is recorded in the audit log
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        X-Admin-User  header    string                      false "Actor recorded in the audit log"
+// @Param        request       body      dto.FailedJobBulkRequestDTO true  "IDs and/or filters"
+// @Success      200           {object}  dto.FailedJobBulkResponse
+// @Failure      400           {object}  dto.ErrorResponse
+// @Router       /admin/jobs/failed/purge [post]
+func (h *AdminHandler) PurgeFailedJobs(c *fiber.Ctx) error {
+	var req dto.FailedJobBulkRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+	req.Actor = adminActor(c)
+
+	response, err := h.deadLetters.PurgeFailedJobs(&req)
+	if err != nil {
+		return adminError(c, err)
+	}
+	return c.JSON(response)
+}
+
+// ListAuditLogs
+//
+// @Summary      List Admin Audit Log
+// @Description  Lists recorded admin actions, newest first
+// @Tags         Admin
+// @Produce      json
+// @Param        action  query     string false "Action (failed_jobs.requeue, failed_jobs.purge)"
+// @Param        page    query     int    false "Page (1-based)"
+// @Param        limit   query     int    false "Page size (max 100)"
+// @Success      200     {object}  dto.AuditLogListResponse
+// @Router       /admin/audit [get]
+func (h *AdminHandler) ListAuditLogs(c *fiber.Ctx) error {
+	response, err := h.deadLetters.ListAuditLogs(c.Query("action"), c.QueryInt("page"), c.QueryInt("limit"))
+	if err != nil {
+		return adminError(c, err)
+	}
+	return c.JSON(response)
+}
+
+// Kimliği doğrulanmış isteklerde audit log'a principal yazılır; X-Admin-User yalnızca auth kapalıyken dikkate alınır
+func adminActor(c *fiber.Ctx) string {
+	if principal := middleware.CurrentPrincipal(c); principal != nil && principal.Subject != "" {
+		return principal.Method + ":" + principal.Subject
+	}
+	if actor := c.Get("X-Admin-User"); actor != "" {
+		return actor
+	}
+	return c.IP()
+}
+
+func adminError(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+	var uploadErr *fe.UploadError
+	if errors.As(err, &uploadErr) {
+		switch uploadErr.Code {
+		case "not_found":
+			status = fiber.StatusNotFound
+		case "invalid_request":
+			status = fiber.StatusBadRequest
+		}
+	}
+	return c.Status(status).JSON(dto.ErrorResponse{
+		Error: err.Error(),
+	})
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"file-uploader/internal/delivery/http/middleware"
+	"file-uploader/internal/domain/dto"
+	"file-uploader/internal/usecases"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type APIKeyHandler struct {
+	apiKeys usecases.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeys usecases.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeys: apiKeys,
+	}
+}
+
+// CreateAPIKey
+//
+// @Summary      Create API Key
+// @Description  Issues an API key for a tenant. The key is only returned in this response; only its SHA-256 digest is stored. owner_id defaults to the caller's tenant
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.APIKeyCreateRequestDTO true "API key"
+// @Success      201      {object}  dto.APIKeyResponse
+// @Failure      400      {object}  dto.ErrorResponse
+// @Failure      403      {object}  dto.ErrorResponse
+// @Router       /admin/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *fiber.Ctx) error {
+	var req dto.APIKeyCreateRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+	if req.OwnerID == "" {
+		req.OwnerID = middleware.OwnerID(c)
+	}
+
+	response, err := h.apiKeys.CreateKey(&req)
+	if err != nil {
+		return adminError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// ListAPIKeys
+//
+// @Summary      List API Keys
+// @Description  Lists issued API keys (without the key itself), optionally filtered by tenant
+// @Tags         Admin
+// @Produce      json
+// @Param        owner_id  query     string false "Tenant"
+// @Success      200       {object}  dto.APIKeyListResponse
+// @Failure      403       {object}  dto.ErrorResponse
+// @Router       /admin/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(c *fiber.Ctx) error {
+	response, err := h.apiKeys.ListKeys(c.Query("owner_id"))
+	if err != nil {
+		return adminError(c, err)
+	}
+	return c.JSON(response)
+}
+
+// RevokeAPIKey
+//
+// @Summary      Revoke API Key
+// @Description  Revokes an API key; requests made with it are rejected with 401 from then on
+// @Tags         Admin
+// @Param        id   path  string true "API key ID"
+// @Success      204
+// @Failure      404  {object}  dto.ErrorResponse
+// @Router       /admin/api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c *fiber.Ctx) error {
+	if err := h.apiKeys.RevokeKey(c.Params("id")); err != nil {
+		return adminError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
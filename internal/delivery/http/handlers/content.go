@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"file-uploader/internal/domain/dto"
+	fe "file-uploader/pkg/errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Dosya stream'i gönderildikten sonra fasthttp tarafından kapatılır
+type contentReader struct {
+	io.Reader
+	io.Closer
+}
+
+// Range/If-Range, ETag/If-None-Match ve Last-Modified/If-Modified-Since desteğiyle dosyayı stream eder.
+// Tek aralıklı Range istekleri 206 ile yanıtlanır; çok aralıklı istekler yok sayılıp tüm dosya gönderilir.
+// disposition: inline (tarayıcıda gösterilir) | attachment (indirilir)
+func sendContent(c *fiber.Ctx, content *dto.MediaContent, cacheControl, disposition string) error {
+	c.Set(fiber.HeaderAcceptRanges, "bytes")
+	c.Set(fiber.HeaderETag, content.ETag)
+	if !content.ModTime.IsZero() {
+		c.Set(fiber.HeaderLastModified, content.ModTime.Format(http.TimeFormat))
+	}
+	if cacheControl != "" {
+		c.Set(fiber.HeaderCacheControl, cacheControl)
+	}
+
+	if notModified(c, content) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	c.Set(fiber.HeaderContentType, content.ContentType)
+	if content.Filename != "" {
+		c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType(disposition, map[string]string{"filename": content.Filename}))
+	}
+
+	start, length := int64(0), content.Size
+	if header := c.Get(fiber.HeaderRange); header != "" && rangeApplies(c, content) {
+		rangeStart, rangeLength, ok, satisfiable := parseRange(header, content.Size)
+		if !satisfiable {
+			c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes */%d", content.Size))
+			return c.SendStatus(fiber.StatusRequestedRangeNotSatisfiable)
+		}
+		if ok {
+			start, length = rangeStart, rangeLength
+			c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes %d-%d/%d", start, start+length-1, content.Size))
+			c.Status(fiber.StatusPartialContent)
+		}
+	}
+
+	// Dosya yalnızca gövde gönderilecekse açılır
+	file, err := content.Open()
+	if err != nil {
+		c.Response().Header.Del(fiber.HeaderContentRange)
+		c.Response().Header.Del(fiber.HeaderContentDisposition)
+		return fe.HandleError(c, err)
+	}
+	body := io.NewSectionReader(file, start, length)
+	return c.SendStream(contentReader{Reader: body, Closer: file}, int(length))
+}
+
+// If-None-Match verilmişse If-Modified-Since'e bakılmaz (RFC 9110 13.2.2)
+func notModified(c *fiber.Ctx, content *dto.MediaContent) bool {
+	if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+		return false
+	}
+	if header := c.Get(fiber.HeaderIfNoneMatch); header != "" {
+		for _, tag := range strings.Split(header, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == content.ETag {
+				return true
+			}
+		}
+		return false
+	}
+	if header := c.Get(fiber.HeaderIfModifiedSince); header != "" && !content.ModTime.IsZero() {
+		if since, err := http.ParseTime(header); err == nil {
+			return !content.ModTime.After(since)
+		}
+	}
+	return false
+}
+
+// If-Range eşleşmezse dosya değişmiş demektir, Range yok sayılıp tüm dosya gönderilir.
+// ETag karşılaştırması strong yapılır, weak ETag'ler eşleşmez
+func rangeApplies(c *fiber.Ctx, content *dto.MediaContent) bool {
+	header := c.Get(fiber.HeaderIfRange)
+	if header == "" {
+		return true
+	}
+	if strings.HasPrefix(header, `"`) || strings.HasPrefix(header, "W/") {
+		return header == content.ETag
+	}
+	at, err := time.Parse(http.TimeFormat, header)
+	return err == nil && !content.ModTime.IsZero() && content.ModTime.Equal(at)
+}
+
+// "bytes=a-b", "bytes=a-" ve "bytes=-n" biçimlerini çözer.
+// ok=false ise Range yok sayılır (geçersiz ya da çok aralıklı), satisfiable=false ise 416 dönülür
+func parseRange(header string, size int64) (start, length int64, ok, satisfiable bool) {
+	spec, found := strings.CutPrefix(header, "bytes=")
+	if !found || strings.Contains(spec, ",") {
+		return 0, 0, false, true
+	}
+	first, last, found := strings.Cut(strings.TrimSpace(spec), "-")
+	if !found {
+		return 0, 0, false, true
+	}
+
+	// Son n byte
+	if first == "" {
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil {
+			return 0, 0, false, true
+		}
+		if n <= 0 || size == 0 {
+			return 0, 0, false, false
+		}
+		n = min(n, size)
+		return size - n, n, true, true
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 {
+		return 0, 0, false, true
+	}
+	end := size - 1
+	if last != "" {
+		if end, err = strconv.ParseInt(last, 10, 64); err != nil || end < start {
+			return 0, 0, false, true
+		}
+		end = min(end, size-1)
+	}
+	if start >= size {
+		return 0, 0, false, false
+	}
+	return start, end - start + 1, true, true
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"file-uploader/internal/domain/dto"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		header          string
+		size            int64
+		start, length   int64
+		ok, satisfiable bool
+	}{
+		{"bytes=0-99", 1000, 0, 100, true, true},
+		{"bytes=100-", 1000, 100, 900, true, true},
+		{"bytes=990-2000", 1000, 990, 10, true, true},
+		{"bytes=-100", 1000, 900, 100, true, true},
+		{"bytes=-5000", 1000, 0, 1000, true, true},
+		{"bytes=999-999", 1000, 999, 1, true, true},
+		// 416
+		{"bytes=1000-", 1000, 0, 0, false, false},
+		{"bytes=5000-6000", 1000, 0, 0, false, false},
+		{"bytes=-0", 1000, 0, 0, false, false},
+		{"bytes=-10", 0, 0, 0, false, false},
+		{"bytes=0-", 0, 0, 0, false, false},
+		// Yok sayılır, tüm dosya gönderilir
+		{"bytes=0-10,20-30", 1000, 0, 0, false, true},
+		{"bytes=50-10", 1000, 0, 0, false, true},
+		{"bytes=abc-", 1000, 0, 0, false, true},
+		{"bytes=-abc", 1000, 0, 0, false, true},
+		{"bytes=10", 1000, 0, 0, false, true},
+		{"items=0-10", 1000, 0, 0, false, true},
+		{"bytes=-1-5", 1000, 0, 0, false, true},
+	}
+	for _, tt := range tests {
+		start, length, ok, satisfiable := parseRange(tt.header, tt.size)
+		if start != tt.start || length != tt.length || ok != tt.ok || satisfiable != tt.satisfiable {
+			t.Errorf("parseRange(%q, %d) = (%d, %d, %v, %v), beklenen (%d, %d, %v, %v)",
+				tt.header, tt.size, start, length, ok, satisfiable, tt.start, tt.length, tt.ok, tt.satisfiable)
+		}
+	}
+}
+
+func TestRangeApplies(t *testing.T) {
+	modTime := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	content := &dto.MediaContent{ETag: `"abc"`, ModTime: modTime}
+
+	tests := []struct {
+		name    string
+		ifRange string
+		content *dto.MediaContent
+		want    bool
+	}{
+		{"If-Range yok", "", content, true},
+		{"ETag eşleşiyor", `"abc"`, content, true},
+		{"ETag farklı", `"def"`, content, false},
+		{"weak ETag eşleşmez", `W/"abc"`, content, false},
+		{"tarih eşleşiyor", modTime.Format(http.TimeFormat), content, true},
+		{"tarih farklı", modTime.Add(time.Second).Format(http.TimeFormat), content, false},
+		{"geçersiz tarih", "dün", content, false},
+		{"değişiklik zamanı bilinmiyor", modTime.Format(http.TimeFormat), &dto.MediaContent{ETag: `"abc"`}, false},
+	}
+
+	app := fiber.New()
+	for _, tt := range tests {
+		c := app.AcquireCtx(&fasthttp.RequestCtx{})
+		if tt.ifRange != "" {
+			c.Request().Header.Set(fiber.HeaderIfRange, tt.ifRange)
+		}
+		if got := rangeApplies(c, tt.content); got != tt.want {
+			t.Errorf("%s: rangeApplies = %v, beklenen %v", tt.name, got, tt.want)
+		}
+		app.ReleaseCtx(c)
+	}
+}
+
+// 304 ve 416 yanıtları için dosya açılmamalı
+func TestSendContentAnswersWithoutOpening(t *testing.T) {
+	data := []byte("0123456789")
+	opened := 0
+	newContent := func() *dto.MediaContent {
+		return &dto.MediaContent{
+			Open: func() (multipart.File, error) {
+				opened++
+				return nopFile{bytes.NewReader(data)}, nil
+			},
+			Size:        int64(len(data)),
+			ModTime:     time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			ETag:        `"abc"`,
+			ContentType: "text/plain",
+		}
+	}
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		return sendContent(c, newContent(), "", "inline")
+	})
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		status  int
+		body    string // boşsa gövdeye bakılmaz
+		opens   int
+	}{
+		{"If-None-Match", map[string]string{fiber.HeaderIfNoneMatch: `"abc"`}, fiber.StatusNotModified, "", 0},
+		{"If-Modified-Since", map[string]string{fiber.HeaderIfModifiedSince: "Fri, 02 Jan 2026 03:04:05 GMT"}, fiber.StatusNotModified, "", 0},
+		{"karşılanamayan Range", map[string]string{fiber.HeaderRange: "bytes=50-"}, fiber.StatusRequestedRangeNotSatisfiable, "", 0},
+		{"Range", map[string]string{fiber.HeaderRange: "bytes=2-4"}, fiber.StatusPartialContent, "234", 1},
+		{"tüm dosya", nil, fiber.StatusOK, string(data), 1},
+	}
+	for _, tt := range tests {
+		opened = 0
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		for key, value := range tt.headers {
+			req.Header.Set(key, value)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != tt.status || (tt.body != "" && string(body) != tt.body) || opened != tt.opens {
+			t.Errorf("%s: status %d, gövde %q, %d kez açıldı; beklenen %d, %q, %d",
+				tt.name, resp.StatusCode, body, opened, tt.status, tt.body, tt.opens)
+		}
+	}
+}
+
+type nopFile struct {
+	*bytes.Reader
+}
+
+func (nopFile) Close() error { return nil }
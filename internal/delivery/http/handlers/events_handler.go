@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"file-uploader/internal/delivery/http/middleware"
+	"file-uploader/internal/domain/dto"
+	"file-uploader/internal/usecases"
+	fe "file-uploader/pkg/errors"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Proxy'lerin boşta kalan bağlantıyı kapatmaması için gönderilir
+const eventsHeartbeatInterval = 15 * time.Second
+
+type EventsHandler struct {
+	progress usecases.ProgressService
+}
+
+func NewEventsHandler(progress usecases.ProgressService) *EventsHandler {
+	return &EventsHandler{
+		progress: progress,
+	}
+}
+
+// UploadEvents
+//
+// @Summary      Upload Progress Events (SSE)
+// @Description  Streams chunk_received, merge_started, merge_finished, variant_created, completed and failed events for an upload as Server-Sent Events
+// @Tags         Upload
+// @Produce      text/event-stream
+// @Param        id   path      string true "Upload ID"
+// @Success      200  {string}  string "event stream"
+// @Failure      404  {object}  dto.ErrorResponse "Upload session not found"
+// @Router       /upload/{id}/events [get]
+func (h *EventsHandler) UploadEvents(c *fiber.Ctx) error {
+	uploadID := c.Params("id")
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	stream, unsubscribe, err := h.progress.Subscribe(ctx, middleware.OwnerID(c), uploadID)
+	if err != nil {
+		cancelCtx()
+		return subscribeError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no") // nginx tamponlamasın
+
+	// Stream writer handler döndükten sonra çalışır, fiber.Ctx burada kullanılmamalı
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancelCtx()
+		defer unsubscribe()
+
+		heartbeat := time.NewTicker(eventsHeartbeatInterval)
+		defer heartbeat.Stop()
+
+		fmt.Fprintf(w, "retry: 3000\n\n")
+		if err := w.Flush(); err != nil {
+			return
+		}
+		for {
+			select {
+			case event, ok := <-stream:
+				if !ok {
+					return
+				}
+				payload, err := json.Marshal(event)
+				if err != nil {
+					log.Printf("Olay serileştirilemedi: %v", err)
+					continue
+				}
+				fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, payload)
+			case <-heartbeat.C:
+				fmt.Fprintf(w, ": ping\n\n")
+			}
+			// Client bağlantıyı kapattıysa flush hata döner
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+	return nil
+}
+
+// WebSocket upgrade isteği değilse 426 döner
+func (h *EventsHandler) RequireUpgrade(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return c.Status(fiber.StatusUpgradeRequired).JSON(dto.ErrorResponse{
+			Error: "WebSocket upgrade gerekli",
+		})
+	}
+	return c.Next()
+}
+
+// UploadEventsWS, SSE ile aynı olayları WebSocket üzerinden JSON mesaj olarak gönderir
+func (h *EventsHandler) UploadEventsWS() fiber.Handler {
+	return websocket.New(func(conn *websocket.Conn) {
+		uploadID := conn.Params("id")
+		ownerID, _ := conn.Locals(middleware.OwnerLocal).(string)
+		ctx, cancelCtx := context.WithCancel(context.Background())
+		defer cancelCtx()
+
+		stream, unsubscribe, err := h.progress.Subscribe(ctx, ownerID, uploadID)
+		if err != nil {
+			conn.WriteJSON(dto.ErrorResponse{Error: err.Error()})
+			return
+		}
+		defer unsubscribe()
+
+		// Client'tan gelen mesajlar okunmaz, yalnızca bağlantının kapandığı anlaşılır
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		heartbeat := time.NewTicker(eventsHeartbeatInterval)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case <-closed:
+				return
+			case event, ok := <-stream:
+				if !ok {
+					return
+				}
+				if err := conn.WriteJSON(event); err != nil {
+					return
+				}
+			case <-heartbeat.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(5*time.Second)); err != nil {
+					return
+				}
+			}
+		}
+	})
+}
+
+func subscribeError(c *fiber.Ctx, err error) error {
+	var uploadErr *fe.UploadError
+	if errors.As(err, &uploadErr) && uploadErr.Code == "not_found" {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+		Error: err.Error(),
+	})
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"errors"
+
+	"file-uploader/internal/delivery/http/middleware"
+	"file-uploader/internal/domain/dto"
+	"file-uploader/internal/usecases"
+	fe "file-uploader/pkg/errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type JobHandler struct {
+	jobService usecases.JobService
+}
+
+func NewJobHandler(jobService usecases.JobService) *JobHandler {
+	return &JobHandler{
+		jobService: jobService,
+	}
+}
+
+// GetJob
+//
+// @Summary      Get Job
+// @Description  Returns the current state of a queued job (queued, running, retrying, succeeded, failed)
+// @Tags         Jobs
+// @Produce      json
+// @Param        id   path      string true "Job ID"
+// @Success      200  {object}  dto.JobResponse
+// @Failure      404  {object}  dto.ErrorResponse "Job not found"
+// @Router       /jobs/{id} [get]
+func (h *JobHandler) GetJob(c *fiber.Ctx) error {
+	response, err := h.jobService.GetJob(middleware.OwnerID(c), c.Params("id"))
+	if err != nil {
+		var uploadErr *fe.UploadError
+		if errors.As(err, &uploadErr) && uploadErr.Code == "not_found" {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error: err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+	return c.JSON(response)
+}
+
+// ListUploadJobs
+//
+// @Summary      List Upload Jobs
+// @Description  Returns the job history of an upload (chunk, merge, retry and cleanup jobs) in creation order
+// @Tags         Jobs
+// @Produce      json
+// @Param        id   path      string true "Upload ID"
+// @Success      200  {object}  dto.UploadJobsResponse
+// @Failure      404  {object}  dto.ErrorResponse "Upload session not found"
+// @Failure      500  {object}  dto.ErrorResponse
+// @Router       /upload/{id}/jobs [get]
+func (h *JobHandler) ListUploadJobs(c *fiber.Ctx) error {
+	response, err := h.jobService.ListUploadJobs(middleware.OwnerID(c), c.Params("id"))
+	if err != nil {
+		var uploadErr *fe.UploadError
+		if errors.As(err, &uploadErr) && uploadErr.Code == "not_found" {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error: err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+	return c.JSON(response)
+}
@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"file-uploader/internal/delivery/http/middleware"
 	"file-uploader/internal/domain/dto"
 	"file-uploader/internal/usecases"
+	"file-uploader/pkg/config"
+	"file-uploader/pkg/errors"
 	"fmt"
-	"os"
+	"mime/multipart"
+	"path/filepath"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
@@ -12,33 +16,29 @@ import (
 )
 
 type MediaHandler struct {
-	repo usecases.MediaService
+	repo         usecases.MediaService
+	cacheControl string
 }
 
-func NewMediaHandler(repo usecases.MediaService) *MediaHandler {
-	return &MediaHandler{repo: repo}
+func NewMediaHandler(repo usecases.MediaService, cfg config.ContentConfig) *MediaHandler {
+	return &MediaHandler{repo: repo, cacheControl: cfg.CacheControl}
 }
 
 func (h *MediaHandler) CreateMedia(c *fiber.Ctx) error {
 	var media dto.ImageDTO
 	id := uuid.New()
 	media.ID = id.String()
+	media.OwnerID = middleware.OwnerID(c)
 
 	file, err := c.FormFile("file")
-	media.OriginalName = file.Filename
-	media.Status = "processing"
-
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "file is required"})
 	}
+	media.OriginalName = file.Filename
+	media.Status = "processing"
 
-	savePath := fmt.Sprintf("./uploads/media/original/%s_%s", media.ID, file.Filename)
-
-	if err := os.MkdirAll("./uploads/media/original", os.ModePerm); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "belirtilen dosya yolu oluşturulamadı"})
-	}
-
-	if err := c.SaveFile(file, savePath); err != nil {
+	savePath, err := h.saveOriginal(file, media.ID)
+	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "dosya kaydedilemedi"})
 	}
 	media.FilePath = savePath
@@ -49,15 +49,28 @@ func (h *MediaHandler) CreateMedia(c *fiber.Ctx) error {
 	return c.JSON(media)
 }
 
+// Image ya da video id'si kabul edilir; kayıt varyantları ve iş geçmişiyle döner
 func (h *MediaHandler) GetMedia(c *fiber.Ctx) error {
 	id := c.Params("id")
-	media, err := h.repo.GetMediaByID(id)
+	media, err := h.repo.GetMediaDetail(middleware.OwnerID(c), id)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "media bulunamadı"})
+		if uploadErr, ok := err.(*errors.UploadError); ok && uploadErr.Code == "not_found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "media bulunamadı"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "media alınamadı"})
 	}
 	return c.JSON(media)
 }
 
+// Orijinal ya da ?variant= ile seçilen varyant dosyası Range desteğiyle stream edilir
+func (h *MediaHandler) GetMediaContent(c *fiber.Ctx) error {
+	content, err := h.repo.GetMediaContent(middleware.OwnerID(c), c.Params("id"), c.Query("variant"))
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+	return sendContent(c, content, h.cacheControl, "inline")
+}
+
 func (h *MediaHandler) UpdateMediaStatus(c *fiber.Ctx) error {
 	id := c.Params("id")
 	var status struct {
@@ -66,6 +79,9 @@ func (h *MediaHandler) UpdateMediaStatus(c *fiber.Ctx) error {
 	if err := c.BodyParser(&status); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request"})
 	}
+	if _, err := h.repo.GetMediaByID(middleware.OwnerID(c), id); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "media bulunamadı"})
+	}
 	if err := h.repo.UpdateMediaStatus(id, status.Status); err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "media bulunamadı"})
 	}
@@ -73,13 +89,59 @@ func (h *MediaHandler) UpdateMediaStatus(c *fiber.Ctx) error {
 }
 
 func (h *MediaHandler) GetAllMedia(c *fiber.Ctx) error {
-	media, err := h.repo.GetAllMedia()
+	media, err := h.repo.GetAllMedia(middleware.OwnerID(c))
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "media alınamadı"})
 	}
 	return c.JSON(media)
 }
 
+// Filtreli ve sıralı listeleme, sayfalar next_cursor ile gezilir (bkz. dto.MediaListRequestDTO)
+func (h *MediaHandler) ListMedia(c *fiber.Ctx) error {
+	var req dto.MediaListRequestDTO
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	ownerID, err := listOwner(c, req.OwnerID)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+	response, err := h.repo.ListMedia(ownerID, &req)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+	return c.JSON(response)
+}
+
+func (h *MediaHandler) ListVideos(c *fiber.Ctx) error {
+	var req dto.MediaListRequestDTO
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	ownerID, err := listOwner(c, req.OwnerID)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+	response, err := h.repo.ListVideos(ownerID, &req)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+	return c.JSON(response)
+}
+
+// Başka bir tenant'ın kayıtları yalnızca admin tarafından listelenebilir
+func listOwner(c *fiber.Ctx, requested string) (string, error) {
+	ownerID := middleware.OwnerID(c)
+	if requested == "" || requested == ownerID {
+		return ownerID, nil
+	}
+	principal := middleware.CurrentPrincipal(c)
+	if principal == nil || !principal.Admin {
+		return "", errors.ErrForbidden(fmt.Errorf("başka tenant'ın kayıtları için admin yetkisi gerekli"))
+	}
+	return requested, nil
+}
+
 func (h *MediaHandler) CreateSize(c *fiber.Ctx) error {
 	size := dto.MediaSize{
 		VariantType: c.Query("variant_type"),
@@ -107,13 +169,22 @@ func (h *MediaHandler) UpdateSize(c *fiber.Ctx) error {
 
 func (h *MediaHandler) GetVideoByID(c *fiber.Ctx) error {
 	id := c.Params("video_id")
-	video, err := h.repo.GetVideoByID(id)
+	video, err := h.repo.GetVideoByID(middleware.OwnerID(c), id)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "video bulunamadı"})
 	}
 	return c.JSON(video)
 }
 
+// ?variant=original (varsayılan) ya da resized; video oynatıcıların seek yapabilmesi için Range desteklenir
+func (h *MediaHandler) GetVideoContent(c *fiber.Ctx) error {
+	content, err := h.repo.GetVideoContent(middleware.OwnerID(c), c.Params("video_id"), c.Query("variant"))
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+	return sendContent(c, content, h.cacheControl, "inline")
+}
+
 func (h *MediaHandler) ResizeByWidth(c *fiber.Ctx) error {
 	id := c.Params("video_id")
 	var req struct {
@@ -122,7 +193,7 @@ func (h *MediaHandler) ResizeByWidth(c *fiber.Ctx) error {
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request"})
 	}
-	video, err := h.repo.GetVideoByID(id)
+	video, err := h.repo.GetVideoByID(middleware.OwnerID(c), id)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "video bulunamadı"})
 	}
@@ -141,7 +212,7 @@ func (h *MediaHandler) ResizeByHeight(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "height > 0 olmalı"})
 	}
 
-	video, err := h.repo.GetVideoByID(id)
+	video, err := h.repo.GetVideoByID(middleware.OwnerID(c), id)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "video bulunamadı"})
 	}
@@ -161,7 +232,7 @@ func (h *MediaHandler) ResizeVideo(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "width and height must be > 0"})
 	}
 
-	video, err := h.repo.GetVideoByID(id)
+	video, err := h.repo.GetVideoByID(middleware.OwnerID(c), id)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "video not found"})
 	}
@@ -182,17 +253,13 @@ func (h *MediaHandler) CreateVideo(c *fiber.Ctx) error {
 	var video dto.VideoDTO
 	videoID := uuid.New()
 	video.VideoID = videoID.String()
+	video.OwnerID = middleware.OwnerID(c)
 	video.OriginalName = fileHeader.Filename
 	video.Status = "processing"
 	video.FileType = fileHeader.Header.Get("Content-Type")
 
-	saveDir := "./uploads/videos/original"
-
-	if err := os.MkdirAll(saveDir, os.ModePerm); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to create upload directory"})
-	}
-	savePath := fmt.Sprintf("%s/%s_%s", saveDir, video.VideoID, fileHeader.Filename)
-	if err := c.SaveFile(fileHeader, savePath); err != nil {
+	savePath, err := h.saveOriginal(fileHeader, video.VideoID)
+	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to save video"})
 	}
 	video.FilePath = savePath
@@ -203,3 +270,74 @@ func (h *MediaHandler) CreateVideo(c *fiber.Ctx) error {
 
 	return c.Status(fiber.StatusCreated).JSON(video)
 }
+
+// Media varyantlarıyla birlikte çöp kutusuna taşınır; dosyalar TRASH_RETENTION sonunda,
+// aynı içeriği paylaşan kayıtlar varsa son referans silinene kadar korunur
+func (h *MediaHandler) DeleteMedia(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if err := h.repo.DeleteMedia(middleware.OwnerID(c), id); err != nil {
+		if uploadErr, ok := err.(*errors.UploadError); ok && uploadErr.Code == "not_found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "media bulunamadı"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "media silinemedi"})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *MediaHandler) DeleteVideo(c *fiber.Ctx) error {
+	id := c.Params("video_id")
+	if err := h.repo.DeleteVideo(middleware.OwnerID(c), id); err != nil {
+		if uploadErr, ok := err.(*errors.UploadError); ok && uploadErr.Code == "not_found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "video bulunamadı"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "video silinemedi"})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Çöp kutusundaki media'lar, en son silinen önce
+func (h *MediaHandler) GetDeletedMedia(c *fiber.Ctx) error {
+	media, err := h.repo.GetDeletedMedia(middleware.OwnerID(c))
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+	return c.JSON(media)
+}
+
+func (h *MediaHandler) RestoreMedia(c *fiber.Ctx) error {
+	if err := h.repo.RestoreMedia(middleware.OwnerID(c), c.Params("id")); err != nil {
+		if uploadErr, ok := err.(*errors.UploadError); ok && uploadErr.Code == "not_found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "çöp kutusunda media bulunamadı"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "media geri alınamadı"})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *MediaHandler) GetDeletedVideos(c *fiber.Ctx) error {
+	videos, err := h.repo.GetDeletedVideos(middleware.OwnerID(c))
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+	return c.JSON(videos)
+}
+
+func (h *MediaHandler) RestoreVideo(c *fiber.Ctx) error {
+	if err := h.repo.RestoreVideo(middleware.OwnerID(c), c.Params("video_id")); err != nil {
+		if uploadErr, ok := err.(*errors.UploadError); ok && uploadErr.Code == "not_found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "çöp kutusunda video bulunamadı"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "video geri alınamadı"})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Dosya "<id>_<ad>" adıyla storage'a yazılır (media/original ya da videos/original)
+func (h *MediaHandler) saveOriginal(fileHeader *multipart.FileHeader, id string) (string, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	return h.repo.StoreOriginal(file, fmt.Sprintf("%s_%s", id, filepath.Base(fileHeader.Filename)))
+}
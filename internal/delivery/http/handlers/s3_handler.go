@@ -0,0 +1,373 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"file-uploader/internal/domain/dto"
+	"file-uploader/internal/usecases"
+	"file-uploader/pkg/config"
+	fe "file-uploader/pkg/errors"
+	"file-uploader/pkg/sigv4"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// İmzadaki x-amz-date ile sunucu saati arasında izin verilen fark
+	s3MaxClockSkew = 15 * time.Minute
+	// Okunurken SigV4 ile doğrulanan (aws-chunked ise çözülen) gövde
+	s3BodyLocal = "s3Body"
+	// CompleteMultipartUpload XML gövdesi için üst sınır (10000 part rahatça sığar)
+	s3MaxXMLBodySize = 4 << 20
+	// İsteği imzalayan access key'in bağlı olduğu tenant; upload oturumlarının sahibi olarak kullanılır
+	s3OwnerLocal = "s3Owner"
+)
+
+type S3Handler struct {
+	s3Service usecases.S3GatewayService
+	cfg       config.S3GatewayConfig
+}
+
+func NewS3Handler(s3Service usecases.S3GatewayService, cfg config.S3GatewayConfig) *S3Handler {
+	return &S3Handler{
+		s3Service: s3Service,
+		cfg:       cfg,
+	}
+}
+
+// Authorization header'ındaki SigV4 imzası yapılandırılmış access key'lerle doğrulanır
+func (h *S3Handler) VerifySignature(c *fiber.Ctx) error {
+	auth, err := sigv4.ParseAuthorization(c.Get(fiber.HeaderAuthorization))
+	if err != nil {
+		return h.writeError(c, fiber.StatusForbidden, "AccessDenied", err.Error())
+	}
+	credential, ok := h.cfg.AccessKeys[auth.AccessKey]
+	if !ok {
+		return h.writeError(c, fiber.StatusForbidden, "InvalidAccessKeyId", "access key tanımlı değil")
+	}
+	if auth.Service != "s3" || auth.Region != h.cfg.Region {
+		return h.writeError(c, fiber.StatusBadRequest, "AuthorizationHeaderMalformed", "credential scope bölgesi veya servisi geçersiz")
+	}
+
+	amzDate := c.Get("X-Amz-Date")
+	signedAt, err := time.Parse(sigv4.TimeFormat, amzDate)
+	if err != nil || !strings.HasPrefix(amzDate, auth.Date) {
+		return h.writeError(c, fiber.StatusForbidden, "AccessDenied", "x-amz-date geçersiz")
+	}
+	if skew := time.Since(signedAt); skew > s3MaxClockSkew || skew < -s3MaxClockSkew {
+		return h.writeError(c, fiber.StatusForbidden, "RequestTimeTooSkewed", "istek zamanı sunucu saatinden çok farklı")
+	}
+
+	payloadHash := c.Get("X-Amz-Content-Sha256")
+	if payloadHash == "" {
+		return h.writeError(c, fiber.StatusBadRequest, "InvalidRequest", "x-amz-content-sha256 zorunlu")
+	}
+
+	// İmza, istemcinin gönderdiği (normalize edilmemiş) path ve query üzerinden hesaplanır
+	uri := c.Request().URI()
+	canonical := sigv4.CanonicalRequest(c.Method(), string(uri.PathOriginal()), string(uri.QueryString()), func(name string) string {
+		if name == "host" {
+			return string(c.Request().Host())
+		}
+		return c.Get(name)
+	}, auth.SignedHeaders, payloadHash)
+
+	signingKey := sigv4.SigningKey(credential.SecretKey, auth.Date, auth.Region, auth.Service)
+	signature := sigv4.Sign(signingKey, sigv4.StringToSign(amzDate, auth.Scope(), canonical))
+	if !hmac.Equal([]byte(signature), []byte(auth.Signature)) {
+		return h.writeError(c, fiber.StatusForbidden, "SignatureDoesNotMatch", "istek imzası doğrulanamadı")
+	}
+
+	// Gövde burada okunmaz; doğrulama okuma sırasında yapılır ve hata son Read'de döner
+	body := requestBodyStream(c)
+	switch payloadHash {
+	case sigv4.UnsignedPayload:
+	case sigv4.StreamingTrailer:
+		body = sigv4.NewChunkedReader(body, nil)
+	case sigv4.StreamingSigned:
+		// Her chunk imzası bir önceki chunk'ın (ilki için header'daki) imzasına zincirlenir
+		previous := signature
+		body = sigv4.NewChunkedReader(body, func(chunkHash, chunkSignature string) error {
+			expected := sigv4.Sign(signingKey, sigv4.ChunkStringToSign(amzDate, auth.Scope(), previous, chunkHash))
+			if !hmac.Equal([]byte(expected), []byte(chunkSignature)) {
+				return sigv4.ErrSignatureMismatch
+			}
+			previous = chunkSignature
+			return nil
+		})
+	default:
+		if strings.HasPrefix(payloadHash, "STREAMING-") {
+			return h.writeError(c, fiber.StatusNotImplemented, "NotImplemented", "desteklenmeyen payload tipi: "+payloadHash)
+		}
+		body = sigv4.NewPayloadReader(body, payloadHash)
+	}
+
+	if c.Params("bucket") != h.cfg.Bucket {
+		return h.writeError(c, fiber.StatusNotFound, "NoSuchBucket", "bucket bulunamadı: "+c.Params("bucket"))
+	}
+
+	c.Locals(s3BodyLocal, &s3Body{r: body})
+	c.Locals(s3OwnerLocal, credential.OwnerID)
+	return c.Next()
+}
+
+// Post
+//
+// @Summary      S3 CreateMultipartUpload / CompleteMultipartUpload
+// @Description  POST ?uploads starts a multipart upload, POST ?uploadId=... completes it and queues the merge
+// @Tags         S3
+// @Produce      xml
+// @Param        bucket    path   string true  "Bucket"
+// @Param        key       path   string true  "Object key"
+// @Param        uploadId  query  string false "Upload ID"
+// @Success      200
+// @Failure      400
+// @Failure      403
+// @Router       /s3/{bucket}/{key} [post]
+func (h *S3Handler) Post(c *fiber.Ctx) error {
+	key, err := objectKey(c)
+	if err != nil {
+		return h.writeError(c, fiber.StatusBadRequest, "InvalidArgument", err.Error())
+	}
+
+	if c.Context().QueryArgs().Has("uploads") {
+		uploadID, err := h.s3Service.CreateMultipartUpload(s3Owner(c), key, c.Get(fiber.HeaderContentType))
+		if err != nil {
+			return h.handleError(c, err)
+		}
+		return writeXML(c, dto.InitiateMultipartUploadResult{
+			Xmlns:    dto.S3XMLNamespace,
+			Bucket:   h.cfg.Bucket,
+			Key:      key,
+			UploadID: uploadID,
+		})
+	}
+
+	uploadID := c.Query("uploadId")
+	if uploadID == "" {
+		return h.writeError(c, fiber.StatusNotImplemented, "NotImplemented", "yalnızca multipart upload işlemleri destekleniyor")
+	}
+
+	// Gövde sonuna kadar okunur, imza/hash doğrulaması ancak böyle tamamlanır
+	body := s3RequestBody(c)
+	data, err := io.ReadAll(io.LimitReader(body, s3MaxXMLBodySize+1))
+	if err != nil {
+		return h.writeBodyError(c, err)
+	}
+	if len(data) > s3MaxXMLBodySize {
+		return h.writeError(c, fiber.StatusBadRequest, "MaxMessageLengthExceeded", "istek gövdesi çok büyük")
+	}
+	var req dto.CompleteMultipartUpload
+	if err := xml.Unmarshal(data, &req); err != nil {
+		return h.writeError(c, fiber.StatusBadRequest, "MalformedXML", err.Error())
+	}
+	etag, err := h.s3Service.CompleteMultipartUpload(s3Owner(c), key, uploadID, req.Parts)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+	return writeXML(c, dto.CompleteMultipartUploadResult{
+		Xmlns:    dto.S3XMLNamespace,
+		Location: c.BaseURL() + c.Path(),
+		Bucket:   h.cfg.Bucket,
+		Key:      key,
+		ETag:     etag,
+	})
+}
+
+// UploadPart
+//
+// @Summary      S3 UploadPart
+// @Description  Stores the request body as part partNumber of the multipart upload and returns its ETag
+// @Tags         S3
+// @Param        bucket      path   string true "Bucket"
+// @Param        key         path   string true "Object key"
+// @Param        uploadId    query  string true "Upload ID"
+// @Param        partNumber  query  int    true "Part number (1-10000)"
+// @Success      200
+// @Failure      400
+// @Failure      404
+// @Router       /s3/{bucket}/{key} [put]
+func (h *S3Handler) UploadPart(c *fiber.Ctx) error {
+	key, err := objectKey(c)
+	if err != nil {
+		return h.writeError(c, fiber.StatusBadRequest, "InvalidArgument", err.Error())
+	}
+	uploadID := c.Query("uploadId")
+	if uploadID == "" || c.Query("partNumber") == "" {
+		return h.writeError(c, fiber.StatusNotImplemented, "NotImplemented", "yalnızca UploadPart destekleniyor")
+	}
+	partNumber, err := strconv.Atoi(c.Query("partNumber"))
+	if err != nil {
+		return h.writeError(c, fiber.StatusBadRequest, "InvalidArgument", "partNumber geçersiz")
+	}
+
+	// Part boyutu gövde okunmadan kontrol edilir; aws-chunked gövdede asıl boyut x-amz-decoded-content-length'tir
+	length := int64(c.Request().Header.ContentLength())
+	if decoded := c.Get("X-Amz-Decoded-Content-Length"); decoded != "" {
+		if length, err = strconv.ParseInt(decoded, 10, 64); err != nil {
+			return h.writeError(c, fiber.StatusBadRequest, "InvalidArgument", "x-amz-decoded-content-length geçersiz")
+		}
+	}
+
+	body := s3RequestBody(c)
+	etag, err := h.s3Service.UploadPart(s3Owner(c), key, uploadID, partNumber, length, body)
+	if err != nil {
+		if body.err != nil {
+			return h.writeBodyError(c, body.err)
+		}
+		return h.handleError(c, err)
+	}
+	c.Set(fiber.HeaderETag, etag)
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// AbortMultipartUpload
+//
+// @Summary      S3 AbortMultipartUpload
+// @Description  Cancels the multipart upload and removes its staged parts
+// @Tags         S3
+// @Param        bucket    path   string true "Bucket"
+// @Param        key       path   string true "Object key"
+// @Param        uploadId  query  string true "Upload ID"
+// @Success      204
+// @Failure      404
+// @Router       /s3/{bucket}/{key} [delete]
+func (h *S3Handler) AbortMultipartUpload(c *fiber.Ctx) error {
+	key, err := objectKey(c)
+	if err != nil {
+		return h.writeError(c, fiber.StatusBadRequest, "InvalidArgument", err.Error())
+	}
+	uploadID := c.Query("uploadId")
+	if uploadID == "" {
+		return h.writeError(c, fiber.StatusNotImplemented, "NotImplemented", "yalnızca AbortMultipartUpload destekleniyor")
+	}
+
+	if err := h.s3Service.AbortMultipartUpload(s3Owner(c), key, uploadID); err != nil {
+		return h.handleError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListParts
+//
+// @Summary      S3 ListParts
+// @Description  Lists the parts received so far for a multipart upload
+// @Tags         S3
+// @Produce      xml
+// @Param        bucket    path   string true "Bucket"
+// @Param        key       path   string true "Object key"
+// @Param        uploadId  query  string true "Upload ID"
+// @Success      200
+// @Failure      404
+// @Router       /s3/{bucket}/{key} [get]
+func (h *S3Handler) ListParts(c *fiber.Ctx) error {
+	key, err := objectKey(c)
+	if err != nil {
+		return h.writeError(c, fiber.StatusBadRequest, "InvalidArgument", err.Error())
+	}
+	uploadID := c.Query("uploadId")
+	if uploadID == "" {
+		return h.writeError(c, fiber.StatusNotImplemented, "NotImplemented", "yalnızca ListParts destekleniyor")
+	}
+
+	parts, err := h.s3Service.ListParts(s3Owner(c), key, uploadID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+	return writeXML(c, dto.ListPartsResult{
+		Xmlns:    dto.S3XMLNamespace,
+		Bucket:   h.cfg.Bucket,
+		Key:      key,
+		UploadID: uploadID,
+		Parts:    parts,
+	})
+}
+
+// UploadError kodları S3 hata kodlarına çevrilir
+func (h *S3Handler) handleError(c *fiber.Ctx, err error) error {
+	var uploadErr *fe.UploadError
+	if errors.As(err, &uploadErr) {
+		switch uploadErr.Code {
+		case "not_found", "upload_expired", "upload_not_active":
+			return h.writeError(c, fiber.StatusNotFound, "NoSuchUpload", err.Error())
+		case "invalid_part":
+			return h.writeError(c, fiber.StatusBadRequest, "InvalidPart", err.Error())
+		case "invalid_part_order":
+			return h.writeError(c, fiber.StatusBadRequest, "InvalidPartOrder", err.Error())
+		case "upload_too_large":
+			return h.writeError(c, fiber.StatusBadRequest, "EntityTooLarge", err.Error())
+		case "quota_exceeded":
+			return h.writeError(c, fiber.StatusForbidden, "QuotaExceeded", err.Error())
+		case "invalid_request", "invalid_chunk":
+			return h.writeError(c, fiber.StatusBadRequest, "InvalidArgument", err.Error())
+		}
+	}
+	return h.writeError(c, fiber.StatusInternalServerError, "InternalError", err.Error())
+}
+
+// Gövde okunurken oluşan imza/hash/format hataları S3 hata kodlarıyla döner
+func (h *S3Handler) writeBodyError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, sigv4.ErrSignatureMismatch):
+		return h.writeError(c, fiber.StatusForbidden, "SignatureDoesNotMatch", err.Error())
+	case errors.Is(err, sigv4.ErrPayloadHashMismatch):
+		return h.writeError(c, fiber.StatusBadRequest, "XAmzContentSHA256Mismatch", err.Error())
+	}
+	return h.writeError(c, fiber.StatusBadRequest, "IncompleteBody", err.Error())
+}
+
+func (h *S3Handler) writeError(c *fiber.Ctx, status int, code, message string) error {
+	c.Status(status)
+	return writeXML(c, dto.S3Error{
+		Code:     code,
+		Message:  message,
+		Resource: c.Path(),
+	})
+}
+
+func writeXML(c *fiber.Ctx, v interface{}) error {
+	body, err := xml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationXML)
+	return c.Send(append([]byte(xml.Header), body...))
+}
+
+func objectKey(c *fiber.Ctx) (string, error) {
+	key, err := url.PathUnescape(c.Params("*"))
+	if err != nil || key == "" {
+		return "", errors.New("object key geçersiz")
+	}
+	return key, nil
+}
+
+// Okuma sırasında oluşan ilk hata saklanır; servis bunu staging hatası olarak sarmalasa da handler S3 koduna çevirebilir
+type s3Body struct {
+	r   io.Reader
+	err error
+}
+
+func (b *s3Body) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	if err != nil && err != io.EOF && b.err == nil {
+		b.err = err
+	}
+	return n, err
+}
+
+func s3RequestBody(c *fiber.Ctx) *s3Body {
+	return c.Locals(s3BodyLocal).(*s3Body)
+}
+
+func s3Owner(c *fiber.Ctx) string {
+	owner, _ := c.Locals(s3OwnerLocal).(string)
+	return owner
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"file-uploader/internal/delivery/http/middleware"
+	"file-uploader/internal/domain/dto"
+	"file-uploader/internal/usecases"
+	"file-uploader/pkg/config"
+	fe "file-uploader/pkg/errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SignedURLHandler struct {
+	signedURLs   usecases.SignedURLService
+	cacheControl string
+}
+
+func NewSignedURLHandler(signedURLs usecases.SignedURLService, cfg config.ContentConfig) *SignedURLHandler {
+	return &SignedURLHandler{
+		signedURLs:   signedURLs,
+		cacheControl: cfg.CacheControl,
+	}
+}
+
+// CreateSignedURL
+//
+// @Summary      Create Signed URL
+// @Description  Issues an expiring HMAC-signed URL for a chunk upload (operation=upload, bound to upload_id, max_size and content_type) or a file download (operation=download). The URL works without an API key or token, only for the returned method
+// @Tags         Signed URLs
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.SignedURLRequestDTO true "Signed URL request"
+// @Success      201      {object}  dto.SignedURLResponse
+// @Failure      400      {object}  dto.ErrorResponse
+// @Failure      404      {object}  dto.ErrorResponse "Upload session or media not found"
+// @Failure      409      {object}  dto.ErrorResponse "Upload is not active"
+// @Router       /signed-urls [post]
+func (h *SignedURLHandler) CreateSignedURL(c *fiber.Ctx) error {
+	var req dto.SignedURLRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	response, err := h.signedURLs.CreateSignedURL(middleware.OwnerID(c), &req)
+	if err != nil {
+		return fe.HandleError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// Download
+//
+// @Summary      Download File
+// @Description  Downloads the original file (or ?variant=) of an image or video with Range support. Accepts the usual credentials or a signed URL issued by /signed-urls
+// @Tags         Signed URLs
+// @Produce      octet-stream
+// @Param        id       path      string true  "Media or video ID"
+// @Param        variant  query     string false "Variant (default: original)"
+// @Success      200      {file}    binary
+// @Success      206      {file}    binary
+// @Failure      401      {object}  dto.ErrorResponse "Invalid or expired signature"
+// @Failure      404      {object}  dto.ErrorResponse
+// @Router       /download/{id} [get]
+func (h *SignedURLHandler) Download(c *fiber.Ctx) error {
+	content, err := h.signedURLs.OpenDownload(middleware.OwnerID(c), c.Params("id"), c.Query("variant"))
+	if err != nil {
+		return fe.HandleError(c, err)
+	}
+
+	// Range ve koşullu istekler content endpoint'leriyle aynıdır, yalnızca indirme olarak sunulur
+	return sendContent(c, content, h.cacheControl, "attachment")
+}
@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"file-uploader/internal/delivery/http/middleware"
+	"file-uploader/internal/domain/dto"
+	"file-uploader/internal/usecases"
+	fe "file-uploader/pkg/errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	TusVersion    = "1.0.0"
+	TusExtensions = "creation,expiration,checksum,termination"
+	// tus checksum extension'ında mismatch için tanımlanan status
+	StatusChecksumMismatch = 460
+)
+
+type TusHandler struct {
+	tusService usecases.TusService
+	basePath   string
+}
+
+func NewTusHandler(tusService usecases.TusService, basePath string) *TusHandler {
+	return &TusHandler{
+		tusService: tusService,
+		basePath:   strings.TrimRight(basePath, "/"),
+	}
+}
+
+// Options (tus discovery) dışındaki tüm istekler Tus-Resumable header'ı taşımalı
+func (h *TusHandler) RequireTusResumable(c *fiber.Ctx) error {
+	c.Set("Tus-Resumable", TusVersion)
+	if c.Method() == fiber.MethodOptions {
+		return c.Next()
+	}
+	if c.Get("Tus-Resumable") != TusVersion {
+		c.Set("Tus-Version", TusVersion)
+		return c.SendStatus(fiber.StatusPreconditionFailed)
+	}
+	return c.Next()
+}
+
+// Options
+//
+// @Summary      tus discovery
+// @Description  Returns supported tus version, extensions, max size and checksum algorithms
+// @Tags         Tus
+// @Success      204
+// @Router       /tus [options]
+func (h *TusHandler) Options(c *fiber.Ctx) error {
+	c.Set("Tus-Version", TusVersion)
+	c.Set("Tus-Extension", TusExtensions)
+	c.Set("Tus-Max-Size", strconv.FormatInt(h.tusService.MaxSize(), 10))
+	c.Set("Tus-Checksum-Algorithm", strings.Join(usecases.TusChecksumAlgorithms, ","))
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// CreateUpload
+//
+// @Summary      tus creation
+// @Description  Creates a new tus upload from Upload-Length and Upload-Metadata (filename, filetype)
+// @Tags         Tus
+// @Param        Tus-Resumable    header  string true  "1.0.0"
+// @Param        Upload-Length    header  int    true  "Total upload size in bytes"
+// @Param        Upload-Metadata  header  string false "Comma separated key base64(value) pairs"
+// @Success      201
+// @Failure      400
+// @Failure      413
+// @Router       /tus [post]
+func (h *TusHandler) CreateUpload(c *fiber.Ctx) error {
+	if c.Get("Upload-Defer-Length") != "" {
+		return c.Status(fiber.StatusBadRequest).SendString("Upload-Defer-Length desteklenmiyor")
+	}
+	length, err := strconv.ParseInt(c.Get("Upload-Length"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Upload-Length geçersiz")
+	}
+
+	metadata, err := parseTusMetadata(c.Get("Upload-Metadata"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+	filename := metadata["filename"]
+	if filename == "" {
+		filename = metadata["name"]
+	}
+	mimeType := metadata["filetype"]
+	if mimeType == "" {
+		mimeType = metadata["type"]
+	}
+
+	info, err := h.tusService.CreateUpload(middleware.OwnerID(c), filename, mimeType, length)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	c.Set("Location", c.BaseURL()+h.basePath+"/"+info.UploadID)
+	h.setUploadHeaders(c, info)
+	return c.SendStatus(fiber.StatusCreated)
+}
+
+// GetOffset
+//
+// @Summary      tus offset discovery
+// @Description  Returns the current Upload-Offset and Upload-Length of a tus upload
+// @Tags         Tus
+// @Param        Tus-Resumable  header  string true "1.0.0"
+// @Param        id             path    string true "Upload ID"
+// @Success      200
+// @Failure      404
+// @Failure      410
+// @Router       /tus/{id} [head]
+func (h *TusHandler) GetOffset(c *fiber.Ctx) error {
+	info, err := h.tusService.GetUpload(middleware.OwnerID(c), c.Params("id"))
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	c.Set("Cache-Control", "no-store")
+	c.Set("Upload-Metadata", encodeTusMetadata(info))
+	h.setUploadHeaders(c, info)
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// AppendChunk
+//
+// @Summary      tus PATCH
+// @Description  Appends the request body to the upload at Upload-Offset, optionally verifying Upload-Checksum
+// @Tags         Tus
+// @Accept       application/offset+octet-stream
+// @Param        Tus-Resumable    header  string true  "1.0.0"
+// @Param        Upload-Offset    header  int    true  "Current offset"
+// @Param        Upload-Checksum  header  string false "<algorithm> <base64 digest>"
+// @Param        id               path    string true  "Upload ID"
+// @Success      204
+// @Failure      409
+// @Failure      413
+// @Failure      415
+// @Failure      460
+// @Router       /tus/{id} [patch]
+func (h *TusHandler) AppendChunk(c *fiber.Ctx) error {
+	if c.Get(fiber.HeaderContentType) != "application/offset+octet-stream" {
+		return c.Status(fiber.StatusUnsupportedMediaType).SendString("Content-Type application/offset+octet-stream olmalı")
+	}
+	offset, err := strconv.ParseInt(c.Get("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		return c.Status(fiber.StatusBadRequest).SendString("Upload-Offset geçersiz")
+	}
+
+	var algorithm, checksum string
+	if header := c.Get("Upload-Checksum"); header != "" {
+		parts := strings.SplitN(header, " ", 2)
+		if len(parts) != 2 {
+			return c.Status(fiber.StatusBadRequest).SendString("Upload-Checksum geçersiz")
+		}
+		algorithm, checksum = strings.ToLower(parts[0]), parts[1]
+	}
+
+	// Gövde c.Body() ile belleğe alınmaz; Content-Length servis tarafından okumadan önce kontrol edilir
+	info, err := h.tusService.AppendChunk(middleware.OwnerID(c), c.Params("id"), offset, int64(c.Request().Header.ContentLength()), requestBodyStream(c), algorithm, checksum)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	h.setUploadHeaders(c, info)
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// TerminateUpload
+//
+// @Summary      tus termination
+// @Description  Terminates a tus upload and removes its staged data
+// @Tags         Tus
+// @Param        Tus-Resumable  header  string true "1.0.0"
+// @Param        id             path    string true "Upload ID"
+// @Success      204
+// @Failure      404
+// @Router       /tus/{id} [delete]
+func (h *TusHandler) TerminateUpload(c *fiber.Ctx) error {
+	if err := h.tusService.TerminateUpload(middleware.OwnerID(c), c.Params("id")); err != nil {
+		return h.handleError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *TusHandler) setUploadHeaders(c *fiber.Ctx, info *dto.TusUploadInfo) {
+	c.Set("Upload-Offset", strconv.FormatInt(info.Offset, 10))
+	c.Set("Upload-Length", strconv.FormatInt(info.Length, 10))
+	if !info.ExpiresAt.IsZero() {
+		c.Set("Upload-Expires", info.ExpiresAt.UTC().Format(http.TimeFormat))
+	}
+}
+
+// UploadError kodları tus protokolündeki status kodlarına çevrilir
+func (h *TusHandler) handleError(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+	var uploadErr *fe.UploadError
+	if errors.As(err, &uploadErr) {
+		switch uploadErr.Code {
+		case "not_found":
+			status = fiber.StatusNotFound
+		case "upload_expired":
+			status = fiber.StatusGone
+		case "offset_mismatch", "upload_not_active":
+			status = fiber.StatusConflict
+		case "upload_too_large", "quota_exceeded":
+			status = fiber.StatusRequestEntityTooLarge
+		case "checksum_mismatch":
+			status = StatusChecksumMismatch
+		case "invalid_request", "filename_mismatch", "invalid_chunk":
+			status = fiber.StatusBadRequest
+		}
+	}
+	return c.Status(status).SendString(err.Error())
+}
+
+// StreamRequestBody açıkken gövde bağlantıdan okunur; küçük gövdeler fasthttp tarafından zaten okunmuş olabilir
+func requestBodyStream(c *fiber.Ctx) io.Reader {
+	if stream := c.Context().RequestBodyStream(); stream != nil {
+		return stream
+	}
+	return bytes.NewReader(c.Body())
+}
+
+// Upload-Metadata: "key base64(value),key2 base64(value2)"
+func parseTusMetadata(header string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	if header == "" {
+		return metadata, nil
+	}
+	for _, pair := range strings.Split(header, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), " ", 2)
+		if parts[0] == "" {
+			continue
+		}
+		if len(parts) == 1 {
+			metadata[parts[0]] = ""
+			continue
+		}
+		value, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			return nil, errors.New("Upload-Metadata geçersiz: " + parts[0])
+		}
+		metadata[parts[0]] = string(value)
+	}
+	return metadata, nil
+}
+
+func encodeTusMetadata(info *dto.TusUploadInfo) string {
+	pairs := []string{"filename " + base64.StdEncoding.EncodeToString([]byte(info.Filename))}
+	if info.MimeType != "" {
+		pairs = append(pairs, "filetype "+base64.StdEncoding.EncodeToString([]byte(info.MimeType)))
+	}
+	return strings.Join(pairs, ",")
+}
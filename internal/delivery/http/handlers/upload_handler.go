@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"log"
 	"strconv"
 
+	"file-uploader/internal/delivery/http/middleware"
 	"file-uploader/internal/domain/dto"
 	"file-uploader/internal/usecases"
+	fe "file-uploader/pkg/errors"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -22,10 +25,48 @@ func NewUploadHandler(uploadService usecases.UploadService) *UploadHandler {
 	}
 }
 
+// InitUpload
+//
+// @Summary      Init Upload
+// @Description  Opens a new upload session with declared size, chunk size and optional SHA-256, returns a server-issued upload ID
+// @Tags         Upload
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.InitUploadRequestDTO true "Init upload request"
+// @Success      201      {object}  dto.InitUploadResponse
+// @Failure      400      {object}  dto.ErrorResponse
+// @Failure      500      {object}  dto.ErrorResponse
+// @Router       /upload/init [post]
+func (h *UploadHandler) InitUpload(c *fiber.Ctx) error {
+	var req dto.InitUploadRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+	req.OwnerID = middleware.OwnerID(c)
+
+	response, err := h.uploadService.InitUpload(&req)
+	if err != nil {
+		// Yalnızca doğrulama hataları 400; oturum kaydedilemezse 500
+		var uploadErr *fe.UploadError
+		if errors.As(err, &uploadErr) && uploadErr.Code == "invalid_request" {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error: err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
 // UploadStatus
 //
 // @Summary      Get Upload Status
-// @Description  Returns the list of already uploaded chunks for a given upload session
+// @Description  Returns progress, missing chunk indices and lifecycle state for a given upload session
 // @Tags         Upload
 // @Accept       json
 // @Produce      json
@@ -33,12 +74,14 @@ func NewUploadHandler(uploadService usecases.UploadService) *UploadHandler {
 // @Param        filename   query     string true "File name"
 // @Success      200       {object}  dto.UploadStatusResponse
 // @Failure      400       {object}  dto.ErrorResponse "Missing parameter"
+// @Failure      404       {object}  dto.ErrorResponse "Upload session not found"
 // @Failure      500       {object}  dto.ErrorResponse "Internal server error"
 // @Router       /upload/status [get]
 func (h *UploadHandler) UploadStatus(c *fiber.Ctx) error {
 	req := &dto.UploadStatusRequestDTO{
 		UploadID: c.Query("upload_id"),
 		Filename: c.Query("filename"),
+		OwnerID:  middleware.OwnerID(c),
 	}
 
 	if req.UploadID == "" || req.Filename == "" {
@@ -49,6 +92,12 @@ func (h *UploadHandler) UploadStatus(c *fiber.Ctx) error {
 
 	response, err := h.uploadService.GetUploadStatus(req)
 	if err != nil {
+		var uploadErr *fe.UploadError
+		if errors.As(err, &uploadErr) && uploadErr.Code == "not_found" {
+			return c.Status(404).JSON(dto.ErrorResponse{
+				Error: err.Error(),
+			})
+		}
 		return c.Status(500).JSON(dto.ErrorResponse{
 			Error: err.Error(),
 		})
@@ -71,6 +120,7 @@ func (h *UploadHandler) UploadStatus(c *fiber.Ctx) error {
 // @Param        file         formData  file   true "Chunk file"
 // @Success      200          {object}  dto.UploadChunkResponse
 // @Failure      400          {object}  dto.ErrorResponse
+// @Failure      413          {object}  dto.ErrorResponse "Storage quota exceeded"
 // @Router       /upload/chunk [post]
 func (h *UploadHandler) UploadChunk(c *fiber.Ctx) error {
 	req := &dto.UploadChunkRequestDTO{
@@ -78,6 +128,7 @@ func (h *UploadHandler) UploadChunk(c *fiber.Ctx) error {
 		ChunkIndex: c.FormValue("chunk_index"),
 		Filename:   c.FormValue("filename"),
 		ChunkHash:  c.FormValue("chunk_hash"),
+		OwnerID:    middleware.OwnerID(c),
 	}
 
 	if req.UploadID == "" || req.ChunkIndex == "" || req.Filename == "" {
@@ -95,6 +146,12 @@ func (h *UploadHandler) UploadChunk(c *fiber.Ctx) error {
 
 	response, err := h.uploadService.UploadChunk(req, fileHeader)
 	if err != nil {
+		var uploadErr *fe.UploadError
+		if errors.As(err, &uploadErr) && uploadErr.Code == "quota_exceeded" {
+			return c.Status(fiber.StatusRequestEntityTooLarge).JSON(dto.ErrorResponse{
+				Error: err.Error(),
+			})
+		}
 		return c.Status(400).JSON(dto.ErrorResponse{
 			Error: err.Error(),
 		})
@@ -104,6 +161,51 @@ func (h *UploadHandler) UploadChunk(c *fiber.Ctx) error {
 	return c.JSON(response)
 }
 
+// QueryChunks
+//
+// @Summary      Query Chunks
+// @Description  Looks up chunk hashes in the server's shared chunk pool; known chunks are attached to the session and need not be uploaded
+// @Tags         Upload
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                   true "Upload ID"
+// @Param        request  body      dto.ChunkQueryRequestDTO true "Chunk indices and SHA-256 hashes"
+// @Success      200      {object}  dto.ChunkQueryResponse
+// @Failure      400      {object}  dto.ErrorResponse
+// @Failure      404      {object}  dto.ErrorResponse "Upload session not found"
+// @Failure      413      {object}  dto.ErrorResponse "Storage quota exceeded"
+// @Router       /upload/{id}/chunks/query [post]
+func (h *UploadHandler) QueryChunks(c *fiber.Ctx) error {
+	var req dto.ChunkQueryRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+	req.UploadID = c.Params("id")
+	req.OwnerID = middleware.OwnerID(c)
+
+	response, err := h.uploadService.QueryChunks(&req)
+	if err != nil {
+		var uploadErr *fe.UploadError
+		if errors.As(err, &uploadErr) && uploadErr.Code == "not_found" {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error: err.Error(),
+			})
+		}
+		if uploadErr != nil && uploadErr.Code == "quota_exceeded" {
+			return c.Status(fiber.StatusRequestEntityTooLarge).JSON(dto.ErrorResponse{
+				Error: err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	return c.JSON(response)
+}
+
 // CompleteUpload
 //
 // @Summary      Complete Upload
@@ -112,10 +214,14 @@ func (h *UploadHandler) UploadChunk(c *fiber.Ctx) error {
 // @Accept       multipart/form-data
 // @Produce      json
 // @Param        upload_id     formData  string true "Upload ID"
-// @Param        total_chunks  formData  int    true "Total chunks"
+// @Param        total_chunks  formData  int    false "Total chunks (must match the session if given)"
 // @Param        filename      formData  string true "File name"
+// @Param        sha256        formData  string false "Expected SHA-256 of the whole file (hex)"
+// @Param        md5           formData  string false "Expected MD5 of the whole file (hex)"
+// @Param        crc32c        formData  string false "Expected CRC32C of the whole file (hex)"
 // @Success      200           {object}  dto.CompleteUploadResponse
 // @Failure      400           {object}  dto.ErrorResponse
+// @Failure      413           {object}  dto.ErrorResponse "Storage quota exceeded"
 // @Router       /upload/complete [post]
 func (h *UploadHandler) CompleteUpload(c *fiber.Ctx) error {
 	totalChunks, _ := strconv.Atoi(c.FormValue("total_chunks"))
@@ -124,9 +230,13 @@ func (h *UploadHandler) CompleteUpload(c *fiber.Ctx) error {
 		UploadID:    c.FormValue("upload_id"),
 		TotalChunks: totalChunks,
 		Filename:    c.FormValue("filename"),
+		SHA256:      c.FormValue("sha256"),
+		MD5:         c.FormValue("md5"),
+		CRC32C:      c.FormValue("crc32c"),
+		OwnerID:     middleware.OwnerID(c),
 	}
 
-	if req.UploadID == "" || req.Filename == "" || req.TotalChunks <= 0 {
+	if req.UploadID == "" || req.Filename == "" || req.TotalChunks < 0 {
 		return c.Status(400).JSON(dto.ErrorResponse{
 			Error: "Eksik veya geçersiz parametre",
 		})
@@ -134,6 +244,12 @@ func (h *UploadHandler) CompleteUpload(c *fiber.Ctx) error {
 
 	response, err := h.uploadService.CompleteUpload(req)
 	if err != nil {
+		var uploadErr *fe.UploadError
+		if errors.As(err, &uploadErr) && uploadErr.Code == "quota_exceeded" {
+			return c.Status(fiber.StatusRequestEntityTooLarge).JSON(dto.ErrorResponse{
+				Error: err.Error(),
+			})
+		}
 		return c.Status(400).JSON(dto.ErrorResponse{
 			Error: err.Error(),
 		})
@@ -152,6 +268,8 @@ func (h *UploadHandler) CompleteUpload(c *fiber.Ctx) error {
 // @Param        request  body      dto.CancelUploadRequestDTO true "Cancel upload request"
 // @Success      200      {object}  dto.CancelUploadResponse
 // @Failure      400      {object}  dto.ErrorResponse
+// @Failure      404      {object}  dto.ErrorResponse "Upload session not found"
+// @Failure      409      {object}  dto.ErrorResponse "Upload already completed, merging or cancelled"
 // @Failure      500      {object}  dto.ErrorResponse
 // @Router       /upload/cancel [post]
 func (h *UploadHandler) CancelUpload(c *fiber.Ctx) error {
@@ -161,9 +279,21 @@ func (h *UploadHandler) CancelUpload(c *fiber.Ctx) error {
 			Error: err.Error(),
 		})
 	}
+	req.OwnerID = middleware.OwnerID(c)
 
 	resp, err := h.uploadService.CancelUpload(&req)
 	if err != nil {
+		var uploadErr *fe.UploadError
+		if errors.As(err, &uploadErr) && uploadErr.Code == "not_found" {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error: err.Error(),
+			})
+		}
+		if uploadErr != nil && uploadErr.Code == "upload_not_active" {
+			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+				Error: err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
 			Error: err.Error(),
 		})
@@ -196,7 +326,7 @@ func (h *UploadHandler) RetryMerge(c *fiber.Ctx) error {
 		})
 	}
 
-	finalPath, err := h.uploadService.RetryMerge(req.UploadID, req.Filename)
+	finalPath, err := h.uploadService.RetryMerge(middleware.OwnerID(c), req.UploadID, req.Filename)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
 			Error: err.Error(),
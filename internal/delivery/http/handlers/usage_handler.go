@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"fmt"
+
+	"file-uploader/internal/delivery/http/middleware"
+	"file-uploader/internal/domain/dto"
+	"file-uploader/internal/usecases"
+	fe "file-uploader/pkg/errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type UsageHandler struct {
+	quota usecases.QuotaService
+}
+
+func NewUsageHandler(quota usecases.QuotaService) *UsageHandler {
+	return &UsageHandler{
+		quota: quota,
+	}
+}
+
+// GetUsage
+//
+// @Summary      Get Storage Usage
+// @Description  Returns the caller's stored bytes (staging, originals, variants) broken down by media type, together with the quota. Admins may query another tenant with owner_id
+// @Tags         Usage
+// @Produce      json
+// @Param        owner_id  query     string false "Tenant (admin only)"
+// @Success      200       {object}  dto.UsageResponse
+// @Failure      403       {object}  dto.ErrorResponse
+// @Failure      500       {object}  dto.ErrorResponse
+// @Router       /usage [get]
+func (h *UsageHandler) GetUsage(c *fiber.Ctx) error {
+	ownerID := middleware.OwnerID(c)
+	if requested := c.Query("owner_id"); requested != "" && requested != ownerID {
+		principal := middleware.CurrentPrincipal(c)
+		if principal == nil || !principal.Admin {
+			return fe.HandleError(c, fe.ErrForbidden(fmt.Errorf("başka tenant'ın kullanımı için admin yetkisi gerekli")))
+		}
+		ownerID = requested
+	}
+
+	response, err := h.quota.GetUsage(ownerID)
+	if err != nil {
+		return adminError(c, err)
+	}
+	return c.JSON(response)
+}
+
+// SetQuota
+//
+// @Summary      Set Tenant Quota
+// @Description  Sets the storage quota of a tenant in bytes; 0 removes the limit. Tenants without a quota use QUOTA_DEFAULT_BYTES
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        owner_id  path      string                   true "Tenant"
+// @Param        request   body      dto.QuotaSetRequestDTO   true "Quota"
+// @Success      200       {object}  dto.QuotaResponse
+// @Failure      400       {object}  dto.ErrorResponse
+// @Failure      403       {object}  dto.ErrorResponse
+// @Router       /admin/quotas/{owner_id} [put]
+func (h *UsageHandler) SetQuota(c *fiber.Ctx) error {
+	var req dto.QuotaSetRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	response, err := h.quota.SetQuota(c.Params("owner_id"), req.MaxBytes)
+	if err != nil {
+		return adminError(c, err)
+	}
+	return c.JSON(response)
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"file-uploader/internal/domain/dto"
+	"file-uploader/internal/usecases"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type WebhookHandler struct {
+	webhooks usecases.WebhookService
+}
+
+func NewWebhookHandler(webhooks usecases.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhooks: webhooks,
+	}
+}
+
+// CreateWebhook
+//
+// @Summary      Create Webhook Subscription
+// @Description  Subscribes a URL to lifecycle events (upload.completed, upload.failed, media.processed, video.resized or "*"). The signing secret is generated if omitted and is only returned in this response
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.WebhookCreateRequestDTO true "Subscription"
+// @Success      201      {object}  dto.WebhookResponse
+// @Failure      400      {object}  dto.ErrorResponse
+// @Router       /webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *fiber.Ctx) error {
+	var req dto.WebhookCreateRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	response, err := h.webhooks.CreateSubscription(&req)
+	if err != nil {
+		return adminError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// ListWebhooks
+//
+// @Summary      List Webhook Subscriptions
+// @Tags         Webhooks
+// @Produce      json
+// @Success      200  {object}  dto.WebhookListResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Router       /webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *fiber.Ctx) error {
+	response, err := h.webhooks.ListSubscriptions()
+	if err != nil {
+		return adminError(c, err)
+	}
+	return c.JSON(response)
+}
+
+// GetWebhook
+//
+// @Summary      Get Webhook Subscription
+// @Tags         Webhooks
+// @Produce      json
+// @Param        id   path      string true "Subscription ID"
+// @Success      200  {object}  dto.WebhookResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Router       /webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(c *fiber.Ctx) error {
+	response, err := h.webhooks.GetSubscription(c.Params("id"))
+	if err != nil {
+		return adminError(c, err)
+	}
+	return c.JSON(response)
+}
+
+// UpdateWebhook
+//
+// @Summary      Update Webhook Subscription
+// @Description  Updates only the given fields; set active=false to pause deliveries
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string true "Subscription ID"
+// @Param        request  body      dto.WebhookUpdateRequestDTO true "Fields to update"
+// @Success      200      {object}  dto.WebhookResponse
+// @Failure      400      {object}  dto.ErrorResponse
+// @Failure      404      {object}  dto.ErrorResponse
+// @Router       /webhooks/{id} [patch]
+func (h *WebhookHandler) UpdateWebhook(c *fiber.Ctx) error {
+	var req dto.WebhookUpdateRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	response, err := h.webhooks.UpdateSubscription(c.Params("id"), &req)
+	if err != nil {
+		return adminError(c, err)
+	}
+	return c.JSON(response)
+}
+
+// DeleteWebhook
+//
+// @Summary      Delete Webhook Subscription
+// @Description  Deletes the subscription together with its delivery log
+// @Tags         Webhooks
+// @Param        id   path  string true "Subscription ID"
+// @Success      204
+// @Failure      404  {object}  dto.ErrorResponse
+// @Router       /webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *fiber.Ctx) error {
+	if err := h.webhooks.DeleteSubscription(c.Params("id")); err != nil {
+		return adminError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListWebhookDeliveries
+//
+// @Summary      List Webhook Deliveries
+// @Description  Delivery log of a subscription, newest first, with attempt count and last response
+// @Tags         Webhooks
+// @Produce      json
+// @Param        id      path      string true  "Subscription ID"
+// @Param        status  query     string false "pending, retrying, succeeded or failed"
+// @Param        page    query     int    false "Page (1-based)"
+// @Param        limit   query     int    false "Page size (max 100)"
+// @Success      200     {object}  dto.WebhookDeliveryListResponse
+// @Failure      400     {object}  dto.ErrorResponse
+// @Failure      404     {object}  dto.ErrorResponse
+// @Router       /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListWebhookDeliveries(c *fiber.Ctx) error {
+	var req dto.WebhookDeliveryListRequestDTO
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	response, err := h.webhooks.ListDeliveries(c.Params("id"), &req)
+	if err != nil {
+		return adminError(c, err)
+	}
+	return c.JSON(response)
+}
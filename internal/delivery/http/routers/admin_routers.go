@@ -0,0 +1,26 @@
+package routers
+
+import (
+	"file-uploader/internal/delivery/http/handlers"
+	"file-uploader/internal/delivery/http/middleware"
+	"file-uploader/internal/usecases"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupAdminRoutes(app *fiber.App, deadLetters usecases.DeadLetterService, apiKeys usecases.APIKeyService) {
+
+	adminHandler := handlers.NewAdminHandler(deadLetters)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeys)
+
+	// Routes (kimlik doğrulama /api/v1 altında global, burada yalnızca admin yetkisi aranır):
+	admin := app.Group("/api/v1/admin", middleware.RequireAdmin())
+	admin.Get("/jobs/failed", adminHandler.ListFailedJobs)
+	admin.Post("/jobs/failed/requeue", adminHandler.RequeueFailedJobs)
+	admin.Post("/jobs/failed/purge", adminHandler.PurgeFailedJobs)
+	admin.Get("/jobs/failed/:id", adminHandler.GetFailedJob)
+	admin.Get("/audit", adminHandler.ListAuditLogs)
+	admin.Post("/api-keys", apiKeyHandler.CreateAPIKey)
+	admin.Get("/api-keys", apiKeyHandler.ListAPIKeys)
+	admin.Delete("/api-keys/:id", apiKeyHandler.RevokeAPIKey)
+}
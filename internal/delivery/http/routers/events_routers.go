@@ -0,0 +1,18 @@
+package routers
+
+import (
+	"file-uploader/internal/delivery/http/handlers"
+	"file-uploader/internal/usecases"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupEventRoutes(app *fiber.App, progress usecases.ProgressService) {
+
+	eventsHandler := handlers.NewEventsHandler(progress)
+
+	// Routes:
+	api := app.Group("/api/v1")
+	api.Get("/upload/:id/events", eventsHandler.UploadEvents)
+	api.Get("/upload/:id/ws", eventsHandler.RequireUpgrade, eventsHandler.UploadEventsWS())
+}
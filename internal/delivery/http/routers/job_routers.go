@@ -0,0 +1,18 @@
+package routers
+
+import (
+	"file-uploader/internal/delivery/http/handlers"
+	"file-uploader/internal/usecases"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupJobRoutes(app *fiber.App, jobService usecases.JobService) {
+
+	jobHandler := handlers.NewJobHandler(jobService)
+
+	// Routes:
+	api := app.Group("/api/v1")
+	api.Get("/jobs/:id", jobHandler.GetJob)
+	api.Get("/upload/:id/jobs", jobHandler.ListUploadJobs)
+}
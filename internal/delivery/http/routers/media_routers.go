@@ -2,8 +2,9 @@ package routers
 
 import (
 	"file-uploader/internal/delivery/http/handlers"
+	"file-uploader/internal/delivery/http/middleware"
+	"file-uploader/internal/domain/repositories"
 	infra_repo "file-uploader/internal/infrastructure/repositories"
-	"file-uploader/internal/infrastructure/storage"
 	"file-uploader/internal/usecases"
 	"file-uploader/pkg/config"
 
@@ -11,30 +12,41 @@ import (
 	"gorm.io/gorm"
 )
 
-func SetupMediaRoutes(app *fiber.App, cfg *config.Config, database *gorm.DB) {
+func SetupMediaRoutes(app *fiber.App, cfg *config.Config, database *gorm.DB, fileStorage repositories.StorageStrategy) {
 	mediaRepo := infra_repo.NewMediaRepository(database)
 	variantRepo := infra_repo.NewMediaVariantRepository(database, mediaRepo)
 	sizeRepo := infra_repo.NewMediaSizeRepository(database)
 
-	// Storage
-	localStorage := storage.NewLocalStorage(cfg.Upload.UploadsDir)
 	videoRepo := infra_repo.NewVideoRepository(database)
+	blobRepo := infra_repo.NewBlobRepository(database)
+	mediaJobRepo := infra_repo.NewMediaJobRepository(database)
 
 	// Service
-	mediaService := usecases.NewMediaService(mediaRepo, variantRepo, sizeRepo, localStorage, videoRepo)
-	mediaHandler := handlers.NewMediaHandler(mediaService)
+	quotaService := usecases.NewQuotaService(infra_repo.NewUsageRepository(database), fileStorage, cfg.Quota)
+	mediaService := usecases.NewMediaService(mediaRepo, variantRepo, sizeRepo, fileStorage, videoRepo, blobRepo, mediaJobRepo, quotaService)
+	mediaHandler := handlers.NewMediaHandler(mediaService, cfg.Content)
 
 	api := app.Group("/api/v1")
 	// Image:
+	api.Get("/media", mediaHandler.ListMedia)
+	api.Get("/media/trash", mediaHandler.GetDeletedMedia) // /media/:id'den önce eşleşmeli
 	api.Get("/media/:id", mediaHandler.GetMedia)
+	api.Get("/media/:id/content", mediaHandler.GetMediaContent)
 	api.Post("/media", mediaHandler.CreateMedia) // gerek yok ama deneme amaçlı oluşturdum
-	api.Post("/media/size", mediaHandler.CreateSize)
-	api.Put("/media/size", mediaHandler.UpdateSize)
-	//! GetAllMedia eklenebilir
+	// Varyant boyutları tüm tenant'ları etkilediği için yalnızca admin:
+	api.Post("/media/size", middleware.RequireAdmin(), mediaHandler.CreateSize)
+	api.Put("/media/size", middleware.RequireAdmin(), mediaHandler.UpdateSize)
+	api.Delete("/media/:id", mediaHandler.DeleteMedia)
+	api.Post("/media/:id/restore", mediaHandler.RestoreMedia)
 	// Video:
+	api.Get("/video", mediaHandler.ListVideos)
+	api.Get("/video/trash", mediaHandler.GetDeletedVideos)
 	api.Get("/video/:video_id", mediaHandler.GetVideoByID)
+	api.Get("/video/:video_id/content", mediaHandler.GetVideoContent)
 	api.Post("/video/create", mediaHandler.CreateVideo)
 	api.Post("/video/:video_id/resize", mediaHandler.ResizeVideo)
+	api.Delete("/video/:video_id", mediaHandler.DeleteVideo)
+	api.Post("/video/:video_id/restore", mediaHandler.RestoreVideo)
 	//api.Post("/video/:video_id/width", mediaHandler.ResizeByWidth)
 	//api.Post("/video/:video_id/height", mediaHandler.ResizeByHeight)
 }
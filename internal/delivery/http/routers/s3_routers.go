@@ -0,0 +1,21 @@
+package routers
+
+import (
+	"file-uploader/internal/delivery/http/handlers"
+	"file-uploader/internal/usecases"
+	"file-uploader/pkg/config"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupS3Routes(app *fiber.App, s3Service usecases.S3GatewayService, cfg config.S3GatewayConfig) {
+
+	s3Handler := handlers.NewS3Handler(s3Service, cfg)
+
+	// S3 uyumlu multipart upload (path-style, SigV4):
+	s3 := app.Group("/s3/:bucket", s3Handler.VerifySignature)
+	s3.Post("/*", s3Handler.Post)
+	s3.Put("/*", s3Handler.UploadPart)
+	s3.Delete("/*", s3Handler.AbortMultipartUpload)
+	s3.Get("/*", s3Handler.ListParts)
+}
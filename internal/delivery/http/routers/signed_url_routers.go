@@ -0,0 +1,21 @@
+package routers
+
+import (
+	"file-uploader/internal/delivery/http/handlers"
+	"file-uploader/internal/delivery/http/middleware"
+	"file-uploader/internal/usecases"
+	"file-uploader/pkg/config"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupSignedURLRoutes(app *fiber.App, signedURLs usecases.SignedURLService, cfg config.ContentConfig) {
+
+	signedURLHandler := handlers.NewSignedURLHandler(signedURLs, cfg)
+
+	// Routes:
+	api := app.Group("/api/v1")
+	api.Post("/signed-urls", signedURLHandler.CreateSignedURL)
+	// İmzalı URL ile de çağrılabilir
+	api.Get("/download/:id", middleware.EnforceSignedURL(), signedURLHandler.Download)
+}
@@ -0,0 +1,24 @@
+package routers
+
+import (
+	"file-uploader/internal/delivery/http/handlers"
+	"file-uploader/internal/usecases"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const tusBasePath = "/api/v1/tus"
+
+func SetupTusRoutes(app *fiber.App, tusService usecases.TusService) {
+
+	tusHandler := handlers.NewTusHandler(tusService, tusBasePath)
+
+	// tus 1.0 (creation, expiration, checksum, termination):
+	tus := app.Group(tusBasePath, tusHandler.RequireTusResumable)
+	tus.Options("/", tusHandler.Options)
+	tus.Options("/:id", tusHandler.Options)
+	tus.Post("/", tusHandler.CreateUpload)
+	tus.Head("/:id", tusHandler.GetOffset)
+	tus.Patch("/:id", tusHandler.AppendChunk)
+	tus.Delete("/:id", tusHandler.TerminateUpload)
+}
@@ -2,6 +2,7 @@ package routers
 
 import (
 	"file-uploader/internal/delivery/http/handlers"
+	"file-uploader/internal/delivery/http/middleware"
 	"file-uploader/internal/usecases"
 
 	"github.com/gofiber/fiber/v2"
@@ -13,7 +14,10 @@ func SetupUploadRoutes(app *fiber.App, uploadService usecases.UploadService) {
 
 	// Routes:
 	api := app.Group("/api/v1")
-	api.Post("/upload/chunk", uploadHandler.UploadChunk)
+	api.Post("/upload/init", uploadHandler.InitUpload)
+	// İmzalı URL ile de çağrılabilir (bkz. /signed-urls)
+	api.Post("/upload/chunk", middleware.EnforceSignedURL(), uploadHandler.UploadChunk)
+	api.Post("/upload/:id/chunks/query", uploadHandler.QueryChunks)
 	api.Post("/upload/complete", uploadHandler.CompleteUpload)
 	api.Post("/upload/cancel", uploadHandler.CancelUpload)
 	api.Get("/upload/status", uploadHandler.UploadStatus)
@@ -0,0 +1,20 @@
+package routers
+
+import (
+	"file-uploader/internal/delivery/http/handlers"
+	"file-uploader/internal/delivery/http/middleware"
+	"file-uploader/internal/usecases"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupUsageRoutes(app *fiber.App, quota usecases.QuotaService) {
+
+	usageHandler := handlers.NewUsageHandler(quota)
+
+	// Routes:
+	api := app.Group("/api/v1")
+	api.Get("/usage", usageHandler.GetUsage)
+	// Kota tanımları yalnızca admin:
+	api.Put("/admin/quotas/:owner_id", middleware.RequireAdmin(), usageHandler.SetQuota)
+}
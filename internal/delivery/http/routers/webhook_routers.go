@@ -0,0 +1,23 @@
+package routers
+
+import (
+	"file-uploader/internal/delivery/http/handlers"
+	"file-uploader/internal/delivery/http/middleware"
+	"file-uploader/internal/usecases"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupWebhookRoutes(app *fiber.App, webhookService usecases.WebhookService) {
+
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+
+	// Routes (yalnızca admin):
+	webhooks := app.Group("/api/v1/webhooks", middleware.RequireAdmin())
+	webhooks.Post("/", webhookHandler.CreateWebhook)
+	webhooks.Get("/", webhookHandler.ListWebhooks)
+	webhooks.Get("/:id", webhookHandler.GetWebhook)
+	webhooks.Patch("/:id", webhookHandler.UpdateWebhook)
+	webhooks.Delete("/:id", webhookHandler.DeleteWebhook)
+	webhooks.Get("/:id/deliveries", webhookHandler.ListWebhookDeliveries)
+}
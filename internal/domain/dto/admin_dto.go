@@ -0,0 +1,79 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// GET /admin/jobs/failed sorgu parametreleri; older_than/newer_than Go duration formatındadır (örn. 24h)
+type FailedJobListRequestDTO struct {
+	Page      int    `query:"page"`
+	Limit     int    `query:"limit"`
+	JobType   string `query:"job_type"`
+	Status    string `query:"status"`
+	OlderThan string `query:"older_than"`
+	NewerThan string `query:"newer_than"`
+}
+
+// Toplu requeue/purge: id listesi ya da filtrelerden en az biri verilmelidir
+type FailedJobBulkRequestDTO struct {
+	IDs       []uint `json:"ids,omitempty"`
+	JobType   string `json:"job_type,omitempty"`
+	Status    string `json:"status,omitempty"`
+	OlderThan string `json:"older_than,omitempty"`
+	NewerThan string `json:"newer_than,omitempty"`
+	Actor     string `json:"-"`
+}
+
+type FailedJobItem struct {
+	ID        uint      `json:"id"`
+	UploadID  string    `json:"upload_id"`
+	JobType   string    `json:"job_type"`
+	Status    string    `json:"status"`
+	LastError string    `json:"last_error"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type FailedJobListResponse struct {
+	Items []FailedJobItem `json:"items"`
+	Page  int             `json:"page"`
+	Limit int             `json:"limit"`
+	Total int64           `json:"total"`
+}
+
+// Payload JSON ise olduğu gibi, değilse base64 olarak döner
+type FailedJobDetailResponse struct {
+	FailedJobItem
+	Payload       json.RawMessage `json:"payload,omitempty" swaggertype:"object"`
+	PayloadBase64 string          `json:"payload_base64,omitempty"`
+}
+
+type FailedJobSkip struct {
+	ID     uint   `json:"id"`
+	Reason string `json:"reason"`
+}
+
+type FailedJobBulkResponse struct {
+	Action   string          `json:"action"`
+	Matched  int             `json:"matched"`
+	Affected int             `json:"affected"`
+	Skipped  []FailedJobSkip `json:"skipped,omitempty"`
+	AuditID  uint            `json:"audit_id"`
+}
+
+type AuditLogListResponse struct {
+	Items []AuditLogItem `json:"items"`
+	Page  int            `json:"page"`
+	Limit int            `json:"limit"`
+	Total int64          `json:"total"`
+}
+
+type AuditLogItem struct {
+	ID        uint      `json:"id"`
+	Action    string    `json:"action"`
+	Actor     string    `json:"actor"`
+	Criteria  string    `json:"criteria"`
+	TargetIDs string    `json:"target_ids"`
+	Affected  int       `json:"affected"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+import "time"
+
+// OwnerID verilmezse anahtar, isteği yapan admin'in tenant'ına açılır
+type APIKeyCreateRequestDTO struct {
+	Name    string `json:"name"`
+	OwnerID string `json:"owner_id"`
+	Admin   bool   `json:"admin"`
+}
+
+// Key yalnızca oluşturma yanıtında döner
+type APIKeyResponse struct {
+	ID         string     `json:"id"`
+	OwnerID    string     `json:"owner_id"`
+	Name       string     `json:"name,omitempty"`
+	Prefix     string     `json:"prefix"`
+	Admin      bool       `json:"admin"`
+	Key        string     `json:"key,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+type APIKeyListResponse struct {
+	Items []APIKeyResponse `json:"items"`
+}
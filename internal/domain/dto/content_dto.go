@@ -0,0 +1,17 @@
+package dto
+
+import (
+	"mime/multipart"
+	"time"
+)
+
+// Stream edilecek dosya. Bilgileri StorageStrategy.Stat ile alınır, dosya yalnızca gövde gönderilecekse
+// Open ile açılır (304/416 yanıtlarında açılmaz); açılan dosyayı çağıran kapatır
+type MediaContent struct {
+	Open        func() (multipart.File, error)
+	Size        int64
+	ModTime     time.Time
+	ETag        string // tırnaklı, strong ETag
+	ContentType string
+	Filename    string
+}
@@ -3,22 +3,27 @@ package dto
 import "time"
 
 type ImageDTO struct {
-	ID           string    `json:"id"`
-	OriginalName string    `json:"original_name"`
-	FileType     string    `json:"file_type"`
-	FilePath     string    `json:"file_path"`
-	Status       string    `json:"status"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           string     `json:"id"`
+	OwnerID      string     `json:"owner_id,omitempty"`
+	OriginalName string     `json:"original_name"`
+	FileType     string     `json:"file_type"`
+	FilePath     string     `json:"file_path"`
+	SHA256       string     `json:"sha256,omitempty"`
+	Status       string     `json:"status"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"` // çöp kutusundaysa
 }
 
 type MediaVariant struct {
 	VariantID   string `json:"variant_id"`
 	MediaID     string `json:"media_id"`
+	OwnerID     string `json:"owner_id,omitempty"`
 	VariantName string `json:"variant_name"`
 	Width       int    `json:"width"`
 	Height      int    `json:"height"`
-	FilePath    string `json:"file_path"`
+	FilePath    string `json:"file_path,omitempty"`
+	ContentURL  string `json:"content_url,omitempty"` // yalnızca GET /media/{id} yanıtında, file_path yerine
 	//Status      string `json:"status"`
 }
 
@@ -0,0 +1,21 @@
+package dto
+
+import "time"
+
+type JobResponse struct {
+	ID         string     `json:"id"`
+	UploadID   string     `json:"upload_id"`
+	Type       string     `json:"type"`
+	Status     string     `json:"status"` // queued, running, retrying, succeeded, failed
+	Attempts   int        `json:"attempts"`
+	LastError  string     `json:"last_error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+type UploadJobsResponse struct {
+	UploadID string        `json:"upload_id"`
+	Jobs     []JobResponse `json:"jobs"`
+}
@@ -2,31 +2,38 @@ package dto
 
 import "time"
 
+// GET /media/{id}: image ya da video, varyantları ve iş geçmişiyle birlikte
 type Media struct {
-	MediaID      string     `json:"id"`
-	Filename     string     `json:"file_name"`
-	FileType     string     `json:"file_type"` // image / video
-	FileSize     int64      `json:"file_size"`
-	Status       string     `json:"status"` // pending, processing, completed, failed
-	OriginalPath string     `json:"original_path"`
-	Jobs         []MediaJob `json:"jobs,omitempty"` // her bir iş
-	Metadata     Metadata   `json:"metadata,omitempty"`
-	CreatedAt    time.Time  `json:"created_at"`
-	UpdatedAt    time.Time  `json:"updated_at"`
+	MediaID    string         `json:"id"`
+	OwnerID    string         `json:"owner_id,omitempty"`
+	Type       string         `json:"type"` // image / video
+	Filename   string         `json:"file_name"`
+	FileType   string         `json:"file_type"` // MIME tipi
+	FileSize   int64          `json:"file_size"`
+	Status     string         `json:"status"`      // processing, completed, resized, failed
+	ContentURL string         `json:"content_url"` // orijinal dosya, GET /media/{id}/content ya da /video/{id}/content
+	SHA256     string         `json:"sha256,omitempty"`
+	Variants   []MediaVariant `json:"variants"`
+	Jobs       []MediaJob     `json:"jobs"` // her bir iş, en eski önce
+	Metadata   Metadata       `json:"metadata"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
 }
 
 // İş tanımı
 type MediaJob struct {
 	JobID      string            `json:"job_id"`
 	MediaID    string            `json:"media_id"`
-	Type       string            `json:"type"`             // resize, compress
-	Status     string            `json:"status"`           // pending, processing, completed, failed
-	Params     map[string]string `json:"params,omitempty"` // width/height veya resolution
+	Type       string            `json:"type"`             // variant, resize
+	Status     string            `json:"status"`           // processing, completed, failed
+	Params     map[string]string `json:"params,omitempty"` // width/height, varyant tipi
 	Width      int               `json:"width,omitempty"`
 	Height     int               `json:"height,omitempty"`
 	OutputPath string            `json:"output_path,omitempty"`
+	Error      string            `json:"error,omitempty"`
 	CreatedAt  time.Time         `json:"created_at"`
 	UpdatedAt  time.Time         `json:"updated_at"`
+	FinishedAt *time.Time        `json:"finished_at,omitempty"`
 }
 
 type Metadata struct {
@@ -45,11 +52,31 @@ type MediaRegisterRequestDTO struct {
 	FilePath string `json:"file_path"`
 }
 
-// API üzerinden job bilgisi dönebilir
-type MediaJobResponseDTO struct {
-	JobID      string            `json:"job_id"`
-	Type       string            `json:"type"`
-	Status     string            `json:"status"`
-	Params     map[string]string `json:"params"`
-	OutputPath string            `json:"output_path"`
+// GET /media ve /video sorgu parametreleri. file_type "image/*" biçiminde önek olarak verilebilir,
+// tarihler RFC3339'dur. cursor bir önceki yanıtın next_cursor değeridir ve aynı sort/order ile kullanılmalıdır
+type MediaListRequestDTO struct {
+	Status        string `query:"status"`
+	FileType      string `query:"file_type"`
+	CreatedAfter  string `query:"created_after"`
+	CreatedBefore string `query:"created_before"`
+	NamePrefix    string `query:"name_prefix"`
+	OwnerID       string `query:"owner_id"` // yalnızca admin
+	Sort          string `query:"sort"`     // created_at (varsayılan), updated_at, original_name
+	Order         string `query:"order"`    // desc (varsayılan), asc
+	Limit         int    `query:"limit"`
+	Cursor        string `query:"cursor"`
+}
+
+type MediaListResponse struct {
+	Items      []*ImageDTO `json:"items"`
+	Total      int64       `json:"total"`
+	Limit      int         `json:"limit"`
+	NextCursor string      `json:"next_cursor,omitempty"` // son sayfada boş
+}
+
+type VideoListResponse struct {
+	Items      []*VideoDTO `json:"items"`
+	Total      int64       `json:"total"`
+	Limit      int         `json:"limit"`
+	NextCursor string      `json:"next_cursor,omitempty"`
 }
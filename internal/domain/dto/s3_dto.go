@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+const S3XMLNamespace = "http://s3.amazonaws.com/doc/2006-03-01/"
+
+// POST /{bucket}/{key}?uploads
+type InitiateMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	UploadID string   `xml:"UploadId"`
+}
+
+// POST /{bucket}/{key}?uploadId=... gövdesi
+type CompleteMultipartUpload struct {
+	XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+	Parts   []CompletedPart `xml:"Part"`
+}
+
+type CompletedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+type CompleteMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"CompleteMultipartUploadResult"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Location string   `xml:"Location"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	ETag     string   `xml:"ETag"`
+}
+
+// GET /{bucket}/{key}?uploadId=...
+type ListPartsResult struct {
+	XMLName  xml.Name     `xml:"ListPartsResult"`
+	Xmlns    string       `xml:"xmlns,attr"`
+	Bucket   string       `xml:"Bucket"`
+	Key      string       `xml:"Key"`
+	UploadID string       `xml:"UploadId"`
+	Parts    []S3PartInfo `xml:"Part"`
+}
+
+type S3PartInfo struct {
+	PartNumber   int       `xml:"PartNumber"`
+	LastModified time.Time `xml:"LastModified"`
+	ETag         string    `xml:"ETag"`
+	Size         int64     `xml:"Size"`
+}
+
+type S3Error struct {
+	XMLName   xml.Name `xml:"Error"`
+	Code      string   `xml:"Code"`
+	Message   string   `xml:"Message"`
+	Resource  string   `xml:"Resource,omitempty"`
+	RequestID string   `xml:"RequestId,omitempty"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+import "time"
+
+// operation: upload (chunk upload, upload_id zorunlu) | download (media ya da video, media_id zorunlu; variant verilmezse orijinal).
+// expires_in saniye cinsindendir; verilmezse SIGNED_URL_DEFAULT_TTL kullanılır
+type SignedURLRequestDTO struct {
+	Operation   string `json:"operation"`
+	UploadID    string `json:"upload_id,omitempty"`
+	MediaID     string `json:"media_id,omitempty"`
+	Variant     string `json:"variant,omitempty"`
+	ExpiresIn   int64  `json:"expires_in,omitempty"`
+	MaxSize     int64  `json:"max_size,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+}
+
+// URL sunucuya göre görelidir ve tek bir method için geçerlidir
+type SignedURLResponse struct {
+	URL       string    `json:"url"`
+	Method    string    `json:"method"`
+	ExpiresAt time.Time `json:"expires_at"`
+	KeyID     string    `json:"key_id"`
+	MaxSize   int64     `json:"max_size,omitempty"`
+}
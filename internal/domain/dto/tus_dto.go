@@ -0,0 +1,14 @@
+package dto
+
+import "time"
+
+// tus oturumunun HEAD/PATCH yanıtlarında dönen bilgileri
+type TusUploadInfo struct {
+	UploadID  string
+	Filename  string
+	MimeType  string
+	Offset    int64
+	Length    int64
+	Status    string
+	ExpiresAt time.Time
+}
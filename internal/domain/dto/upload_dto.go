@@ -1,6 +1,29 @@
 package dto
 
+import "time"
+
+// OwnerID istekten değil kimliği doğrulanmış principal'dan gelir
+type InitUploadRequestDTO struct {
+	OwnerID   string `json:"-" form:"-"`
+	Filename  string `json:"filename" form:"filename"`
+	TotalSize int64  `json:"total_size" form:"total_size"`
+	ChunkSize int64  `json:"chunk_size" form:"chunk_size"`
+	MimeType  string `json:"mime_type" form:"mime_type"`
+	SHA256    string `json:"sha256,omitempty" form:"sha256"` // opsiyonel, tüm dosyanın hash'i
+}
+
+type InitUploadResponse struct {
+	UploadID    string    `json:"upload_id"`
+	Filename    string    `json:"filename"`
+	TotalSize   int64     `json:"total_size"`
+	ChunkSize   int64     `json:"chunk_size"`
+	TotalChunks int       `json:"total_chunks"`
+	Status      string    `json:"status"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
 type UploadChunkRequestDTO struct {
+	OwnerID    string `json:"-" form:"-"`
 	UploadID   string `json:"upload_id" form:"upload_id"`
 	ChunkIndex string `json:"chunk_index" form:"chunk_index"`
 	Filename   string `json:"filename" form:"filename"`
@@ -8,9 +31,33 @@ type UploadChunkRequestDTO struct {
 }
 
 type CompleteUploadRequestDTO struct {
+	OwnerID     string `json:"-" form:"-"`
 	UploadID    string `json:"upload_id" form:"upload_id"`
 	TotalChunks int    `json:"total_chunks" form:"total_chunks"`
 	Filename    string `json:"filename" form:"filename"`
+	// Tüm dosyanın beklenen özetleri (hex), merge sırasında doğrulanır
+	SHA256 string `json:"sha256,omitempty" form:"sha256"`
+	MD5    string `json:"md5,omitempty" form:"md5"`
+	CRC32C string `json:"crc32c,omitempty" form:"crc32c"`
+}
+
+// Client chunk'ları göndermeden önce hangilerinin sunucudaki havuzda bulunduğunu sorar
+type ChunkQueryRequestDTO struct {
+	OwnerID  string           `json:"-"`
+	UploadID string           `json:"-"`
+	Chunks   []ChunkQueryItem `json:"chunks"`
+}
+
+type ChunkQueryItem struct {
+	Index int    `json:"index"`
+	Hash  string `json:"hash"`           // chunk'ın SHA-256'sı (hex)
+	Size  int64  `json:"size,omitempty"` // opsiyonel, verilirse havuzdaki boyutla eşleşmeli
+}
+
+type ChunkQueryResponse struct {
+	UploadID string `json:"upload_id"`
+	Existing []int  `json:"existing"` // oturuma bağlanan, tekrar gönderilmesi gerekmeyen chunk'lar
+	Missing  []int  `json:"missing"`  // gönderilmesi gereken chunk'lar
 }
 
 type CompleteRetryRequest struct {
@@ -19,24 +66,34 @@ type CompleteRetryRequest struct {
 }
 
 type UploadStatusRequestDTO struct {
+	OwnerID  string `json:"-" form:"-"`
 	UploadID string `json:"upload_id" form:"upload_id"`
 	Filename string `json:"filename" form:"filename"`
 }
 
 type CancelUploadRequestDTO struct {
+	OwnerID  string `json:"-" form:"-"`
 	UploadID string `json:"upload_id" form:"upload_id"`
 }
 
 type CancelUploadResponse struct {
 	Status  string `json:"status"`            // "ok" veya "failed" şeklinde
 	Message string `json:"message,omitempty"` // opsiyonel açıklama
+	JobID   string `json:"job_id,omitempty"`  // cleanup job'u
 }
 
 type UploadStatusResponse struct {
-	UploadID       string `json:"upload_id"`
-	Filename       string `json:"filename"`
-	UploadedChunks int    `json:"uploaded_chunks"`
-	Status         string `json:"status,omitempty"` // "completed", "failed" gibi (opsiyonel)
+	UploadID       string    `json:"upload_id"`
+	Filename       string    `json:"filename"`
+	TotalChunks    int       `json:"total_chunks"`
+	UploadedChunks int       `json:"uploaded_chunks"`
+	UploadedBytes  int64     `json:"uploaded_bytes"`
+	MissingChunks  []int     `json:"missing_chunks"`
+	Progress       int       `json:"progress"`         // yüzde (0-100)
+	Status         string    `json:"status,omitempty"` // "in_progress", "merging", "completed", "failed", "cancelled", "expired"
+	ExpiresAt      time.Time `json:"expires_at"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
 }
 
 type UploadChunkResponse struct {
@@ -44,6 +101,7 @@ type UploadChunkResponse struct {
 	UploadID   string `json:"upload_id"`
 	ChunkIndex int    `json:"chunk_index"`
 	Filename   string `json:"filename"`
+	JobID      string `json:"job_id"` // GET /jobs/{id} ile takip edilir
 	Message    string `json:"message,omitempty"`
 	//TotalChunks int    `json:"total_chunks,omitempty"` // opsiyonel
 }
@@ -52,6 +110,7 @@ type CompleteUploadResponse struct {
 	Status   string `json:"status"`
 	Message  string `json:"message"`
 	Filename string `json:"filename"`
+	JobID    string `json:"job_id"` // merge job'u, GET /jobs/{id} ile takip edilir
 }
 
 type ErrorResponse struct {
@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+type UsageBreakdown struct {
+	StagingBytes  int64 `json:"staging_bytes"`
+	OriginalBytes int64 `json:"original_bytes"`
+	VariantBytes  int64 `json:"variant_bytes"`
+	TotalBytes    int64 `json:"total_bytes"`
+}
+
+// QuotaBytes 0 ise kota yoktur ve RemainingBytes dönmez
+type UsageResponse struct {
+	OwnerID        string                    `json:"owner_id"`
+	QuotaBytes     int64                     `json:"quota_bytes"`
+	UsedBytes      int64                     `json:"used_bytes"`
+	RemainingBytes *int64                    `json:"remaining_bytes,omitempty"`
+	Totals         UsageBreakdown            `json:"totals"`
+	ByMediaType    map[string]UsageBreakdown `json:"by_media_type"` // image, video, other
+}
+
+// max_bytes 0 verilirse tenant'ın kotası kaldırılır (sınırsız)
+type QuotaSetRequestDTO struct {
+	MaxBytes int64 `json:"max_bytes"`
+}
+
+type QuotaResponse struct {
+	OwnerID   string    `json:"owner_id"`
+	MaxBytes  int64     `json:"max_bytes"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
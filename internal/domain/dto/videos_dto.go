@@ -3,15 +3,18 @@ package dto
 import "time"
 
 type VideoDTO struct {
-	VideoID      string    `json:"video_id"`
-	OriginalName string    `json:"original_name"`
-	FileType     string    `json:"file_type"`
-	FilePath     string    `json:"file_path"`
-	Status       string    `json:"status"`
-	Height       int64     `json:"height"`
-	Width        int64     `json:"width"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	VideoID      string     `json:"video_id"`
+	OwnerID      string     `json:"owner_id,omitempty"`
+	OriginalName string     `json:"original_name"`
+	FileType     string     `json:"file_type"`
+	FilePath     string     `json:"file_path"`
+	SHA256       string     `json:"sha256,omitempty"`
+	Status       string     `json:"status"`
+	Height       int64      `json:"height"`
+	Width        int64      `json:"width"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"` // çöp kutusundaysa
 }
 
 type Output struct {
@@ -0,0 +1,20 @@
+package entities
+
+import "time"
+
+// Anahtarın kendisi saklanmaz, yalnızca SHA-256 özeti tutulur; Prefix listelemede anahtarı tanımak içindir
+type APIKey struct {
+	ID         string     `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	OwnerID    string     `json:"owner_id" gorm:"type:varchar(255);not null;index"`
+	Name       string     `json:"name" gorm:"type:varchar(255)"`
+	Prefix     string     `json:"prefix" gorm:"type:varchar(16);not null"`
+	KeyHash    string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	Admin      bool       `json:"admin" gorm:"not null;default:false"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
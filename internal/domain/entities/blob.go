@@ -0,0 +1,18 @@
+package entities
+
+import "time"
+
+// Blob, aynı içeriğe (SHA-256) sahip media/video kayıtlarının paylaştığı fiziksel dosyayı temsil eder
+type Blob struct {
+	SHA256    string    `json:"sha256" gorm:"column:sha256;primaryKey;type:varchar(64)"`
+	FilePath  string    `json:"file_path" gorm:"type:varchar(500);not null"`
+	Size      int64     `json:"size"`
+	RefCount  int       `json:"ref_count"`
+	OriginID  string    `json:"origin_id,omitempty" gorm:"type:varchar(36)"` // varyantları üretilen ilk media/video kaydı
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (Blob) TableName() string {
+	return "blobs"
+}
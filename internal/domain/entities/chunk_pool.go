@@ -0,0 +1,18 @@
+package entities
+
+import "time"
+
+// Merge'i başarıyla tamamlanmış upload'ların chunk'ları tenant ve SHA-256'ya göre havuzda tutulur,
+// aynı tenant'ın sonraki upload'ları chunk'ı tekrar göndermeden kullanabilir
+type PooledChunk struct {
+	OwnerID    string    `json:"owner_id" gorm:"primaryKey;type:varchar(255)"`
+	SHA256     string    `json:"sha256" gorm:"column:sha256;primaryKey;type:varchar(64)"`
+	Size       int64     `json:"size"`
+	FilePath   string    `json:"file_path" gorm:"type:varchar(500);not null"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+}
+
+func (PooledChunk) TableName() string {
+	return "chunk_pool"
+}
@@ -0,0 +1,19 @@
+package entities
+
+import "time"
+
+// Şifreli olarak saklanan bir dosyanın veri anahtarı. Anahtar, KeyID'li master key ile sarılmış halde tutulur;
+// rotasyonda yalnızca WrappedKey ve KeyID değişir, dosyanın kendisi yeniden yazılmaz
+type EncryptedObject struct {
+	Backend    string    `json:"backend" gorm:"primaryKey;type:varchar(200)"` // "local" ya da "s3:<bucket>"
+	Location   string    `json:"location" gorm:"primaryKey;type:varchar(500)"`
+	KeyID      string    `json:"key_id" gorm:"type:varchar(100);not null"`
+	WrappedKey []byte    `json:"-" gorm:"type:bytea;not null"`
+	Size       int64     `json:"size"` // şifresiz boyut
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (EncryptedObject) TableName() string {
+	return "encrypted_objects"
+}
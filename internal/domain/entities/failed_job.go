@@ -0,0 +1,33 @@
+package entities
+
+import "time"
+
+// Worker'ın tekrar denemeden vazgeçtiği iş (dead-letter), payload kuyruğa atılan Job JSON'ıdır
+type FailedJob struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UploadID  string    `json:"upload_id" gorm:"type:varchar(255);not null"`
+	JobType   string    `json:"job_type" gorm:"type:varchar(50);not null"`
+	LastError string    `json:"last_error" gorm:"type:varchar(255);not null"`
+	Payload   []byte    `json:"-" gorm:"type:bytea;not null"`
+	JobStatus string    `json:"job_status" gorm:"type:varchar(20);default:failed"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (FailedJob) TableName() string {
+	return "failed_jobs"
+}
+
+// Admin API'de yapılan her değişiklik (requeue, purge) kim tarafından, hangi kayıtlara yapıldığıyla tutulur
+type AdminAuditLog struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Action    string    `json:"action" gorm:"type:varchar(50);not null;index"`
+	Actor     string    `json:"actor" gorm:"type:varchar(255);not null"`
+	Criteria  string    `json:"criteria" gorm:"type:text"` // isteğin JSON hali (id listesi / filtreler)
+	TargetIDs string    `json:"target_ids" gorm:"type:text"`
+	Affected  int       `json:"affected"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (AdminAuditLog) TableName() string {
+	return "admin_audit_logs"
+}
@@ -9,9 +9,11 @@ import (
 
 type Image struct {
 	ID           uuid.UUID `gorm:"type:uuid;primaryKey"`
+	OwnerID      string    `gorm:"type:varchar(255);index"`
 	OriginalName string
 	FileType     string
 	FilePath     string
+	SHA256       string `gorm:"column:sha256;type:varchar(64)"` // merge sırasında doğrulanan özet
 	Status       string `gorm:"type:varchar(20)"`
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
@@ -21,11 +23,13 @@ type Image struct {
 type MediaVariant struct {
 	VariantID   uuid.UUID `gorm:"type:uuid;primaryKey"`
 	MediaID     uuid.UUID
+	OwnerID     string `gorm:"type:varchar(255);index"`
 	VariantName string
 	Width       int
 	Height      int
 	FilePath    string
 	CreatedAt   time.Time
+	DeletedAt   gorm.DeletedAt `gorm:"index"` // media ile birlikte çöp kutusuna taşınır
 }
 
 type MediaSize struct {
@@ -0,0 +1,22 @@
+package entities
+
+import "time"
+
+// Kuyruğa atılan her iş için kalıcı kayıt; client dönen job ID ile işin durumunu takip eder.
+// Otomatik retry aynı ID ile devam eder: queued -> running -> retrying -> running -> succeeded/failed
+type Job struct {
+	ID         string     `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UploadID   string     `json:"upload_id" gorm:"type:varchar(255);not null;index"`
+	Type       string     `json:"type" gorm:"type:varchar(50);not null"`
+	Status     string     `json:"status" gorm:"type:varchar(20);not null"`
+	Attempts   int        `json:"attempts" gorm:"not null;default:0"`
+	LastError  string     `json:"last_error,omitempty" gorm:"type:text"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+func (Job) TableName() string {
+	return "jobs"
+}
@@ -2,26 +2,43 @@ package entities
 
 import "time"
 
+// Image ve video kayıtlarının ortak görünümü; tablo değildir, images/videos satırından ve
+// media_jobs kayıtlarından oluşturulur. Varyantlar kendi tablolarından ayrıca okunur
 type Media struct {
 	MediaID      string
+	OwnerID      string
+	Type         string // image, video
 	Filename     string
-	FileType     string
+	FileType     string // MIME tipi
 	FileSize     int64
 	Status       string
 	OriginalPath string
+	SHA256       string
 	Metadata     Metadata
 	Jobs         []*MediaJob
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
 }
 
+// Media üzerinde çalışan her işlem (image varyantı, video resize) için kayıt.
+// Durum: processing -> completed/failed; başarılı işin çıktısı OutputPath'tedir
 type MediaJob struct {
-	JobID      string
-	MediaID    string
-	Type       string
-	Status     string
-	Params     map[string]string
+	JobID      string            `gorm:"primaryKey;type:varchar(36)"`
+	MediaID    string            `gorm:"type:varchar(36);not null;index"`
+	MediaType  string            `gorm:"type:varchar(10);not null"`
+	OwnerID    string            `gorm:"type:varchar(255)"`
+	Type       string            `gorm:"type:varchar(20);not null"`
+	Status     string            `gorm:"type:varchar(20);not null"`
+	Params     map[string]string `gorm:"type:jsonb;serializer:json"`
 	OutputPath string
+	Error      string `gorm:"type:text"`
 	CreatedAt  time.Time
 	UpdatedAt  time.Time
+	FinishedAt *time.Time
+}
+
+func (MediaJob) TableName() string {
+	return "media_jobs"
 }
 
 type Metadata struct {
@@ -0,0 +1,19 @@
+package entities
+
+import "time"
+
+// Postgres kuyruk backend'inin mesajı: worker'lar SELECT ... FOR UPDATE SKIP LOCKED ile birbirini beklemeden iş alır
+type QueueMessage struct {
+	ID          int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Queue       string     `json:"queue" gorm:"type:varchar(100);not null;index:idx_queue_messages_available,priority:1"`
+	Body        []byte     `json:"body" gorm:"type:bytea;not null"`
+	Attempts    int        `json:"attempts" gorm:"not null;default:0"`
+	AvailableAt time.Time  `json:"available_at" gorm:"not null;index:idx_queue_messages_available,priority:2"`
+	LockedBy    string     `json:"locked_by" gorm:"type:varchar(255)"`
+	LockedUntil *time.Time `json:"locked_until"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func (QueueMessage) TableName() string {
+	return "queue_messages"
+}
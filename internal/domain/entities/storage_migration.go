@@ -0,0 +1,35 @@
+package entities
+
+import "time"
+
+// Bir storage'dan diğerine yapılan taşıma; aynı ID ile tekrar çalıştırılan taşıma kaldığı yerden devam eder
+type StorageMigration struct {
+	ID          string     `json:"id" gorm:"primaryKey;type:varchar(100)"`
+	Source      string     `json:"source" gorm:"type:varchar(500);not null"`
+	Destination string     `json:"destination" gorm:"type:varchar(500);not null"`
+	Status      string     `json:"status" gorm:"type:varchar(20);not null"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
+
+func (StorageMigration) TableName() string {
+	return "storage_migrations"
+}
+
+// Taşınacak tek bir dosya; aynı dosyaya referans veren tüm kayıtlar (images, media_variants, videos, blobs) birlikte güncellenir
+type StorageMigrationItem struct {
+	MigrationID     string    `json:"migration_id" gorm:"primaryKey;type:varchar(100)"`
+	SourcePath      string    `json:"source_path" gorm:"primaryKey;type:varchar(500)"`
+	DestinationPath string    `json:"destination_path,omitempty" gorm:"type:varchar(500)"`
+	SHA256          string    `json:"sha256,omitempty" gorm:"column:sha256;type:varchar(64)"`
+	Size            int64     `json:"size"`
+	Status          string    `json:"status" gorm:"type:varchar(20);not null"`
+	Attempts        int       `json:"attempts" gorm:"not null;default:0"`
+	LastError       string    `json:"last_error,omitempty" gorm:"type:text"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+func (StorageMigrationItem) TableName() string {
+	return "storage_migration_items"
+}
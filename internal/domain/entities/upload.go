@@ -1,27 +1,53 @@
 package entities
 
-import "time"
+import (
+	"time"
+
+	fl "file-uploader/pkg/file"
+)
 
 // Upload represents a file upload session
 type Upload struct {
-	ID          string    `json:"id"`
-	Filename    string    `json:"filename"`
+	ID          string    `json:"id" gorm:"primaryKey;type:varchar(255)"`
+	OwnerID     string    `json:"owner_id" gorm:"type:varchar(255);index"` // oturumu açan tenant
+	Filename    string    `json:"filename" gorm:"type:varchar(255);not null"`
+	TotalSize   int64     `json:"total_size"`
+	ChunkSize   int64     `json:"chunk_size"`
 	TotalChunks int       `json:"total_chunks"`
-	Status      string    `json:"status"` // "uploading", "completed", "failed"
+	MimeType    string    `json:"mime_type" gorm:"type:varchar(100)"`
+	FileHash    string    `json:"file_hash,omitempty" gorm:"type:varchar(64)"` // beklenen SHA-256 (opsiyonel)
+	FileMD5     string    `json:"file_md5,omitempty" gorm:"column:file_md5;type:varchar(32)"`
+	FileCRC32C  string    `json:"file_crc32c,omitempty" gorm:"column:file_crc32c;type:varchar(8)"`
+	Status      string    `json:"status" gorm:"type:varchar(20)"` // "in_progress", "merging", "completed", "failed", "cancelled", "expired"
+	ExpiresAt   time.Time `json:"expires_at"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+func (Upload) TableName() string {
+	return "upload_sessions"
+}
+
+// Merge sırasında doğrulanacak özetler
+func (u *Upload) ExpectedDigest() fl.Digest {
+	return fl.Digest{SHA256: u.FileHash, MD5: u.FileMD5, CRC32C: u.FileCRC32C}
+}
+
 // UploadChunk represents a single chunk of a file upload
 type UploadChunk struct {
-	UploadID   string    `json:"upload_id"`
-	ChunkIndex int       `json:"chunk_index"`
-	Filename   string    `json:"filename"`
+	UploadID   string    `json:"upload_id" gorm:"primaryKey;type:varchar(255)"`
+	ChunkIndex int       `json:"chunk_index" gorm:"primaryKey"`
+	Filename   string    `json:"filename" gorm:"type:varchar(255)"`
 	Size       int64     `json:"size"`
-	Hash       string    `json:"hash,omitempty"`
+	Hash       string    `json:"hash,omitempty" gorm:"type:varchar(64)"`
+	ETag       string    `json:"etag,omitempty" gorm:"column:etag;type:varchar(32)"` // S3 multipart part ETag (MD5 hex)
 	CreatedAt  time.Time `json:"created_at"`
 }
 
+func (UploadChunk) TableName() string {
+	return "upload_chunks"
+}
+
 // UploadStatus represents the current status of an upload
 type UploadStatus struct {
 	UploadID       string `json:"upload_id"`
@@ -0,0 +1,27 @@
+package entities
+
+import "time"
+
+// Tenant'ın kategori (staging/original/variant) ve medya tipi başına kullandığı byte sayacı
+type StorageUsage struct {
+	OwnerID   string    `json:"owner_id" gorm:"primaryKey;type:varchar(255)"`
+	Category  string    `json:"category" gorm:"primaryKey;type:varchar(20)"`
+	MediaType string    `json:"media_type" gorm:"primaryKey;type:varchar(20)"`
+	Bytes     int64     `json:"bytes" gorm:"not null;default:0"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (StorageUsage) TableName() string {
+	return "storage_usage"
+}
+
+// Kayıt yoksa QUOTA_DEFAULT_BYTES uygulanır; MaxBytes 0 ise sınırsız
+type TenantQuota struct {
+	OwnerID   string    `json:"owner_id" gorm:"primaryKey;type:varchar(255)"`
+	MaxBytes  int64     `json:"max_bytes" gorm:"not null"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (TenantQuota) TableName() string {
+	return "tenant_quotas"
+}
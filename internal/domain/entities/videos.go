@@ -4,16 +4,20 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Video struct {
 	VideoID      uuid.UUID `gorm:"type:uuid;primaryKey"` // DB’de UUID tipinde
+	OwnerID      string    `gorm:"type:varchar(255);index"`
 	OriginalName string    `gorm:"type:varchar(255);not null"`
 	FileType     string    `gorm:"type:varchar(50)"`
 	FilePath     string    `gorm:"type:varchar(500);not null"`
+	SHA256       string    `gorm:"column:sha256;type:varchar(64)"` // merge sırasında doğrulanan özet
 	Status       string    `gorm:"type:varchar(50)"`
 	Height       int64
 	Width        int64
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
+	DeletedAt    gorm.DeletedAt `gorm:"index"` // soft delete
 }
@@ -0,0 +1,79 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Webhook ile dışarıya bildirilen yaşam döngüsü olayları
+const (
+	EventUploadCompleted = "upload.completed"
+	EventUploadFailed    = "upload.failed"
+	EventMediaProcessed  = "media.processed"
+	EventVideoResized    = "video.resized"
+)
+
+// Events virgülle ayrılmış olay listesidir, "*" tüm olaylara abone olur
+type WebhookSubscription struct {
+	ID          string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	URL         string    `json:"url" gorm:"type:varchar(1000);not null"`
+	Secret      string    `json:"-" gorm:"type:varchar(255);not null"`
+	Events      string    `json:"events" gorm:"type:text;not null"`
+	Description string    `json:"description" gorm:"type:varchar(255)"`
+	Active      bool      `json:"active" gorm:"not null;default:true"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// Transactional outbox: olay, durumu değiştiren işlemle aynı transaction'da yazılır,
+// worker'daki dispatcher abonelere teslimat kayıtlarına dönüştürür
+type OutboxEvent struct {
+	ID           uint64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	EventType    string     `json:"event_type" gorm:"type:varchar(50);not null"`
+	AggregateID  string     `json:"aggregate_id" gorm:"type:varchar(255);not null"` // upload id
+	Payload      string     `json:"payload" gorm:"type:text;not null"`              // webhook gövdesindeki data alanı (JSON)
+	CreatedAt    time.Time  `json:"created_at"`
+	DispatchedAt *time.Time `json:"dispatched_at"`
+}
+
+func (OutboxEvent) TableName() string {
+	return "outbox_events"
+}
+
+// data, webhook gövdesinin data alanına JSON olarak yazılır
+func NewOutboxEvent(eventType, aggregateID string, data interface{}) (*OutboxEvent, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return &OutboxEvent{
+		EventType:   eventType,
+		AggregateID: aggregateID,
+		Payload:     string(payload),
+	}, nil
+}
+
+// Bir olayın bir aboneliğe teslimatı; teslim edilemezse üstel bekleme ile tekrar denenir
+type WebhookDelivery struct {
+	ID             uint64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	SubscriptionID string     `json:"subscription_id" gorm:"type:varchar(36);not null;index"`
+	EventID        uint64     `json:"event_id" gorm:"not null"`
+	EventType      string     `json:"event_type" gorm:"type:varchar(50);not null"`
+	Body           string     `json:"-" gorm:"type:text;not null"` // imzalanan ve gönderilen gövde
+	Status         string     `json:"status" gorm:"type:varchar(20);not null"`
+	Attempts       int        `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at" gorm:"not null"`
+	LastStatusCode int        `json:"last_status_code"`
+	LastError      string     `json:"last_error,omitempty" gorm:"type:text"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
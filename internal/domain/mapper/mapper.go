@@ -11,6 +11,7 @@ func MediaToDTO(m *entities.Image) dto.ImageDTO {
 		OriginalName: m.OriginalName,
 		FileType:     m.FileType,
 		FilePath:     m.FilePath,
+		SHA256:       m.SHA256,
 		Status:       m.Status,
 		CreatedAt:    m.CreatedAt,
 		UpdatedAt:    m.UpdatedAt,
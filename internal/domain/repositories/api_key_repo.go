@@ -0,0 +1,13 @@
+package repositories
+
+import "file-uploader/internal/domain/entities"
+
+type APIKeyRepository interface {
+	Create(key *entities.APIKey) error
+	// İptal edilmemiş anahtarı özetine göre bulur
+	GetActiveByHash(keyHash string) (*entities.APIKey, error)
+	// ownerID boşsa tüm anahtarlar listelenir
+	List(ownerID string) ([]entities.APIKey, error)
+	Revoke(id string) error
+	TouchLastUsed(id string) error
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import "file-uploader/internal/domain/entities"
+
+//* Merge edilen dosyalar içerik adresli (SHA-256) tutulur, referans sayısı sıfıra düşmeden dosya silinmez
+
+type BlobRepository interface {
+	// Blob yoksa ref_count=1 ile oluşturur (created=true), varsa ref_count'u artırıp mevcut blob'u döner
+	Acquire(sha256, filePath string, size int64) (blob *entities.Blob, created bool, err error)
+	// Blob yoksa not_found döner
+	Get(sha256 string) (*entities.Blob, error)
+	SetOrigin(sha256, originID string) error
+	// Blob'un dosyası kaybolduysa yeni merge edilen kopya blob olur, varyantlar yeniden üretileceği için origin sıfırlanır
+	Relocate(sha256, filePath string) error
+	// ref_count'u azaltır, sıfıra düşerse kaydı siler; dönen blob'daki RefCount kalan referans sayısıdır
+	Release(sha256 string) (*entities.Blob, error)
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import "file-uploader/internal/domain/entities"
+
+//* Şifreli dosyaların sarılmış veri anahtarları; kaydı olmayan dosyalar şifresiz kabul edilir
+type EncryptedObjectRepository interface {
+	// Kayıt yoksa not_found döner
+	Get(backend, location string) (*entities.EncryptedObject, error)
+	// Aynı konumdaki eski kayıt (üzerine yazılan dosya) değiştirilir
+	Save(object *entities.EncryptedObject) error
+	Delete(backend, location string) error
+
+	// keyID dışındaki anahtarlarla sarılmış kayıtlar, (backend, location) sırasıyla after'dan sonrası
+	ListWrappedWithOtherKey(keyID, afterBackend, afterLocation string, limit int) ([]entities.EncryptedObject, error)
+	// Kayıt bu arada başka bir rotasyonla değiştiyse false döner
+	Rewrap(backend, location, oldKeyID, newKeyID string, wrappedKey []byte) (bool, error)
+	// Key ID başına kayıt sayısı; eski anahtar sıfırlanınca kaldırılabilir
+	CountByKey() (map[string]int64, error)
+}
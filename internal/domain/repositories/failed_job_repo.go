@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"time"
+
+	"file-uploader/internal/domain/entities"
+)
+
+// Dead-letter kayıtlarını listeleme/toplu işlem filtresi, boş alanlar filtrelenmez
+type FailedJobFilter struct {
+	IDs           []uint
+	JobType       string
+	Status        string
+	CreatedBefore time.Time
+	CreatedAfter  time.Time
+}
+
+type FailedJobRepository interface {
+	List(filter FailedJobFilter, offset, limit int) ([]entities.FailedJob, int64, error)
+	GetByID(id uint) (*entities.FailedJob, error)
+	Find(filter FailedJobFilter) ([]entities.FailedJob, error)
+	UpdateStatus(ids []uint, status string) error
+	Delete(ids []uint) (int64, error)
+}
+
+type AuditLogRepository interface {
+	Record(entry *entities.AdminAuditLog) error
+	List(action string, offset, limit int) ([]entities.AdminAuditLog, int64, error)
+}
@@ -0,0 +1,13 @@
+package repositories
+
+import "file-uploader/internal/domain/entities"
+
+type JobRepository interface {
+	// Kayıt yoksa oluşturur, varsa (requeue/retry) queued durumuna döndürür
+	Upsert(job *entities.Job) error
+	// Durum geçişi: running'de deneme sayısı artar, succeeded/failed'da bitiş zamanı yazılır
+	Transition(id, status, lastError string) error
+	// ownerID verilirse yalnızca tenant'ın upload'larına ait job'lar döner
+	GetByID(ownerID, id string) (*entities.Job, error)
+	ListByUpload(ownerID, uploadID string) ([]entities.Job, error)
+}
@@ -3,16 +3,52 @@ package repositories
 import (
 	"file-uploader/internal/domain/dto"
 	"file-uploader/internal/domain/entities"
+	"time"
 )
 
 //* Single Responsibility Principle (SRP): Her repo sadece bir tabloya odaklanıyor, yönetimi ve test edilmesi kolay
+//* ownerID parametresi sorguyu tenant'a göre kapsamlar, boş ownerID worker ve sistem işlemleri içindir
+
+// Media/video listeleme filtresi, boş alanlar filtrelenmez. Sayfalama keyset ile yapılır:
+// After verilirse sıralamada (Sort, id) ikilisi After'dan sonra gelen kayıtlar döner
+type MediaListFilter struct {
+	OwnerID       string
+	Status        string
+	FileType      string // "image/*" biçiminde verilirse önek eşleşmesi
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	NamePrefix    string
+	Sort          string // created_at, updated_at ya da original_name
+	Desc          bool
+	After         *MediaCursor
+	Limit         int
+}
+
+// Bir önceki sayfanın son kaydı; zamanlar RFC3339Nano ile tutulur
+type MediaCursor struct {
+	Value string
+	ID    string
+}
 
 type MediaRepository interface {
 	CreateMedia(media *dto.ImageDTO) error
-	GetMediaByID(id string) (*dto.ImageDTO, error)
+	GetMediaByID(ownerID, id string) (*dto.ImageDTO, error)
 	UpdateMediaStatus(id string, status string) error
-	GetAllMedia() ([]*dto.ImageDTO, error)
-	GetMediaByStatus(status string) ([]*dto.ImageDTO, error)
+	GetAllMedia(ownerID string) ([]*dto.ImageDTO, error)
+	GetMediaByStatus(ownerID, status string) ([]*dto.ImageDTO, error)
+	// Toplam, cursor'dan bağımsız olarak filtreye uyan kayıt sayısıdır
+	ListMedia(filter MediaListFilter) ([]*dto.ImageDTO, int64, error)
+	// Kalıcı siler (çöp kutusu temizliği)
+	DeleteMedia(ownerID, id string) error
+
+	// Çöp kutusu: soft delete edilen kayıt dosyalarıyla birlikte retention süresi boyunca tutulur
+	SoftDeleteMedia(ownerID, id string) error
+	// Çöp kutusunda değilse not_found döner
+	RestoreMedia(ownerID, id string) error
+	GetDeletedMedia(ownerID string) ([]*dto.ImageDTO, error)
+	GetMediaDeletedBefore(before time.Time, limit int) ([]*dto.ImageDTO, error)
+	// Çöp kutusundakiler dahil; dedup ile bağlanan kayıt silinmiş origin'in dosyalarını paylaşabilir
+	GetMediaByIDWithDeleted(id string) (*dto.ImageDTO, error)
 }
 
 type MediaVariantRepository interface {
@@ -22,6 +58,14 @@ type MediaVariantRepository interface {
 	GetVariantByID(id string) (*dto.MediaVariant, error)
 	UpdateVariant(variant *dto.MediaVariant) error
 	DeleteVariant(id string) error
+	GetVariantsByMediaID(ownerID, mediaID string) ([]*dto.MediaVariant, error)
+	// Kalıcı siler (çöp kutusu temizliği)
+	DeleteVariantsByMediaID(ownerID, mediaID string) error
+	// Çöp kutusundakiler dahil sayılır, dosyaları kalıcı silinene kadar storage'da durur
+	CountByFilePath(filePath string) (int64, error)
+	SoftDeleteVariantsByMediaID(ownerID, mediaID string) error
+	RestoreVariantsByMediaID(ownerID, mediaID string) error
+	GetVariantsByMediaIDWithDeleted(mediaID string) ([]*dto.MediaVariant, error)
 }
 
 type MediaSizeRepository interface {
@@ -34,8 +78,30 @@ type MediaSizeRepository interface {
 
 type VideoRepository interface {
 	CreateVideo(video *dto.VideoDTO) error
-	GetVideoByID(id string) (*dto.VideoDTO, error)
+	GetVideoByID(ownerID, id string) (*dto.VideoDTO, error)
+	ListVideos(filter MediaListFilter) ([]*dto.VideoDTO, int64, error)
 	ResizeWidth(video *entities.Video) error
 	ResizeHeight(video *entities.Video) error
 	ResizeVideo(video *entities.Video) error
+	// Kalıcı siler (çöp kutusu temizliği)
+	DeleteVideo(ownerID, id string) error
+	// Çöp kutusundakiler dahil sayılır
+	CountByFilePath(filePath string) (int64, error)
+
+	SoftDeleteVideo(ownerID, id string) error
+	// Çöp kutusunda değilse not_found döner
+	RestoreVideo(ownerID, id string) error
+	GetDeletedVideos(ownerID string) ([]*dto.VideoDTO, error)
+	GetVideosDeletedBefore(before time.Time, limit int) ([]*dto.VideoDTO, error)
+	GetVideoByIDWithDeleted(id string) (*dto.VideoDTO, error)
+}
+
+// Media işlerinin geçmişi (media_jobs); kayıtlar media kalıcı silinene kadar tutulur
+type MediaJobRepository interface {
+	Create(job *entities.MediaJob) error
+	// status completed ya da failed olur, bitiş zamanı yazılır
+	Finish(jobID, status, outputPath, errMessage string) error
+	// En eski iş önce
+	ListByMediaID(mediaID string) ([]*entities.MediaJob, error)
+	DeleteByMediaID(mediaID string) error
 }
@@ -0,0 +1,30 @@
+package repositories
+
+import "file-uploader/internal/domain/entities"
+
+// Taşıma sayıları (storagectl status)
+type StorageMigrationSummary struct {
+	Pending int64
+	Done    int64
+	Failed  int64
+	Bytes   int64 // taşınmış dosyaların toplam boyutu
+}
+
+//* Taşıma ilerlemesi Postgres'te tutulur; yarıda kalan taşıma aynı ID ile kaldığı yerden devam eder
+type StorageMigrationRepository interface {
+	// Kayıt yoksa not_found döner
+	GetMigration(id string) (*entities.StorageMigration, error)
+	CreateMigration(migration *entities.StorageMigration) error
+	UpdateMigrationStatus(id, status string) error
+
+	// images (silinmişler dahil), media_variants, videos ve blobs'un referans verdiği tekil dosya yolları
+	ListReferencedPaths() ([]string, error)
+	// Zaten eklenmiş yollar atlanır
+	AddItems(migrationID string, paths []string) error
+	// retryFailed ise başarısız olanlar da döner
+	ListPendingItems(migrationID string, retryFailed bool) ([]entities.StorageMigrationItem, error)
+	// Dosyaya referans veren tüm satırların file_path'i ve item'ın durumu tek transaction'da güncellenir
+	SwitchPath(item *entities.StorageMigrationItem) error
+	MarkItemFailed(migrationID, sourcePath, lastError string) error
+	Summary(migrationID string) (*StorageMigrationSummary, error)
+}
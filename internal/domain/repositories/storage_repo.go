@@ -1,7 +1,12 @@
 package repositories
 
-import "mime/multipart"
+import (
+	"mime/multipart"
+	"os"
+)
 
+// Dosyalar backend'den bağımsız mantıksal key'lerle adreslenir (bkz. file.OriginalKey, file.VariantKey).
+// İşlenecek dosyalar LocalPath ile okunur, üretilen dosyalar Store, gelen dosyalar Save ile yazılır
 type StorageStrategy interface {
 	UploadImage(file multipart.File, metadata map[string]string) (string, error)
 	CopyFile(sourcePath, destinationPath string) error
@@ -11,4 +16,14 @@ type StorageStrategy interface {
 	Delete(fileID string) error
 	Download(fileID string) (multipart.File, error)
 	FileExists(filePath string) bool
+
+	// Gelen dosyayı key altına yazar ve saklandığı konumu döner
+	Save(file multipart.File, key string) (string, error)
+	// Yerel dosyayı key altına taşır ve saklandığı konumu döner; yerel dosya artık kullanılmamalıdır
+	Store(localPath, key string) (string, error)
+	// Dosyanın okunabilir yerel yolunu döner; S3'te geçici dosyaya indirilir, iş bitince cleanup çağrılmalıdır
+	LocalPath(location string) (string, func(), error)
+	Size(location string) (int64, error)
+	// Dosya açılmadan boyut ve değişiklik zamanı döner; şifreli dosyalarda boyut şifresizdir
+	Stat(location string) (os.FileInfo, error)
 }
@@ -1,22 +1,35 @@
 package repositories
 
 import (
+	"io"
 	"mime/multipart"
+	"time"
+
+	"file-uploader/internal/domain/entities"
+	fl "file-uploader/pkg/file"
 )
 
 type FileUploadRepository interface {
 	//Chunk işlemleri
 	SaveChunk(uploadID, filename string, chunkIndex int, file multipart.File) error
+	SaveChunkBytes(uploadID, filename string, chunkIndex int, data []byte) error
 	ChunkExists(uploadID, filename string, chunkIndex int) bool
-	SetUploadedChunks(uploadID, filename string, merged int) error
-	GetUploadedChunks(uploadID, filename string) (int, bool)
+	DeleteChunk(uploadID, filename string, chunkIndex int) error
+	// Staging: server chunk'ı diske stream eder, worker doğrulayıp asıl konumuna taşır
+	StageChunk(uploadID, filename string, chunkIndex int, src io.Reader) (stagingPath, hash string, size int64, err error)
+	CommitStagedChunk(uploadID, filename string, chunkIndex int, stagingPath string) error
+	DiscardStagedChunk(uploadID, stagingPath string) error
 	// Dosya birleştirme / hash doğrulama / temizlik
-	MergeChunks(uploadID, filename string, totalChunks int) (string, error)
+	MergeChunks(uploadID, filename string, totalChunks int, expected fl.Digest) (string, fl.Digest, error)
 	SaveFailedUpload(string, string, string, string, []byte) error
 	GetFailedUpload(uploadID string) string
 	DeleteFailedUpload(uploadID string) error
-	RetryMerge(uploadID, filename string) (string, int, error)
+	// totalChunks'taki her chunk bulunmalı; boşluk ya da okunamayan chunk missing_chunk döner
+	RetryMerge(uploadID, filename string, totalChunks int, expected fl.Digest) (string, int, fl.Digest, error)
 	UpdateRetryStatus(uploadID, status string) error
+	// Chunk havuzu: başarıyla merge edilen chunk'lar aynı tenant'ın upload'ları arasında paylaşılır
+	FindPooledChunks(ownerID string, hashes []string) (map[string]*entities.PooledChunk, error)
+	PurgeChunkPool(maxAge time.Duration) (int, error)
 	CleanupTempFiles(uploadID string) error
 	UploadsDir() string
 	TempDir() string
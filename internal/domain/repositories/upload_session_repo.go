@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"file-uploader/internal/domain/entities"
+	fl "file-uploader/pkg/file"
+)
+
+//* Upload oturumları server ve worker arasında paylaşılır, bu yüzden process içi map yerine kalıcı bir store kullanılıyor
+//* ownerID alan sorgular tenant'a göre kapsamlanır; boş ownerID worker ve sistem işlemleri içindir
+
+type UploadSessionStore interface {
+	// Oturum işlemleri
+	CreateSession(session *entities.Upload) error
+	GetSession(ownerID, uploadID string) (*entities.Upload, error)
+	UpdateStatus(uploadID, status string) error
+	// Durum ve outbox olayları aynı transaction'da yazılır, olay durum değişikliğinden ayrı kaybolmaz
+	UpdateStatusWithEvents(uploadID, status string, events ...*entities.OutboxEvent) error
+	SetTotalChunks(uploadID string, totalChunks int) error
+	// Boyutu başta bilinmeyen upload'larda (S3 multipart) tamamlanınca yazılır
+	SetTotalSize(uploadID string, totalSize int64) error
+	SetExpectedDigest(uploadID string, digest fl.Digest) error
+	// Chunk kayıtları
+	RecordChunk(chunk *entities.UploadChunk) error
+	GetChunks(uploadID string) ([]*entities.UploadChunk, error)
+	DeleteChunks(uploadID string, chunkIndexes []int) error
+	// fn, oturum satırı kilitliyken transaction'a bağlı store ile çalışır
+	LockSession(uploadID string, fn func(store UploadSessionStore) error) error
+}
@@ -0,0 +1,32 @@
+package repositories
+
+import "file-uploader/internal/domain/entities"
+
+// Reconcile sırasında boyutu diskten okunacak dosya
+type StoredFile struct {
+	OwnerID   string
+	Category  string
+	MediaType string
+	FilePath  string
+}
+
+//* Sayaçlar upload/silme akışlarında artırılıp azaltılır, periyodik reconcile ile diskteki gerçek boyutlara eşitlenir
+type UsageRepository interface {
+	// delta negatif olabilir, sayaç sıfırın altına düşmez
+	Add(ownerID, category, mediaType string, delta int64) error
+	// Tenant'ın toplam kullanımına delta eklendiğinde limit aşılmıyorsa delta tek adımda eklenir, aşılıyorsa
+	// sayaç değişmez ve false döner. used, delta eklenmeden önceki toplamdır
+	Reserve(ownerID, category, mediaType string, delta, limit int64) (reserved bool, used int64, err error)
+	ListByOwner(ownerID string) ([]entities.StorageUsage, error)
+	// Reconcile sonucu tüm sayaçların yerine yazılır
+	ReplaceAll(usage []entities.StorageUsage) error
+
+	// Kayıt yoksa not_found döner
+	GetQuota(ownerID string) (*entities.TenantQuota, error)
+	SetQuota(quota *entities.TenantQuota) error
+
+	// DB kayıtlarından hesaplanan kullanım: merge bekleyen chunk'lar ve medya dışı orijinaller
+	SumRecorded() ([]entities.StorageUsage, error)
+	// Boyutu diskten okunacak image/video orijinalleri ve varyantları
+	ListStoredFiles() ([]StoredFile, error)
+}
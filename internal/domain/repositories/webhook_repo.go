@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"time"
+
+	"file-uploader/internal/domain/entities"
+)
+
+type WebhookRepository interface {
+	// Abonelikler
+	CreateSubscription(sub *entities.WebhookSubscription) error
+	GetSubscription(id string) (*entities.WebhookSubscription, error)
+	ListSubscriptions() ([]entities.WebhookSubscription, error)
+	UpdateSubscription(sub *entities.WebhookSubscription) error
+	DeleteSubscription(id string) error
+	// Teslimat logu
+	ListDeliveries(subscriptionID, status string, offset, limit int) ([]entities.WebhookDelivery, int64, error)
+}
+
+//* Outbox olayları abonelere dağıtılır, teslimatlar birden fazla worker'da SKIP LOCKED ile paylaşılır
+
+type OutboxRepository interface {
+	Append(events ...*entities.OutboxEvent) error
+	// Dağıtılmamış olaylar için eşleşen aktif aboneliklere teslimat kaydı açar, dağıtılan olay sayısını döner
+	FanOut(limit int) (int, error)
+	// Zamanı gelen teslimatları lease süresince başka worker'ın alamayacağı şekilde ayırır
+	ClaimDeliveries(limit int, lease time.Duration) ([]entities.WebhookDelivery, error)
+	MarkDelivered(id uint64, statusCode int) error
+	// nextAttempt nil ise teslimat kalıcı olarak başarısız sayılır
+	MarkAttemptFailed(id uint64, statusCode int, lastError string, nextAttempt *time.Time) error
+}
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"file-uploader/internal/domain/repositories"
+	"file-uploader/pkg/config"
+	fe "file-uploader/pkg/errors"
+)
+
+// last_used_at her istekte değil, bu aralıkta bir güncellenir
+const apiKeyTouchInterval = time.Minute
+
+type Authenticator struct {
+	enabled bool
+	keys    repositories.APIKeyRepository
+	jwt     *JWTVerifier
+}
+
+func NewAuthenticator(cfg config.AuthConfig, keys repositories.APIKeyRepository) (*Authenticator, error) {
+	verifier, err := NewJWTVerifier(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Authenticator{
+		enabled: cfg.Enabled,
+		keys:    keys,
+		jwt:     verifier,
+	}, nil
+}
+
+func (a *Authenticator) Enabled() bool {
+	return a.enabled
+}
+
+// Token "fu_" önekliyse API key, değilse JWT olarak doğrulanır
+func (a *Authenticator) Authenticate(token string) (*Principal, error) {
+	if token == "" {
+		return nil, fe.ErrUnauthorized(fmt.Errorf("API key ya da bearer token gerekli"))
+	}
+	if IsAPIKey(token) {
+		return a.authenticateAPIKey(token)
+	}
+	if a.jwt == nil {
+		return nil, fe.ErrUnauthorized(fmt.Errorf("JWT doğrulaması yapılandırılmamış"))
+	}
+	principal, err := a.jwt.Verify(token)
+	if err != nil {
+		return nil, fe.ErrUnauthorized(err)
+	}
+	return principal, nil
+}
+
+func (a *Authenticator) authenticateAPIKey(token string) (*Principal, error) {
+	key, err := a.keys.GetActiveByHash(HashAPIKey(token))
+	if err != nil {
+		if uploadErr, ok := err.(*fe.UploadError); ok && uploadErr.Code == "not_found" {
+			return nil, fe.ErrUnauthorized(fmt.Errorf("geçersiz ya da iptal edilmiş API key"))
+		}
+		return nil, fe.ErrInternal(err)
+	}
+
+	if key.LastUsedAt == nil || time.Since(*key.LastUsedAt) > apiKeyTouchInterval {
+		if err := a.keys.TouchLastUsed(key.ID); err != nil {
+			log.Printf("API key son kullanım zamanı güncellenemedi %s: %v", key.ID, err)
+		}
+	}
+
+	return &Principal{
+		Subject: key.ID,
+		OwnerID: key.OwnerID,
+		Method:  MethodAPIKey,
+		Admin:   key.Admin,
+	}, nil
+}
@@ -0,0 +1,161 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	"file-uploader/pkg/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// HS256 (paylaşılan secret) ve RS256 (yerel JWKS dosyasındaki public key'ler) token'larını doğrular
+type JWTVerifier struct {
+	secret      []byte
+	keys        map[string]*rsa.PublicKey // kid -> key
+	methods     []string
+	issuer      string
+	audience    string
+	tenantClaim string
+}
+
+// Secret ya da JWKS dosyası verilmemişse nil döner, bu durumda yalnızca API key kabul edilir
+func NewJWTVerifier(cfg config.AuthConfig) (*JWTVerifier, error) {
+	if cfg.JWTSecret == "" && cfg.JWKSFile == "" {
+		return nil, nil
+	}
+
+	v := &JWTVerifier{
+		issuer:      cfg.Issuer,
+		audience:    cfg.Audience,
+		tenantClaim: cfg.TenantClaim,
+	}
+	if cfg.JWTSecret != "" {
+		v.secret = []byte(cfg.JWTSecret)
+		v.methods = append(v.methods, jwt.SigningMethodHS256.Alg())
+	}
+	if cfg.JWKSFile != "" {
+		keys, err := loadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		v.keys = keys
+		v.methods = append(v.methods, jwt.SigningMethodRS256.Alg())
+	}
+	return v, nil
+}
+
+type jwks struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+func loadJWKS(path string) (map[string]*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("JWKS dosyası okunamadı: %w", err)
+	}
+	var set jwks
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("JWKS dosyası çözümlenemedi: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("JWKS anahtarı %q için n geçersiz: %w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("JWKS anahtarı %q için e geçersiz: %w", k.Kid, err)
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("JWKS dosyasında RSA imza anahtarı yok: %s", path)
+	}
+	return keys, nil
+}
+
+func (v *JWTVerifier) keyFunc(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		return v.secret, nil
+	case *jwt.SigningMethodRSA:
+		kid, _ := token.Header["kid"].(string)
+		if key, ok := v.keys[kid]; ok {
+			return key, nil
+		}
+		// kid verilmemişse ve dosyada tek anahtar varsa o kullanılır
+		if kid == "" && len(v.keys) == 1 {
+			for _, key := range v.keys {
+				return key, nil
+			}
+		}
+		return nil, fmt.Errorf("bilinmeyen kid: %q", kid)
+	}
+	return nil, fmt.Errorf("desteklenmeyen algoritma: %s", token.Method.Alg())
+}
+
+func (v *JWTVerifier) Verify(tokenString string) (*Principal, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(v.methods),
+		jwt.WithExpirationRequired(),
+	}
+	if v.issuer != "" {
+		options = append(options, jwt.WithIssuer(v.issuer))
+	}
+	if v.audience != "" {
+		options = append(options, jwt.WithAudience(v.audience))
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(tokenString, claims, v.keyFunc, options...); err != nil {
+		return nil, err
+	}
+
+	subject, _ := claims.GetSubject()
+	ownerID, _ := claims[v.tenantClaim].(string)
+	if ownerID == "" {
+		ownerID = subject
+	}
+	if ownerID == "" {
+		return nil, fmt.Errorf("token'da %s ya da sub claim'i yok", v.tenantClaim)
+	}
+
+	return &Principal{
+		Subject: subject,
+		OwnerID: ownerID,
+		Method:  MethodJWT,
+		Admin:   hasRole(claims["roles"], "admin"),
+	}, nil
+}
+
+func hasRole(value interface{}, role string) bool {
+	roles, ok := value.([]interface{})
+	if !ok {
+		return false
+	}
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
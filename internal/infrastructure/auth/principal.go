@@ -0,0 +1,16 @@
+package auth
+
+const (
+	MethodAPIKey    = "api_key"
+	MethodJWT       = "jwt"
+	MethodAnonymous = "anonymous"  // AUTH_ENABLED=false
+	MethodSignedURL = "signed_url" // HMAC imzalı URL, yalnızca imzalanan method + path için geçerli
+)
+
+// İsteği yapan kimlik; OwnerID tüm tenant kapsamlı sorgularda kullanılır
+type Principal struct {
+	Subject string // API key ID'si ya da JWT sub claim'i
+	OwnerID string
+	Method  string
+	Admin   bool
+}
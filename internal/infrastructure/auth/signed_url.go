@@ -0,0 +1,168 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"file-uploader/pkg/config"
+)
+
+// İmzalı URL'lerin query parametreleri; endpoint'lerin kendi form/query alanlarıyla çakışmaması için önekli
+const (
+	SignatureParam   = "sig"
+	KeyIDParam       = "sig_key"
+	ExpiresParam     = "sig_expires"
+	MaxSizeParam     = "sig_max_size"
+	ContentTypeParam = "sig_content_type"
+	OwnerParam       = "sig_owner"
+	UploadIDParam    = "sig_upload"
+	// Download endpoint'inin kendi parametresi; imzaya dahil edildiği için değiştirilemez ya da eklenemez
+	VariantParam = "variant"
+)
+
+// URL'nin yetki verdiği tek istek; imza bu alanların tamamını kapsar
+type SignedURLClaims struct {
+	Method      string
+	Path        string
+	ExpiresAt   time.Time
+	MaxSize     int64  // 0: sınır yok
+	ContentType string // boş: kontrol edilmez
+	OwnerID     string
+	UploadID    string // upload URL'lerinde chunk'ın ait olması gereken oturum
+	Variant     string // download URL'lerinde indirilecek varyant; boş: orijinal
+	KeyID       string
+}
+
+// Birden fazla anahtar aynı anda geçerlidir; imzalama yalnızca aktif anahtarla yapılır
+type URLSigner struct {
+	keys   map[string][]byte
+	active string
+}
+
+// Anahtar verilmemişse imzalı URL'ler kapalıdır; aktif anahtar belirtilmezse tek anahtar aktif sayılır
+func NewURLSigner(cfg config.SignedURLConfig) (*URLSigner, error) {
+	s := &URLSigner{
+		keys:   make(map[string][]byte),
+		active: cfg.ActiveKey,
+	}
+	for id, secret := range cfg.Keys {
+		if secret == "" {
+			return nil, fmt.Errorf("SIGNED_URL_KEYS: %s anahtarının secret'ı boş", id)
+		}
+		s.keys[id] = []byte(secret)
+	}
+	if len(s.keys) == 0 {
+		return s, nil
+	}
+	if s.active == "" {
+		if len(s.keys) > 1 {
+			return nil, fmt.Errorf("birden fazla imza anahtarı var, SIGNED_URL_ACTIVE_KEY belirtilmeli")
+		}
+		for id := range s.keys {
+			s.active = id
+		}
+	}
+	if _, ok := s.keys[s.active]; !ok {
+		return nil, fmt.Errorf("SIGNED_URL_ACTIVE_KEY %q, SIGNED_URL_KEYS içinde yok", s.active)
+	}
+	return s, nil
+}
+
+func (s *URLSigner) Enabled() bool {
+	return len(s.keys) > 0
+}
+
+// Claims aktif anahtarla imzalanır, URL'ye eklenecek query parametreleri döner
+func (s *URLSigner) Sign(claims *SignedURLClaims) (url.Values, error) {
+	if !s.Enabled() {
+		return nil, fmt.Errorf("imzalı URL anahtarı yapılandırılmamış (SIGNED_URL_KEYS)")
+	}
+	claims.KeyID = s.active
+	query := url.Values{}
+	query.Set(KeyIDParam, claims.KeyID)
+	query.Set(ExpiresParam, strconv.FormatInt(claims.ExpiresAt.Unix(), 10))
+	if claims.MaxSize > 0 {
+		query.Set(MaxSizeParam, strconv.FormatInt(claims.MaxSize, 10))
+	}
+	if claims.ContentType != "" {
+		query.Set(ContentTypeParam, claims.ContentType)
+	}
+	if claims.OwnerID != "" {
+		query.Set(OwnerParam, claims.OwnerID)
+	}
+	if claims.UploadID != "" {
+		query.Set(UploadIDParam, claims.UploadID)
+	}
+	if claims.Variant != "" {
+		query.Set(VariantParam, claims.Variant)
+	}
+	query.Set(SignatureParam, s.signature(s.keys[s.active], claims))
+	return query, nil
+}
+
+// İmza isteğin gerçek method ve path'i üzerinden doğrulanır; başka bir endpoint'e taşınan URL geçersiz olur
+func (s *URLSigner) Verify(method, path string, query url.Values) (*SignedURLClaims, error) {
+	secret, ok := s.keys[query.Get(KeyIDParam)]
+	if !ok {
+		return nil, fmt.Errorf("bilinmeyen ya da kaldırılmış imza anahtarı: %q", query.Get(KeyIDParam))
+	}
+	expires, err := strconv.ParseInt(query.Get(ExpiresParam), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("geçersiz %s parametresi", ExpiresParam)
+	}
+	var maxSize int64
+	if value := query.Get(MaxSizeParam); value != "" {
+		if maxSize, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return nil, fmt.Errorf("geçersiz %s parametresi", MaxSizeParam)
+		}
+	}
+
+	claims := &SignedURLClaims{
+		Method:      strings.ToUpper(method),
+		Path:        path,
+		ExpiresAt:   time.Unix(expires, 0),
+		MaxSize:     maxSize,
+		ContentType: query.Get(ContentTypeParam),
+		OwnerID:     query.Get(OwnerParam),
+		UploadID:    query.Get(UploadIDParam),
+		Variant:     query.Get(VariantParam),
+		KeyID:       query.Get(KeyIDParam),
+	}
+	expected := s.signature(secret, claims)
+	if !hmac.Equal([]byte(expected), []byte(query.Get(SignatureParam))) {
+		return nil, fmt.Errorf("imza geçersiz")
+	}
+	// Süre imza doğrulandıktan sonra kontrol edilir, expires değiştirilerek uzatılamaz
+	if time.Now().After(claims.ExpiresAt) {
+		return nil, fmt.Errorf("imzalı URL'nin süresi %s tarihinde doldu", claims.ExpiresAt.UTC().Format(time.RFC3339))
+	}
+	return claims, nil
+}
+
+func IsSignedRequest(query url.Values) bool {
+	return query.Get(SignatureParam) != ""
+}
+
+// hex(HMAC-SHA256(secret, METHOD \n path \n expires \n max_size \n content_type \n owner_id \n upload_id \n variant \n key_id))
+func (s *URLSigner) signature(secret []byte, claims *SignedURLClaims) string {
+	canonical := strings.Join([]string{
+		strings.ToUpper(claims.Method),
+		claims.Path,
+		strconv.FormatInt(claims.ExpiresAt.Unix(), 10),
+		strconv.FormatInt(claims.MaxSize, 10),
+		claims.ContentType,
+		claims.OwnerID,
+		claims.UploadID,
+		claims.Variant,
+		claims.KeyID,
+	}, "\n")
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(canonical))
+	return hex.EncodeToString(mac.Sum(nil))
+}
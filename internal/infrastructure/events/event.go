@@ -0,0 +1,62 @@
+package events
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Upload ilerleme olayları; SSE/WebSocket üzerinden client'a iletilir
+const (
+	ChunkReceived  = "chunk_received"
+	MergeStarted   = "merge_started"
+	MergeFinished  = "merge_finished"
+	VariantCreated = "variant_created"
+	Completed      = "completed" // merge sonrası media/video işleme bitti
+	Failed         = "failed"
+)
+
+type Event struct {
+	Type       string    `json:"type"`
+	UploadID   string    `json:"upload_id"`
+	JobID      string    `json:"job_id,omitempty"`
+	Filename   string    `json:"filename,omitempty"`
+	ChunkIndex *int      `json:"chunk_index,omitempty"`
+	Size       int64     `json:"size,omitempty"`
+	MediaID    string    `json:"media_id,omitempty"`
+	Variant    string    `json:"variant,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Retrying   bool      `json:"retrying,omitempty"` // failed olayından sonra otomatik retry planlandı
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+type Subscriber interface {
+	// Upload'ın olaylarını dinler; dönen fonksiyon aboneliği kapatır ve kanalı kapatır
+	Subscribe(ctx context.Context, uploadID string) (<-chan Event, func(), error)
+}
+
+type Bus interface {
+	Publisher
+	Subscriber
+}
+
+// Olay yayını işin akışını bozmamalı: hata loglanır, publisher verilmemişse atlanır
+func Publish(p Publisher, event Event) {
+	if p == nil || event.UploadID == "" {
+		return
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	if err := p.Publish(context.Background(), event); err != nil {
+		log.Printf("Olay yayınlanamadı %s (%s): %v", event.Type, event.UploadID, err)
+	}
+}
+
+func ChunkIndex(idx int) *int {
+	return &idx
+}
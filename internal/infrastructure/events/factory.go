@@ -0,0 +1,27 @@
+package events
+
+import (
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	BackendRedis  = "redis"
+	BackendMemory = "memory"
+)
+
+// EVENTS_BACKEND'e göre olay yolu oluşturur; memory yalnızca worker'lar server içinde çalışırken anlamlıdır
+func New(backend string, rdb *redis.Client) (Bus, error) {
+	switch backend {
+	case BackendRedis:
+		if rdb == nil {
+			return nil, fmt.Errorf("redis olay yolu için redis bağlantısı gerekli")
+		}
+		return NewRedisBus(rdb), nil
+	case BackendMemory:
+		return NewMemoryBus(), nil
+	default:
+		return nil, fmt.Errorf("bilinmeyen olay backend'i: %s", backend)
+	}
+}
@@ -0,0 +1,60 @@
+package events
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+const subscriberBuffer = 64
+
+// Tek process içindeki olay yolu (memory kuyruğu / server içi worker'lar)
+type MemoryBus struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[chan Event]struct{}
+}
+
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{
+		subscribers: make(map[string]map[chan Event]struct{}),
+	}
+}
+
+// Yavaş abone yayını bekletmez, tamponu dolu abonenin olayı düşürülür
+func (b *MemoryBus) Publish(ctx context.Context, event Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for ch := range b.subscribers[event.UploadID] {
+		select {
+		case ch <- event:
+		default:
+			log.Printf("UYARI: abone tamponu dolu, olay düşürüldü %s (%s)", event.Type, event.UploadID)
+		}
+	}
+	return nil
+}
+
+func (b *MemoryBus) Subscribe(ctx context.Context, uploadID string) (<-chan Event, func(), error) {
+	ch := make(chan Event, subscriberBuffer)
+
+	b.mu.Lock()
+	if b.subscribers[uploadID] == nil {
+		b.subscribers[uploadID] = make(map[chan Event]struct{})
+	}
+	b.subscribers[uploadID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers[uploadID], ch)
+			if len(b.subscribers[uploadID]) == 0 {
+				delete(b.subscribers, uploadID)
+			}
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+	return ch, cancel, nil
+}
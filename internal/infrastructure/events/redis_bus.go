@@ -0,0 +1,75 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const channelPrefix = "upload_events:"
+
+// Worker ve server ayrı process'lerde çalıştığında olaylar Redis pub/sub ile taşınır.
+// Pub/sub kalıcı değildir: bağlı abone yoksa olay kaybolur, kalıcı durum için /upload/status ve /jobs kullanılır
+type RedisBus struct {
+	rdb *redis.Client
+}
+
+func NewRedisBus(rdb *redis.Client) *RedisBus {
+	return &RedisBus{rdb: rdb}
+}
+
+func (b *RedisBus) Publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return b.rdb.Publish(ctx, channelPrefix+event.UploadID, payload).Err()
+}
+
+func (b *RedisBus) Subscribe(ctx context.Context, uploadID string) (<-chan Event, func(), error) {
+	pubsub := b.rdb.Subscribe(ctx, channelPrefix+uploadID)
+	// Abonelik onaylanmadan dönülürse ilk olaylar kaçabilir
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, nil, err
+	}
+
+	messages := pubsub.Channel()
+	out := make(chan Event, subscriberBuffer)
+	done := make(chan struct{})
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-done:
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				var event Event
+				if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+					log.Printf("Geçersiz olay atlandı (%s): %v", uploadID, err)
+					continue
+				}
+				select {
+				case out <- event:
+				case <-done:
+					return
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			close(done)
+			pubsub.Close()
+		})
+	}
+	return out, cancel, nil
+}
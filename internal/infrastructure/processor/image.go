@@ -5,7 +5,6 @@ import (
 	"file-uploader/pkg/helper"
 	"fmt"
 	"log"
-	"os"
 	"path/filepath"
 	"time"
 
@@ -20,39 +19,43 @@ type ResizeOption struct {
 
 type MediaService interface {
 	CreateMedia(media *dto.ImageDTO, filePath string) error
-	CreateVariantsForMedia(mediaID string, filePath string) error
+	CreateVariantsForMedia(ownerID, mediaID string, filePath string) error
 	CreateVideo(video *dto.VideoDTO) error
 	ResizeVideo(videoID string, width int64, height int64, video *dto.VideoDTO) error
 	UpdateMediaStatus(id string, status string) error
+	MarkBlobOrigin(sha256, originID string) error
 }
 
-// Image işle
-func ProcessImageFile(mediaService MediaService, filename, finalFilePath string) error {
+// Image işle, oluşturulan media ID'sini döner. Kayıt ownerID tenant'ına yazılır
+func ProcessImageFile(mediaService MediaService, ownerID, filename, finalFilePath, sha256 string) (string, error) {
 	imageDTO := &dto.ImageDTO{
+		OwnerID:      ownerID,
 		OriginalName: filename,
 		FileType:     helper.GetMimeTypeFromExtension(filename),
 		FilePath:     finalFilePath,
+		SHA256:       sha256,
 		Status:       "processing",
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
 	}
 
-	file, err := os.Open(finalFilePath)
-	if err != nil {
-		return fmt.Errorf("dosya açılamadı: %w", err)
+	if err := mediaService.CreateMedia(imageDTO, finalFilePath); err != nil {
+		return "", fmt.Errorf("media oluşturulamadı: %w", err)
 	}
-	defer file.Close()
 
-	if err := mediaService.CreateMedia(imageDTO, finalFilePath); err != nil {
-		return fmt.Errorf("media oluşturulamadı: %w", err)
+	if err := mediaService.CreateVariantsForMedia(ownerID, imageDTO.ID, finalFilePath); err != nil {
+		return imageDTO.ID, fmt.Errorf("media varyantları oluşturulamadı: %w", err)
 	}
 
-	if err := mediaService.CreateVariantsForMedia(imageDTO.ID, finalFilePath); err != nil {
-		return fmt.Errorf("media varyantları oluşturulamadı: %w", err)
+	// Aynı içerik tekrar yüklendiğinde bu kaydın varyantları paylaşılır
+	if sha256 != "" {
+		if err := mediaService.MarkBlobOrigin(sha256, imageDTO.ID); err != nil {
+			log.Printf("UYARI: blob origin kaydedilemedi %s: %v", sha256, err)
+		}
 	}
 
 	log.Printf("INFO: Image %s başarıyla işlendi. Path: %s", filename, imageDTO.FilePath)
-	return nil
+	return imageDTO.ID, nil
 }
 
 func ResizeImage(inputPath, outputPath string, options ResizeOption) (string, error) {
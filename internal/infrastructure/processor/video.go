@@ -5,18 +5,19 @@ import (
 	"file-uploader/pkg/helper"
 	"fmt"
 	"log"
-	"os"
 	"time"
 
 	"github.com/mowshon/moviego"
 )
 
-// Video işle
-func ProcessVideoFile(mediaService MediaService, filename, finalFilePath string) error {
+// Video işle, oluşturulan video ID'sini döner. Kayıt ownerID tenant'ına yazılır
+func ProcessVideoFile(mediaService MediaService, ownerID, filename, finalFilePath, sha256 string) (string, error) {
 	videoDTO := &dto.VideoDTO{
+		OwnerID:      ownerID,
 		OriginalName: filename,
 		FileType:     helper.GetMimeTypeFromExtension(filename),
 		FilePath:     finalFilePath,
+		SHA256:       sha256,
 		Status:       "processing",
 		Height:       0,
 		Width:        0,
@@ -24,22 +25,22 @@ func ProcessVideoFile(mediaService MediaService, filename, finalFilePath string)
 		UpdatedAt:    time.Now(),
 	}
 
-	file, err := os.Open(finalFilePath)
-	if err != nil {
-		return fmt.Errorf("dosya açılamadı: %w", err)
-	}
-	defer file.Close()
-
 	if err := mediaService.CreateVideo(videoDTO); err != nil {
-		return fmt.Errorf("video oluşturulamadı: %w", err)
+		return "", fmt.Errorf("video oluşturulamadı: %w", err)
 	}
 
 	if err := mediaService.ResizeVideo(videoDTO.VideoID, 1920, 1280, videoDTO); err != nil {
 		log.Printf("UYARI: Video boyutlandırma başarısız: %v", err)
 	}
 
+	if sha256 != "" {
+		if err := mediaService.MarkBlobOrigin(sha256, videoDTO.VideoID); err != nil {
+			log.Printf("UYARI: blob origin kaydedilemedi %s: %v", sha256, err)
+		}
+	}
+
 	log.Printf("INFO: Video %s başarıyla işlendi. Path: %s", filename, videoDTO.FilePath)
-	return nil
+	return videoDTO.VideoID, nil
 }
 
 func ResizeVideo(inputPath string, outputPath string, width int64, height int64) error {
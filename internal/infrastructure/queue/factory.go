@@ -0,0 +1,45 @@
+package queue
+
+import (
+	"fmt"
+	"sync"
+
+	"file-uploader/pkg/config"
+
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+var (
+	memoryQueuesMu sync.Mutex
+	memoryQueues   = make(map[string]*MemoryQueue)
+)
+
+// QUEUE_BACKEND'e göre kuyruk oluşturur. group yalnızca Redis consumer group'u için kullanılır.
+// memory backend'inde aynı adla istenen kuyruk aynı örnektir, böylece server ve in-process
+// worker'lar aynı kuyruğu paylaşır
+func New(cfg config.QueueConfig, name, group, consumer string, rdb *redis.Client, db *gorm.DB) (JobQueue, error) {
+	switch cfg.Backend {
+	case BackendRedis, "":
+		if rdb == nil {
+			return nil, fmt.Errorf("redis kuyruğu için redis bağlantısı gerekli")
+		}
+		return NewRedisStreamQueue(rdb, name, group, consumer, cfg.VisibilityTimeout), nil
+	case BackendPostgres:
+		if db == nil {
+			return nil, fmt.Errorf("postgres kuyruğu için veritabanı bağlantısı gerekli")
+		}
+		return NewPostgresQueue(db, name, consumer, cfg.VisibilityTimeout), nil
+	case BackendMemory:
+		memoryQueuesMu.Lock()
+		defer memoryQueuesMu.Unlock()
+		q, ok := memoryQueues[name]
+		if !ok {
+			q = NewMemoryQueue(cfg.VisibilityTimeout)
+			memoryQueues[name] = q
+		}
+		return q, nil
+	default:
+		return nil, fmt.Errorf("bilinmeyen kuyruk backend'i: %s", cfg.Backend)
+	}
+}
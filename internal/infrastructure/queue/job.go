@@ -10,15 +10,16 @@ const (
 )
 
 type Job struct {
+	ID         string `json:"id,omitempty"` // jobs tablosundaki takip kaydı, retry'da aynı kalır
 	UploadID   string
 	Type       JobType
 	Filename   string
 	ChunkIndex int
 	//File        multipart.File
-	FilePath    string `json:"file_path,omitempty"` // chunk dosya yolu
+	FilePath    string `json:"file_path,omitempty"` // staging'deki chunk dosya yolu
 	TotalChunks int
-	ChunkHash   string `json:"chunk_hash,omitempty"`
-	FileContent []byte `json:"file_content,omitempty"`
+	ChunkHash   string `json:"chunk_hash,omitempty"` // staging'e yazılırken hesaplanan SHA-256
+	ChunkSize   int64  `json:"chunk_size,omitempty"`
 	ErrorType   string `json:"error_type,omitempty"` // Hata türü (örneğin, "missing_chunk")
 	LastError   string `json:"last_error,omitempty"`
 	Status      string `json:"status,omitempty"`
@@ -27,8 +28,10 @@ type Job struct {
 }
 
 type ProcessedJob struct {
+	JobID          string `json:"job_id,omitempty"` // merge job'u, server sonucu işleyince tamamlanır
 	UploadID       string `json:"upload_id"`
 	Filename       string `json:"filename"`
 	MergedFilePath string `json:"merged_file_path"`
 	TotalChunks    int    `json:"total_chunks"`
+	SHA256         string `json:"sha256,omitempty"` // merge sırasında hesaplanan/doğrulanan özet
 }
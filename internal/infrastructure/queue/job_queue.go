@@ -0,0 +1,84 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// Stream/kuyruk adları: server -> worker işleri ve worker -> server merge sonuçları
+const (
+	JobStream       = "job_stream"
+	ProcessedStream = "processed_stream"
+
+	WorkerGroup = "workers" // JobStream'i tüketen worker replikaları
+	ServerGroup = "servers" // ProcessedStream'i tüketen server replikaları
+)
+
+// Desteklenen kuyruk backend'leri (QUEUE_BACKEND)
+const (
+	BackendRedis    = "redis"
+	BackendPostgres = "postgres"
+	BackendMemory   = "memory"
+)
+
+// Kuyruktan alınan mesaj, işlendikten sonra Ack ile onaylanmalı; onaylanmayan mesaj
+// görünürlük süresi dolunca başka bir consumer'a tekrar teslim edilir
+type Message struct {
+	ID       string
+	Body     []byte
+	Attempts int // bu teslim dahil kaç kez alındığı (Nack ile geri bırakılanlar sayılır)
+}
+
+type JobQueue interface {
+	Enqueue(ctx context.Context, body []byte) error
+	// Mesaj delay süresi dolduktan sonra teslim edilir
+	Delay(ctx context.Context, body []byte, delay time.Duration) error
+	// ctx iptal edilene kadar bir mesaj gelmesini bekler
+	Dequeue(ctx context.Context) (*Message, error)
+	Ack(ctx context.Context, msg *Message) error
+	// Mesaj işlenemedi: delay sonra Attempts artırılarak tekrar teslim edilir
+	Nack(ctx context.Context, msg *Message, delay time.Duration) error
+	// İşlenmekte olan mesajın görünürlük süresi baştan başlatılır; mesaj başka bir consumer'a
+	// geçtiyse ErrMessageLost döner
+	Extend(ctx context.Context, msg *Message) error
+}
+
+var ErrMessageLost = errors.New("mesaj artık bu consumer'da değil")
+
+// Görünürlük süresi dolmadan en az iki kez yenilenir
+func HeartbeatInterval(visibilityTimeout time.Duration) time.Duration {
+	return visibilityTimeout / 3
+}
+
+// İş sürdükçe mesajın görünürlük süresi interval aralıklarla yenilenir; görünürlük süresini aşan
+// işler (çok GB'lık merge, video işleme) başka bir consumer'a tekrar teslim edilmez. Dönen stop iş bitince çağrılmalı
+func KeepAlive(q JobQueue, msg *Message, interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		return func() {}
+	}
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := q.Extend(context.Background(), msg); err != nil {
+					log.Printf("Mesajın görünürlük süresi uzatılamadı %s: %v", msg.ID, err)
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
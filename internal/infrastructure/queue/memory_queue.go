@@ -0,0 +1,159 @@
+package queue
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Tek process içinde çalışan kuyruk (tek binary kurulum ve testler için). Mesajlar bellekte tutulur,
+// process kapanınca kaybolur; Ack edilmeyen mesaj visibilityTimeout sonunda tekrar teslim edilir
+type MemoryQueue struct {
+	visibilityTimeout time.Duration
+
+	mu       sync.Mutex
+	nextID   int64
+	ready    []*memoryEntry
+	delayed  []*memoryEntry
+	inflight map[string]*memoryEntry
+	wake     chan struct{} // yeni mesaj geldiğinde kapatılıp yenisiyle değiştirilir, bekleyen Dequeue'lar uyanır
+}
+
+type memoryEntry struct {
+	msg      Message
+	deadline time.Time // delayed: teslim zamanı, inflight: görünürlük süresinin bittiği an
+}
+
+func NewMemoryQueue(visibilityTimeout time.Duration) *MemoryQueue {
+	return &MemoryQueue{
+		visibilityTimeout: visibilityTimeout,
+		inflight:          make(map[string]*memoryEntry),
+		wake:              make(chan struct{}),
+	}
+}
+
+func (q *MemoryQueue) Enqueue(ctx context.Context, body []byte) error {
+	return q.Delay(ctx, body, 0)
+}
+
+func (q *MemoryQueue) Delay(ctx context.Context, body []byte, delay time.Duration) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.nextID++
+	q.push(Message{ID: strconv.FormatInt(q.nextID, 10), Body: body}, delay)
+	return nil
+}
+
+func (q *MemoryQueue) Dequeue(ctx context.Context) (*Message, error) {
+	for {
+		q.mu.Lock()
+		q.promote(time.Now())
+		if len(q.ready) > 0 {
+			entry := q.ready[0]
+			q.ready = q.ready[1:]
+			entry.msg.Attempts++
+			entry.deadline = time.Now().Add(q.visibilityTimeout)
+			q.inflight[entry.msg.ID] = entry
+			msg := entry.msg
+			q.mu.Unlock()
+			return &msg, nil
+		}
+		wait := q.nextDeadline()
+		wake := q.wake
+		q.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-wake:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+func (q *MemoryQueue) Ack(ctx context.Context, msg *Message) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.inflight, msg.ID)
+	return nil
+}
+
+func (q *MemoryQueue) Nack(ctx context.Context, msg *Message, delay time.Duration) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	entry, ok := q.inflight[msg.ID]
+	if !ok {
+		return nil // görünürlük süresi dolup tekrar kuyruğa alınmış
+	}
+	delete(q.inflight, msg.ID)
+	q.push(entry.msg, delay)
+	return nil
+}
+
+func (q *MemoryQueue) Extend(ctx context.Context, msg *Message) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	entry, ok := q.inflight[msg.ID]
+	if !ok {
+		return ErrMessageLost
+	}
+	entry.deadline = time.Now().Add(q.visibilityTimeout)
+	return nil
+}
+
+// q.mu tutulurken çağrılır
+func (q *MemoryQueue) push(msg Message, delay time.Duration) {
+	entry := &memoryEntry{msg: msg}
+	if delay > 0 {
+		entry.deadline = time.Now().Add(delay)
+		q.delayed = append(q.delayed, entry)
+	} else {
+		q.ready = append(q.ready, entry)
+	}
+	close(q.wake)
+	q.wake = make(chan struct{})
+}
+
+// Zamanı gelen gecikmeli mesajlar ve görünürlük süresi dolan inflight mesajlar ready'ye taşınır
+func (q *MemoryQueue) promote(now time.Time) {
+	pending := q.delayed[:0]
+	for _, entry := range q.delayed {
+		if now.Before(entry.deadline) {
+			pending = append(pending, entry)
+		} else {
+			q.ready = append(q.ready, entry)
+		}
+	}
+	q.delayed = pending
+
+	for id, entry := range q.inflight {
+		if !now.Before(entry.deadline) {
+			delete(q.inflight, id)
+			q.ready = append(q.ready, entry)
+		}
+	}
+}
+
+// Bir sonraki gecikmeli/inflight mesajın hazır olacağı ana kadar beklenir
+func (q *MemoryQueue) nextDeadline() time.Duration {
+	wait := q.visibilityTimeout
+	now := time.Now()
+	for _, entry := range q.delayed {
+		if d := entry.deadline.Sub(now); d < wait {
+			wait = d
+		}
+	}
+	for _, entry := range q.inflight {
+		if d := entry.deadline.Sub(now); d < wait {
+			wait = d
+		}
+	}
+	if wait <= 0 {
+		wait = time.Millisecond
+	}
+	return wait
+}
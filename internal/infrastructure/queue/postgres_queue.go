@@ -0,0 +1,133 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"file-uploader/internal/domain/entities"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const postgresPollInterval = time.Second
+
+// Redis olmadan çalışan kuyruk: her mesaj queue_messages tablosunda bir satırdır. Worker'lar
+// SELECT ... FOR UPDATE SKIP LOCKED ile kilitli satırları atlayarak iş alır, alınan satır
+// visibilityTimeout kadar kilitlenir; Ack edilmezse süre dolunca başka bir consumer alır
+type PostgresQueue struct {
+	db                *gorm.DB
+	name              string
+	consumer          string
+	visibilityTimeout time.Duration
+}
+
+func NewPostgresQueue(db *gorm.DB, name, consumer string, visibilityTimeout time.Duration) *PostgresQueue {
+	return &PostgresQueue{
+		db:                db,
+		name:              name,
+		consumer:          consumer,
+		visibilityTimeout: visibilityTimeout,
+	}
+}
+
+func (q *PostgresQueue) Enqueue(ctx context.Context, body []byte) error {
+	return q.Delay(ctx, body, 0)
+}
+
+func (q *PostgresQueue) Delay(ctx context.Context, body []byte, delay time.Duration) error {
+	return q.db.WithContext(ctx).Create(&entities.QueueMessage{
+		Queue:       q.name,
+		Body:        body,
+		AvailableAt: time.Now().Add(delay),
+	}).Error
+}
+
+func (q *PostgresQueue) Dequeue(ctx context.Context) (*Message, error) {
+	for {
+		msg, err := q.claim(ctx)
+		if err != nil || msg != nil {
+			return msg, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(postgresPollInterval):
+		}
+	}
+}
+
+func (q *PostgresQueue) claim(ctx context.Context) (*Message, error) {
+	var claimed *Message
+	err := q.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		var row entities.QueueMessage
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("queue = ? AND available_at <= ? AND (locked_until IS NULL OR locked_until < ?)", q.name, now, now).
+			Order("available_at, id").
+			First(&row).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		lockedUntil := now.Add(q.visibilityTimeout)
+		if err := tx.Model(&row).Updates(map[string]interface{}{
+			"attempts":     gorm.Expr("attempts + 1"),
+			"locked_by":    q.consumer,
+			"locked_until": lockedUntil,
+		}).Error; err != nil {
+			return err
+		}
+		claimed = &Message{ID: strconv.FormatInt(row.ID, 10), Body: row.Body, Attempts: row.Attempts + 1}
+		return nil
+	})
+	return claimed, err
+}
+
+func (q *PostgresQueue) Ack(ctx context.Context, msg *Message) error {
+	id, err := strconv.ParseInt(msg.ID, 10, 64)
+	if err != nil {
+		return err
+	}
+	return q.db.WithContext(ctx).Where("id = ? AND queue = ?", id, q.name).Delete(&entities.QueueMessage{}).Error
+}
+
+// Kilit yalnızca satır hâlâ bu consumer'daysa uzatılır
+func (q *PostgresQueue) Extend(ctx context.Context, msg *Message) error {
+	id, err := strconv.ParseInt(msg.ID, 10, 64)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	result := q.db.WithContext(ctx).Model(&entities.QueueMessage{}).
+		Where("id = ? AND queue = ? AND locked_by = ? AND locked_until >= ?", id, q.name, q.consumer, now).
+		Update("locked_until", now.Add(q.visibilityTimeout))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrMessageLost
+	}
+	return nil
+}
+
+// Kilit kaldırılır ve satır delay sonra tekrar alınabilir hale gelir (attempts korunur)
+func (q *PostgresQueue) Nack(ctx context.Context, msg *Message, delay time.Duration) error {
+	id, err := strconv.ParseInt(msg.ID, 10, 64)
+	if err != nil {
+		return err
+	}
+	return q.db.WithContext(ctx).Model(&entities.QueueMessage{}).
+		Where("id = ? AND queue = ?", id, q.name).
+		Updates(map[string]interface{}{
+			"available_at": time.Now().Add(delay),
+			"locked_by":    "",
+			"locked_until": nil,
+		}).Error
+}
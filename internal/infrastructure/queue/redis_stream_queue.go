@@ -0,0 +1,304 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+const (
+	streamBodyField     = "body"
+	streamAttemptsField = "attempts"
+	streamBlockTimeout  = time.Second
+	delayedPromoteLimit = 100
+)
+
+// Redis Streams + consumer group: aynı gruptaki replikalar işleri paylaşır, XACK edilmeyen mesajlar
+// visibilityTimeout kadar boşta kaldıktan sonra XAUTOCLAIM ile başka bir consumer tarafından devralınır;
+// işlenmekte olan mesajın idle süresi Extend ile sıfırlanır.
+// Gecikmeli mesajlar "<stream>:delayed" sorted set'inde bekler, zamanı gelince stream'e taşınır
+type RedisStreamQueue struct {
+	rdb               *redis.Client
+	stream            string
+	delayed           string
+	group             string
+	consumer          string
+	visibilityTimeout time.Duration
+
+	mu          sync.Mutex
+	groupReady  bool
+	claimCursor string
+	nextClaim   time.Time
+}
+
+// Sorted set üyesi: aynı gövdeli iki gecikmeli mesaj birbirini ezmesin diye benzersiz ID taşır
+type delayedEntry struct {
+	ID       string `json:"id"`
+	Body     []byte `json:"body"`
+	Attempts int    `json:"attempts"`
+}
+
+func NewRedisStreamQueue(rdb *redis.Client, stream, group, consumer string, visibilityTimeout time.Duration) *RedisStreamQueue {
+	return &RedisStreamQueue{
+		rdb:               rdb,
+		stream:            stream,
+		delayed:           stream + ":delayed",
+		group:             group,
+		consumer:          consumer,
+		visibilityTimeout: visibilityTimeout,
+		claimCursor:       "0-0",
+	}
+}
+
+func (q *RedisStreamQueue) Enqueue(ctx context.Context, body []byte) error {
+	return q.add(ctx, q.rdb, body, 0)
+}
+
+func (q *RedisStreamQueue) Delay(ctx context.Context, body []byte, delay time.Duration) error {
+	return q.addDelayed(ctx, q.rdb, body, 0, delay)
+}
+
+func (q *RedisStreamQueue) Dequeue(ctx context.Context) (*Message, error) {
+	if err := q.ensureGroup(ctx); err != nil {
+		return nil, err
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if err := q.promoteDelayed(ctx); err != nil {
+			log.Printf("%s: gecikmeli mesajlar taşınamadı: %v", q.stream, err)
+		}
+
+		// Önce çöken/takılan consumer'lardan kalan mesajlar devralınır
+		msg, err := q.reclaim(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if msg != nil {
+			return msg, nil
+		}
+
+		streams, err := q.rdb.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    q.group,
+			Consumer: q.consumer,
+			Streams:  []string{q.stream, ">"},
+			Count:    1,
+			Block:    streamBlockTimeout,
+		}).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, stream := range streams {
+			for _, m := range stream.Messages {
+				return toMessage(m, 1), nil
+			}
+		}
+	}
+}
+
+// Mesaj onaylanır ve stream'den silinir (tek consumer group olduğu için başka okuyan yok)
+func (q *RedisStreamQueue) Ack(ctx context.Context, msg *Message) error {
+	pipe := q.rdb.TxPipeline()
+	pipe.XAck(ctx, q.stream, q.group, msg.ID)
+	pipe.XDel(ctx, q.stream, msg.ID)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Pending girdisi kapatılıp mesaj deneme sayısıyla birlikte yeniden eklenir (aynı transaction'da)
+func (q *RedisStreamQueue) Nack(ctx context.Context, msg *Message, delay time.Duration) error {
+	pipe := q.rdb.TxPipeline()
+	pipe.XAck(ctx, q.stream, q.group, msg.ID)
+	pipe.XDel(ctx, q.stream, msg.ID)
+	var err error
+	if delay > 0 {
+		err = q.addDelayed(ctx, pipe, msg.Body, msg.Attempts, delay)
+	} else {
+		err = q.add(ctx, pipe, msg.Body, msg.Attempts)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// Mesaj hâlâ bu consumer'ın pending listesindeyse idle süresi sıfırlanır (XCLAIM ... JUSTID, teslim sayısı artmaz).
+// Sahiplik kontrolü ve claim aynı script'te yapılır; XAUTOCLAIM ile başka consumer'a geçmiş mesaj geri alınmaz
+var extendScript = redis.NewScript(`
+local pending = redis.call('XPENDING', KEYS[1], ARGV[1], ARGV[3], ARGV[3], 1)
+if #pending == 0 or pending[1][2] ~= ARGV[2] then
+	return 0
+end
+redis.call('XCLAIM', KEYS[1], ARGV[1], ARGV[2], 0, ARGV[3], 'JUSTID')
+return 1
+`)
+
+func (q *RedisStreamQueue) Extend(ctx context.Context, msg *Message) error {
+	claimed, err := extendScript.Run(ctx, q.rdb, []string{q.stream}, q.group, q.consumer, msg.ID).Int()
+	if err != nil {
+		return err
+	}
+	if claimed == 0 {
+		return ErrMessageLost
+	}
+	return nil
+}
+
+func (q *RedisStreamQueue) add(ctx context.Context, c redis.Cmdable, body []byte, attempts int) error {
+	return c.XAdd(ctx, &redis.XAddArgs{
+		Stream: q.stream,
+		Values: map[string]interface{}{streamBodyField: body, streamAttemptsField: attempts},
+	}).Err()
+}
+
+func (q *RedisStreamQueue) addDelayed(ctx context.Context, c redis.Cmdable, body []byte, attempts int, delay time.Duration) error {
+	member, err := json.Marshal(delayedEntry{ID: uuid.New().String(), Body: body, Attempts: attempts})
+	if err != nil {
+		return err
+	}
+	return c.ZAdd(ctx, q.delayed, &redis.Z{
+		Score:  float64(time.Now().Add(delay).UnixMilli()),
+		Member: string(member),
+	}).Err()
+}
+
+// Zamanı gelen gecikmeli mesajlar stream'e taşınır; ZREM'i kazanan replika taşır, böylece mesaj çoğalmaz
+func (q *RedisStreamQueue) promoteDelayed(ctx context.Context) error {
+	due, err := q.rdb.ZRangeByScore(ctx, q.delayed, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(time.Now().UnixMilli(), 10),
+		Count: delayedPromoteLimit,
+	}).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, member := range due {
+		removed, err := q.rdb.ZRem(ctx, q.delayed, member).Result()
+		if err != nil {
+			return err
+		}
+		if removed == 0 {
+			continue
+		}
+		var entry delayedEntry
+		if err := json.Unmarshal([]byte(member), &entry); err != nil {
+			log.Printf("%s: geçersiz gecikmeli mesaj atlandı: %v", q.stream, err)
+			continue
+		}
+		if err := q.add(ctx, q.rdb, entry.Body, entry.Attempts); err != nil {
+			// Mesaj kaybolmasın diye sorted set'e geri konur
+			q.rdb.ZAdd(ctx, q.delayed, &redis.Z{Score: float64(time.Now().UnixMilli()), Member: member})
+			return err
+		}
+	}
+	return nil
+}
+
+func (q *RedisStreamQueue) ensureGroup(ctx context.Context) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.groupReady {
+		return nil
+	}
+
+	// "0": grup oluşturulmadan önce eklenen mesajlar da teslim edilir
+	err := q.rdb.XGroupCreateMkStream(ctx, q.stream, q.group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("consumer group oluşturulamadı %s/%s: %w", q.stream, q.group, err)
+	}
+	q.groupReady = true
+	return nil
+}
+
+// XAUTOCLAIM her Dequeue'da değil, görünürlük süresinin yarısında bir pending listesini tarar
+func (q *RedisStreamQueue) reclaim(ctx context.Context) (*Message, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if time.Now().Before(q.nextClaim) {
+		return nil, nil
+	}
+
+	messages, next, err := q.rdb.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   q.stream,
+		Group:    q.group,
+		MinIdle:  q.visibilityTimeout,
+		Start:    q.claimCursor,
+		Count:    1,
+		Consumer: q.consumer,
+	}).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	q.claimCursor = next
+	if next == "" || next == "0-0" {
+		q.claimCursor = "0-0"
+	}
+	if len(messages) == 0 {
+		q.nextClaim = time.Now().Add(q.visibilityTimeout / 2)
+		return nil, nil
+	}
+
+	// XAUTOCLAIM teslim sayısını artırır ama döndürmez; her teslimde çöken mesajın denemeleri XPENDING'den okunur,
+	// aksi halde mesaj deneme sınırına hiç ulaşmadan sonsuza kadar devralınır
+	pending, err := q.rdb.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: q.stream,
+		Group:  q.group,
+		Start:  messages[0].ID,
+		End:    messages[0].ID,
+		Count:  1,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	deliveries := int64(1)
+	if len(pending) == 1 {
+		deliveries = pending[0].RetryCount
+	}
+
+	log.Printf("%s: onaylanmamış mesaj devralındı %s (consumer: %s, teslim: %d)", q.stream, messages[0].ID, q.consumer, deliveries)
+	return toMessage(messages[0], deliveries), nil
+}
+
+// attempts alanı Nack'ten önceki denemeleri, deliveries bu stream girdisinin teslim sayısını tutar
+func toMessage(m redis.XMessage, deliveries int64) *Message {
+	var body []byte
+	switch v := m.Values[streamBodyField].(type) {
+	case string:
+		body = []byte(v)
+	case []byte:
+		body = v
+	}
+	attempts := 0
+	if v, ok := m.Values[streamAttemptsField].(string); ok {
+		attempts, _ = strconv.Atoi(v)
+	}
+	return &Message{ID: m.ID, Body: body, Attempts: attempts + int(deliveries)}
+}
+
+// Consumer adı replikalar arasında benzersiz olmalı, pending mesajlar bu adla izlenir
+func ConsumerName(role string) string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%s-%d", role, host, os.Getpid())
+}
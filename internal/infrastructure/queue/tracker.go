@@ -0,0 +1,59 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"file-uploader/internal/domain/entities"
+	"file-uploader/internal/domain/repositories"
+	"file-uploader/pkg/constants"
+
+	"github.com/google/uuid"
+)
+
+// İşe ID verir (yoksa), jobs tablosuna queued olarak yazar ve kuyruğa atar; delay > 0 ise gecikmeli eklenir.
+// Kuyruğa eklenemeyen iş failed olarak işaretlenir
+func Submit(ctx context.Context, q JobQueue, tracker repositories.JobRepository, job *Job, delay time.Duration) error {
+	if job.ID == "" {
+		job.ID = uuid.New().String()
+	}
+	body, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	if tracker != nil {
+		if err := tracker.Upsert(&entities.Job{
+			ID:       job.ID,
+			UploadID: job.UploadID,
+			Type:     string(job.Type),
+			Status:   constants.JobStatusQueued,
+		}); err != nil {
+			// Takip kaydı yazılamasa da iş kuyruğa alınır
+			log.Printf("Job kaydı oluşturulamadı %s: %v", job.ID, err)
+		}
+	}
+
+	if delay > 0 {
+		err = q.Delay(ctx, body, delay)
+	} else {
+		err = q.Enqueue(ctx, body)
+	}
+	if err != nil {
+		Track(tracker, job.ID, constants.JobStatusFailed, err.Error())
+		return err
+	}
+	return nil
+}
+
+// ID'siz (eski) işler ve tracker verilmemiş kurulumlar için sessizce atlanır
+func Track(tracker repositories.JobRepository, id, status, lastError string) {
+	if tracker == nil || id == "" {
+		return
+	}
+	if err := tracker.Transition(id, status, lastError); err != nil {
+		log.Printf("Job durumu güncellenemedi %s -> %s: %v", id, status, err)
+	}
+}
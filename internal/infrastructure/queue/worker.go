@@ -3,140 +3,442 @@ package queue
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"file-uploader/internal/domain/entities"
 	"file-uploader/internal/domain/repositories"
+	"file-uploader/internal/infrastructure/events"
+	"file-uploader/pkg/constants"
+	fe "file-uploader/pkg/errors"
+	fl "file-uploader/pkg/file"
 	"fmt"
 	"log"
-	"os"
 	"sync"
+	"time"
 )
 
-type Worker struct {
-	ID            int        // worker id
-	JobChan       <-chan Job // iş kuyruğu
-	Wg            *sync.WaitGroup
-	Repo          repositories.FileUploadRepository
-	MergeCallback func(uploadID, filename, mergedFilePath string)
-}
+// Otomatik retry merge job'u RetryCount ile artan gecikmeyle kuyruğa eklenir
+const retryMergeBaseDelay = 30 * time.Second
 
-type WorkerMedia struct {
-	ID      int        // worker id
-	JobChan <-chan Job // iş kuyruğu
-	Wg      *sync.WaitGroup
-	Repo    repositories.MediaRepository
-}
+// Sonucu devredilemeyen iş bu süre sonra tekrar teslim edilir
+const handOffRetryDelay = 5 * time.Second
 
-func (w *WorkerMedia) Start(ctx context.Context) { // worker başlatma fonksiyonu
-	go func() {
-		defer w.Wg.Done()
-		for {
-			select {
-			case job, ok := <-w.JobChan: //channeldan iş alınır
-				if !ok {
-					log.Printf("Worker %d: Job channel closed", w.ID)
-					return
-				}
-				select {
-				case <-ctx.Done():
-					log.Printf("Worker %d: job %d cancelled", w.ID, job.UploadID)
-					continue
-				default:
-					//w.processJobMedia(job)
-				}
-			case <-ctx.Done():
-				log.Printf("Worker %d: Stopping due to context cancellation", w.ID)
-				return
-			}
-		}
-	}()
+// Her teslimde çöken ya da sürekli tekrar kuyruğa dönen iş bu kadar teslimden sonra dead-letter'a yazılır
+const maxJobDeliveries = 10
+
+// İşin worker'daki sonucu, jobs tablosundaki duruma çevrilir
+type jobOutcome int
+
+const (
+	outcomeSucceeded jobOutcome = iota
+	outcomeFailed
+	outcomeRetrying  // aynı ID ile gecikmeli retry job'u kuyruğa alındı
+	outcomeHandedOff // merge sonucu processed kuyruğunda, durum server'da tamamlanır
+	outcomeRequeue   // sonuç devredilemedi ya da geçici DB hatası, mesaj onaylanmadan tekrar teslim edilir
+)
+
+type Worker struct {
+	ID        int      // worker id
+	Jobs      JobQueue // server -> worker iş kuyruğu
+	Processed JobQueue // worker -> server merge sonuçları
+	Wg        *sync.WaitGroup
+	Repo      repositories.FileUploadRepository
+	Sessions  repositories.UploadSessionStore
+	Tracker   repositories.JobRepository
+	Events    events.Publisher // upload ilerleme olayları (SSE/WebSocket)
+	Heartbeat time.Duration    // iş sürerken mesajın görünürlük süresinin yenilenme aralığı
 }
 
 func (w *Worker) Start(ctx context.Context) { // worker başlatma fonksiyonu
 	go func() {
 		defer w.Wg.Done()
 		for {
-			select {
-			case job, ok := <-w.JobChan: //channeldan iş alınır
-				if !ok {
-					log.Printf("Worker %d: Job channel closed", w.ID)
+			// İş ancak işlendikten sonra ack edilir, worker çökerse başka bir worker devralır
+			msg, err := w.Jobs.Dequeue(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					log.Printf("Worker %d: Stopping due to context cancellation", w.ID)
 					return
 				}
-				select {
-				case <-ctx.Done():
-					log.Printf("Worker %d: job %d cancelled", w.ID, job.UploadID)
-					continue
-				default:
-					w.processJob(job)
+				log.Printf("Worker %d: Dequeue failed: %v", w.ID, err)
+				time.Sleep(time.Second)
+				continue
+			}
+
+			job, err := DeserializeJob(string(msg.Body))
+			outcome := outcomeFailed
+			if err != nil {
+				log.Printf("Worker %d: DeserializeJob failed: %v", w.ID, err)
+			} else if msg.Attempts > maxJobDeliveries {
+				w.deadLetter(job, msg.Attempts)
+			} else {
+				// Görünürlük süresini aşan merge'ler ikinci bir worker'a teslim edilmesin diye mesaj canlı tutulur
+				stop := KeepAlive(w.Jobs, msg, w.Heartbeat)
+				outcome = w.processJob(ctx, job)
+				stop()
+			}
+			if outcome == outcomeRequeue {
+				// Sonucu devredilemeyen ya da geçici DB hatasıyla biten iş onaylanırsa upload takılı kalır; tekrar teslim edilmesi için Nack edilir
+				if err := w.Jobs.Nack(context.Background(), msg, handOffRetryDelay); err != nil {
+					log.Printf("Worker %d: Nack failed: %v", w.ID, err)
 				}
-			case <-ctx.Done():
-				log.Printf("Worker %d: Stopping due to context cancellation", w.ID)
-				return
+				continue
+			}
+
+			// Shutdown sırasında da yarım kalan iş onaylanır; iş fonksiyonları ctx'i beklemez
+			if err := w.Jobs.Ack(context.Background(), msg); err != nil {
+				log.Printf("Worker %d: Ack failed: %v", w.ID, err)
 			}
 		}
 	}()
 }
 
-func (w *Worker) processJob(job Job) {
+func (w *Worker) processJob(ctx context.Context, job *Job) jobOutcome {
 	log.Printf("Worker %d: Processing job %s for upload %s", w.ID, job.Type, job.UploadID)
+	Track(w.Tracker, job.ID, constants.JobStatusRunning, "")
 
+	var outcome jobOutcome
 	var err error
-
 	switch job.Type {
 	case JobSaveChunk:
-		err = w.processSaveChunk(job)
+		outcome, err = w.processChunk(job)
 	case JobMerge:
-		err = w.processMergeChunks(job)
+		outcome, err = w.processMerge(ctx, job)
+	case JobRetry: //* process retry job'a düşünce burası işlenecek
+		outcome, err = w.processRetryMerge(ctx, job)
 	case JobCleanup:
-		err = w.processCleanup(job)
+		outcome, err = w.processCleanup(job)
 	default:
-		err = fmt.Errorf("unknown job type: %s", job.Type)
+		outcome, err = outcomeFailed, fmt.Errorf("unknown job type: %s", job.Type)
+		log.Printf("Worker %d: Unknown job type: %s", w.ID, job.Type)
 	}
 
+	lastError := ""
 	if err != nil {
-		log.Printf("Worker %d: Job %s failed: %v", w.ID, job.Type, err)
-	} else {
-		log.Printf("Worker %d: Job %s succeeded", w.ID, job.Type)
+		lastError = err.Error()
 	}
+	switch outcome {
+	case outcomeSucceeded:
+		Track(w.Tracker, job.ID, constants.JobStatusSucceeded, "")
+	case outcomeFailed:
+		Track(w.Tracker, job.ID, constants.JobStatusFailed, lastError)
+	case outcomeRetrying, outcomeRequeue:
+		Track(w.Tracker, job.ID, constants.JobStatusRetrying, lastError)
+	}
+	return outcome
 }
 
-func (w *Worker) processSaveChunk(job Job) error {
+func (w *Worker) processChunk(job *Job) (jobOutcome, error) {
+	log.Printf("Processing chunk %d for file %s (UploadID: %s)", job.ChunkIndex, job.Filename, job.UploadID)
 	if job.FilePath == "" {
-		return fmt.Errorf("file path is empty")
+		log.Printf("Chunk %d for file %s has no staging path, skipping", job.ChunkIndex, job.Filename)
+		return outcomeFailed, fmt.Errorf("chunk %d için staging dosya yolu yok", job.ChunkIndex)
+	}
+	if exists := w.Repo.ChunkExists(job.UploadID, job.Filename, job.ChunkIndex); exists {
+		// Önceki denemede dosya taşınmış ama kaydı yazılamamış olabilir; kayıt yoksa yazılır, aksi halde chunk hiç görünmez
+		recorded, err := w.chunkRecorded(job.UploadID, job.ChunkIndex)
+		if err != nil {
+			log.Printf("Failed to read chunk records for %s: %v", job.UploadID, err)
+			return outcomeRequeue, err
+		}
+		if !recorded {
+			log.Printf("Chunk %d for file %s exists without a record, recording", job.ChunkIndex, job.Filename)
+			if outcome, err := w.recordChunk(job); err != nil {
+				return outcome, err
+			}
+		} else {
+			log.Printf("Chunk %d for file %s already exists, skipping", job.ChunkIndex, job.Filename)
+		}
+		if err := w.Repo.DiscardStagedChunk(job.UploadID, job.FilePath); err != nil {
+			log.Printf("Failed to discard staged chunk %d: %v", job.ChunkIndex, err)
+		}
+		return outcomeSucceeded, nil
 	}
 
-	file, err := os.Open(job.FilePath)
-	if err != nil {
-		return fmt.Errorf("failed to open chunk file: %w", err)
+	// Hash doğrulama (staging dosyası server ile worker arasında paylaşılan diskte):
+	if err := fl.ValidateFileHash(job.FilePath, job.ChunkHash); err != nil {
+		log.Printf("Hash validation failed for chunk %d: %v", job.ChunkIndex, err)
+		if err := w.Repo.DiscardStagedChunk(job.UploadID, job.FilePath); err != nil {
+			log.Printf("Failed to discard staged chunk %d: %v", job.ChunkIndex, err)
+		}
+		return outcomeFailed, err
+	}
+
+	// Staging'den chunk konumuna taşımak için:
+	if err := w.Repo.CommitStagedChunk(job.UploadID, job.Filename, job.ChunkIndex, job.FilePath); err != nil {
+		log.Printf("Failed to save chunk %d: %v", job.ChunkIndex, err)
+		return outcomeFailed, err
 	}
-	defer file.Close()
 
-	if err := w.Repo.SaveChunk(job.UploadID, job.Filename, job.ChunkIndex, file); err != nil {
-		w.Repo.CleanupTempFiles(job.UploadID) // Hata durumunda temp dosyaları temizler
-		return err
+	if outcome, err := w.recordChunk(job); err != nil {
+		return outcome, err
 	}
-	return nil
+	log.Printf("Chunk %d for file %s saved successfully", job.ChunkIndex, job.Filename)
+	return outcomeSucceeded, nil
 }
 
-func (w *Worker) processMergeChunks(job Job) error {
-	log.Printf("Worker %d: Merge işlemi başlatılıyor - UploadID: %s, Filename: %s", w.ID, job.UploadID, job.Filename)
+// Chunk kaydını ortak store'a yazmak için (server /upload/status buradan okur). DB hatası geçici sayılır,
+// mesaj tekrar teslim edilir; sonraki denemede dosya yerinde olduğu için yalnızca kayıt yazılır
+func (w *Worker) recordChunk(job *Job) (jobOutcome, error) {
+	if err := w.Sessions.RecordChunk(&entities.UploadChunk{
+		UploadID:   job.UploadID,
+		ChunkIndex: job.ChunkIndex,
+		Filename:   job.Filename,
+		Size:       job.ChunkSize,
+		Hash:       job.ChunkHash,
+	}); err != nil {
+		log.Printf("Failed to record chunk %d for %s: %v", job.ChunkIndex, job.Filename, err)
+		return outcomeRequeue, err
+	}
+	events.Publish(w.Events, events.Event{
+		Type:       events.ChunkReceived,
+		UploadID:   job.UploadID,
+		JobID:      job.ID,
+		Filename:   job.Filename,
+		ChunkIndex: events.ChunkIndex(job.ChunkIndex),
+		Size:       job.ChunkSize,
+	})
+	return outcomeSucceeded, nil
+}
 
-	mergedFilePath, err := w.Repo.MergeChunks(job.UploadID, job.Filename, job.TotalChunks)
+func (w *Worker) chunkRecorded(uploadID string, chunkIndex int) (bool, error) {
+	chunks, err := w.Sessions.GetChunks(uploadID)
 	if err != nil {
-		log.Printf("Worker %d: Merge işlemi başarısız - %v", w.ID, err)
-		return err
+		return false, err
 	}
+	for _, chunk := range chunks {
+		if chunk.ChunkIndex == chunkIndex {
+			return true, nil
+		}
+	}
+	return false, nil
+}
 
-	log.Printf("Worker %d: Merge işlemi tamamlandı - %s", w.ID, mergedFilePath)
+func (w *Worker) processMerge(ctx context.Context, job *Job) (jobOutcome, error) {
+	// Başlamış merge shutdown'da yarıda kesilmez; sonucu iptal edilmemiş context ile devredilir
+	handOff := context.WithoutCancel(ctx)
+	//* exponential backoff ile merge işlemi gerçekleştirildi
+	const maxRetries = 5
+	retryDelay := 1 * time.Second
 
-	// Callback varsa merge sonrası çağır
-	if w.MergeCallback != nil {
-		w.MergeCallback(job.UploadID, job.Filename, mergedFilePath)
+	log.Printf("Dosya %s için merge işlemi başlıyor (UploadID: %s, TotalChunks: %d)",
+		job.Filename, job.UploadID, job.TotalChunks)
+	w.publishMerge(events.MergeStarted, job, nil, false)
+
+	// CompleteUpload'da gönderilen özetler merge sırasında doğrulanır
+	var expected fl.Digest
+	if session, err := w.Sessions.GetSession("", job.UploadID); err == nil {
+		expected = session.ExpectedDigest()
+	} else {
+		log.Printf("Upload oturumu okunamadı %s, bütünlük kontrolü yapılmayacak: %v", job.UploadID, err)
+	}
+
+	var mergedFilePath string
+	var digest fl.Digest
+	var err error
+
+	for i := 0; i < maxRetries; i++ {
+		mergedFilePath, digest, err = w.Repo.MergeChunks(job.UploadID, job.Filename, job.TotalChunks, expected)
+		backoff := time.Duration(retryDelay << i) // Exponential backoff
+		if err != nil {
+			var uploadErr *fe.UploadError
+			if errors.As(err, &uploadErr) && uploadErr.Code == "missing_chunk" {
+				job.LastError = err.Error()
+				log.Printf("Eksik chunk hatası, %d/%d tekrar %v saniye sonra...", i+1, maxRetries, backoff)
+				time.Sleep(backoff)
+				continue
+			} else if errors.As(err, &uploadErr) && uploadErr.Code == "checksum_mismatch" {
+				// Aynı chunk'lar tekrar birleştirilse de sonuç değişmez, tekrar denenmez
+				break
+			} else {
+				log.Printf("Merge işlemi yapılamadı %s: %v", job.Filename, err)
+				w.publishMerge(events.Failed, job, err, false)
+				return outcomeFailed, err
+			}
+		} else {
+			log.Printf("Merge işlemi başarıyla gerçekleşti %s: %s", job.Filename, mergedFilePath)
+			break
+		}
 	}
 
-	return nil
+	if err != nil {
+		return w.mergeFailed(handOff, job, err)
+	}
+	w.publishMerge(events.MergeFinished, job, nil, false)
+
+	return w.pushProcessed(handOff, ProcessedJob{
+		JobID:          job.ID,
+		UploadID:       job.UploadID,
+		Filename:       job.Filename,
+		MergedFilePath: mergedFilePath,
+		TotalChunks:    job.TotalChunks,
+		SHA256:         digest.SHA256,
+	})
+}
+
+func (w *Worker) processRetryMerge(ctx context.Context, job *Job) (jobOutcome, error) {
+	handOff := context.WithoutCancel(ctx)
+	log.Printf("Processing retry merge for file %s (UploadID: %s)", job.Filename, job.UploadID)
+	w.publishMerge(events.MergeStarted, job, nil, false)
+	var expected fl.Digest
+	totalChunks := job.TotalChunks
+	if session, err := w.Sessions.GetSession("", job.UploadID); err == nil {
+		// Retry beklerken iptal edilen upload'ın chunk'ları cleanup ile silinmiştir, durumu değiştirilmez
+		if session.Status == constants.StatusCancelled {
+			log.Printf("Upload %s iptal edildi, retry merge yapılmayacak", job.UploadID)
+			return outcomeFailed, fmt.Errorf("upload iptal edildi")
+		}
+		expected = session.ExpectedDigest()
+		if session.TotalChunks > 0 {
+			totalChunks = session.TotalChunks
+		}
+	}
+	finalPath, totalChunks, digest, err := w.Repo.RetryMerge(job.UploadID, job.Filename, totalChunks, expected)
+	if err != nil {
+		return w.mergeFailed(handOff, job, err)
+	}
+	log.Printf("Retry merge succeeded for %s: %s", job.Filename, finalPath)
+	w.publishMerge(events.MergeFinished, job, nil, false)
+
+	// Shutdown sırasında da merge sonucu iptal edilmemiş context ile devredilir
+	return w.pushProcessed(handOff, ProcessedJob{
+		JobID:          job.ID,
+		UploadID:       job.UploadID,
+		Filename:       job.Filename,
+		MergedFilePath: finalPath,
+		TotalChunks:    totalChunks,
+		SHA256:         digest.SHA256,
+	})
+}
+
+// Merge ve retry job'larının ortak hata yolu: eksik chunk için MaxRetryJobs'a kadar artan gecikmeli retry job'u kuyruğa alınır
+// ve upload bu sürede chunk kabul eder (retrying). Retry hakkı bittiğinde ya da hata tekrar denemeyle düzelmeyecekse
+// (checksum uyuşmazlığı, okuma hatası) upload failed olur
+func (w *Worker) mergeFailed(handOff context.Context, job *Job, err error) (jobOutcome, error) {
+	log.Printf("Merge işlemi başarısız oldu %s: %v", job.Filename, err)
+	job.LastError = err.Error()
+	outcome := outcomeFailed
+	payload := []byte{}
+	if p, marshalErr := json.Marshal(job); marshalErr == nil {
+		payload = p
+	} else {
+		log.Printf("failed merge işlemi için job verisi oluşturulamadı: %v", marshalErr)
+	}
+	if saveErr := w.Repo.SaveFailedUpload(job.UploadID, job.Filename, string(job.Type), err.Error(), payload); saveErr != nil {
+		log.Printf("failed upload kaydı yapılamadı: %v", saveErr)
+	}
+
+	var uploadErr *fe.UploadError
+	if !errors.As(err, &uploadErr) || uploadErr.Code != "missing_chunk" {
+		log.Printf("Merge hatası tekrar denemeyle düzelmez, retry yapılmayacak: %s", job.Filename)
+	} else if job.RetryCount < constants.MaxRetryJobs {
+		retryJob := Job{
+			ID:          job.ID, // client aynı job ID'yi takip etmeye devam eder
+			UploadID:    job.UploadID,
+			Filename:    job.Filename,
+			Type:        JobRetry,
+			TotalChunks: job.TotalChunks,
+			RetryCount:  job.RetryCount + 1,
+		}
+		// Eksik chunk'ların gelmesi için her denemede daha uzun beklenir
+		delay := retryMergeBaseDelay * time.Duration(retryJob.RetryCount)
+		if err := Submit(handOff, w.Jobs, w.Tracker, &retryJob, delay); err != nil {
+			// Retry kuyruğa alınamadıysa upload failed yapılmaz, job tekrar teslim edilir
+			log.Printf("retry job queue'ya eklenemedi: %v / RetryCount: %d", err, retryJob.RetryCount)
+			w.publishMerge(events.Failed, job, err, true)
+			return outcomeRequeue, err
+		}
+		log.Printf("Otomatik retry %v sonra çalışmak üzere queue'ya eklendi: %s (RetryCount: %d)", delay, job.Filename, retryJob.RetryCount)
+		outcome = outcomeRetrying
+	} else {
+		log.Printf("Max retry sayısına ulaşıldı, retry yapılmayacak: %s", job.Filename)
+	}
+	// upload.failed webhook'u yalnızca otomatik retry kalmadığında yazılır
+	if outcome == outcomeRetrying {
+		if statusErr := w.Sessions.UpdateStatus(job.UploadID, constants.StatusRetrying); statusErr != nil {
+			log.Printf("upload durumu güncellenemedi: %v", statusErr)
+		}
+	} else {
+		w.failUpload(job, err)
+	}
+	w.publishMerge(events.Failed, job, err, outcome == outcomeRetrying)
+	return outcome, err
+}
+
+// İş yeniden işlenmez; failed_jobs'a yazılır, admin dead-letter endpoint'inden requeue edilebilir
+func (w *Worker) deadLetter(job *Job, attempts int) {
+	err := fmt.Errorf("iş %d kez teslim edildi ve tamamlanamadı", attempts)
+	log.Printf("Worker %d: %s job %s dead-letter'a yazıldı: %v", w.ID, job.Type, job.ID, err)
+	job.LastError = err.Error()
+	payload, marshalErr := json.Marshal(job)
+	if marshalErr != nil {
+		log.Printf("dead-letter için job verisi oluşturulamadı: %v", marshalErr)
+	}
+	if saveErr := w.Repo.SaveFailedUpload(job.UploadID, job.Filename, string(job.Type), err.Error(), payload); saveErr != nil {
+		log.Printf("failed upload kaydı yapılamadı: %v", saveErr)
+	}
+	Track(w.Tracker, job.ID, constants.JobStatusFailed, err.Error())
+	if job.Type == JobMerge || job.Type == JobRetry {
+		w.failUpload(job, err)
+		w.publishMerge(events.Failed, job, err, false)
+	}
+}
+
+// Upload'ı failed durumuna alır; upload.failed olayı aynı transaction'da outbox'a yazılır
+func (w *Worker) failUpload(job *Job, cause error) {
+	var outbox []*entities.OutboxEvent
+	event, err := entities.NewOutboxEvent(entities.EventUploadFailed, job.UploadID, map[string]interface{}{
+		"upload_id": job.UploadID,
+		"job_id":    job.ID,
+		"filename":  job.Filename,
+		"error":     cause.Error(),
+	})
+	if err != nil {
+		log.Printf("upload.failed olayı oluşturulamadı: %v", err)
+	} else {
+		outbox = append(outbox, event)
+	}
+	if statusErr := w.Sessions.UpdateStatusWithEvents(job.UploadID, constants.StatusFailed, outbox...); statusErr != nil {
+		log.Printf("upload durumu güncellenemedi: %v", statusErr)
+	}
 }
 
-func (w *Worker) processCleanup(job Job) error {
-	return w.Repo.CleanupTempFiles(job.UploadID)
+func (w *Worker) publishMerge(eventType string, job *Job, err error, retrying bool) {
+	event := events.Event{
+		Type:     eventType,
+		UploadID: job.UploadID,
+		JobID:    job.ID,
+		Filename: job.Filename,
+		Retrying: retrying,
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	events.Publish(w.Events, event)
+}
+
+// Push to processed queue for callback; kuyruğa yazılamazsa iş tekrar teslim edilmek üzere geri bırakılır
+func (w *Worker) pushProcessed(ctx context.Context, processed ProcessedJob) (jobOutcome, error) {
+	serialized, err := json.Marshal(processed)
+	if err != nil {
+		log.Printf("Failed to serialize processed job %s: %v", processed.Filename, err)
+		return outcomeFailed, err
+	}
+	if err := w.Processed.Enqueue(ctx, serialized); err != nil {
+		log.Printf("Processed job kuyruğa eklenemedi %s: %v", processed.Filename, err)
+		return outcomeRequeue, err
+	}
+	log.Printf("Processed job pushed to %s: %s", ProcessedStream, processed.Filename)
+	return outcomeHandedOff, nil
+}
+
+func (w *Worker) processCleanup(job *Job) (jobOutcome, error) {
+	log.Printf("Processing cleanup for UploadID: %s", job.UploadID)
+	if err := w.Repo.CleanupTempFiles(job.UploadID); err != nil {
+		log.Printf("Cleanup failed for UploadID %s: %v", job.UploadID, err)
+		return outcomeFailed, err
+	}
+	log.Printf("Cleanup completed for UploadID: %s", job.UploadID)
+	return outcomeSucceeded, nil
 }
 
 func DeserializeJob(data string) (*Job, error) {
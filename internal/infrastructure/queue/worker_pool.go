@@ -3,29 +3,36 @@ package queue
 import (
 	"context"
 	"file-uploader/internal/domain/repositories"
+	"file-uploader/internal/infrastructure/events"
 	"sync"
+	"time"
 )
 
 type WorkerPool struct {
-	JobChan chan Job
-	wg      sync.WaitGroup
-	ctx     context.Context    //graceful shutdown için
-	cancel  context.CancelFunc //graceful shutdown için
+	wg     sync.WaitGroup
+	ctx    context.Context    //graceful shutdown için
+	cancel context.CancelFunc //graceful shutdown için
 }
 
-func NewWorkerPool(workerCount int, repo repositories.FileUploadRepository) *WorkerPool {
+// Kuyruktan iş alan workerCount adet worker başlatır; cmd/worker ve server içi (in-process) kurulumda kullanılır
+// heartbeat, iş sürerken mesajın görünürlük süresinin yenilenme aralığıdır (bkz. HeartbeatInterval)
+func NewWorkerPool(workerCount int, heartbeat time.Duration, jobs, processed JobQueue, repo repositories.FileUploadRepository, sessions repositories.UploadSessionStore, tracker repositories.JobRepository, publisher events.Publisher) *WorkerPool {
 	ctx, cancel := context.WithCancel(context.Background())
 	pool := &WorkerPool{
-		JobChan: make(chan Job, 100),
-		ctx:     ctx,
-		cancel:  cancel,
+		ctx:    ctx,
+		cancel: cancel,
 	}
 	for i := 0; i < workerCount; i++ {
 		worker := &Worker{
-			ID:      i,
-			JobChan: pool.JobChan,
-			Wg:      &pool.wg,
-			Repo:    repo,
+			ID:        i,
+			Jobs:      jobs,
+			Processed: processed,
+			Wg:        &pool.wg,
+			Repo:      repo,
+			Sessions:  sessions,
+			Tracker:   tracker,
+			Events:    publisher,
+			Heartbeat: heartbeat,
 		}
 		pool.wg.Add(1)
 		worker.Start(pool.ctx)
@@ -33,32 +40,8 @@ func NewWorkerPool(workerCount int, repo repositories.FileUploadRepository) *Wor
 	return pool
 }
 
-func NewMediaWorkerPool(workerCount int, rep repositories.MediaRepository) *WorkerPool {
-	ctx, cancel := context.WithCancel(context.Background())
-	pool := &WorkerPool{
-		JobChan: make(chan Job, 100),
-		ctx:     ctx,
-		cancel:  cancel,
-	}
-	for i := 0; i < workerCount; i++ {
-		worker := &WorkerMedia{
-			ID:      i,
-			JobChan: pool.JobChan,
-			Wg:      &pool.wg,
-			Repo:    rep,
-		}
-		pool.wg.Add(1)
-		worker.Start(pool.ctx)
-	}
-	return pool
-}
-
-func (p *WorkerPool) AddJob(job Job) {
-	p.JobChan <- job
-}
-
+// Yeni iş alınmaz, elindeki işi bitiren worker'lar beklenir
 func (p *WorkerPool) Shutdown() {
 	p.cancel()
-	close(p.JobChan)
 	p.wg.Wait()
 }
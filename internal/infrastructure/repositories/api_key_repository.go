@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"errors"
+	"file-uploader/internal/domain/entities"
+	"file-uploader/internal/domain/repositories"
+	fe "file-uploader/pkg/errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) repositories.APIKeyRepository {
+	return &apiKeyRepository{
+		db: db,
+	}
+}
+
+func (r *apiKeyRepository) Create(key *entities.APIKey) error {
+	return r.db.Create(key).Error
+}
+
+func (r *apiKeyRepository) GetActiveByHash(keyHash string) (*entities.APIKey, error) {
+	var key entities.APIKey
+	if err := r.db.Where("key_hash = ? AND revoked_at IS NULL", keyHash).First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fe.ErrNotFound(err)
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepository) List(ownerID string) ([]entities.APIKey, error) {
+	var keys []entities.APIKey
+	err := r.db.Scopes(ownedBy(ownerID)).Order("created_at DESC").Find(&keys).Error
+	return keys, err
+}
+
+func (r *apiKeyRepository) Revoke(id string) error {
+	result := r.db.Model(&entities.APIKey{}).Where("id = ? AND revoked_at IS NULL", id).Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fe.ErrNotFound(gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+func (r *apiKeyRepository) TouchLastUsed(id string) error {
+	return r.db.Model(&entities.APIKey{}).Where("id = ?", id).Update("last_used_at", time.Now()).Error
+}
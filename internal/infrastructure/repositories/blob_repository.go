@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"errors"
+	"file-uploader/internal/domain/entities"
+	"file-uploader/internal/domain/repositories"
+	fe "file-uploader/pkg/errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type blobRepository struct {
+	db *gorm.DB
+}
+
+func NewBlobRepository(db *gorm.DB) repositories.BlobRepository {
+	return &blobRepository{
+		db: db,
+	}
+}
+
+// Aynı anda gelen iki upload'ın ikisinin de blob oluşturmaması için satır kilitlenir
+func (r *blobRepository) Acquire(sha256, filePath string, size int64) (*entities.Blob, bool, error) {
+	var blob entities.Blob
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		insert := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&entities.Blob{
+			SHA256:    sha256,
+			FilePath:  filePath,
+			Size:      size,
+			RefCount:  1,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+		if insert.Error != nil {
+			return insert.Error
+		}
+		created = insert.RowsAffected == 1
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&blob, "sha256 = ?", sha256).Error; err != nil {
+			return err
+		}
+		if created {
+			return nil
+		}
+
+		blob.RefCount++
+		blob.UpdatedAt = now
+		return tx.Model(&blob).Updates(map[string]interface{}{
+			"ref_count":  blob.RefCount,
+			"updated_at": blob.UpdatedAt,
+		}).Error
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return &blob, created, nil
+}
+
+func (r *blobRepository) Get(sha256 string) (*entities.Blob, error) {
+	var blob entities.Blob
+	if err := r.db.First(&blob, "sha256 = ?", sha256).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fe.ErrNotFound(err)
+		}
+		return nil, err
+	}
+	return &blob, nil
+}
+
+// Origin yalnızca ilk kez set edilir, sonradan işlenen kopyalar değiştirmez
+func (r *blobRepository) SetOrigin(sha256, originID string) error {
+	return r.db.Model(&entities.Blob{}).
+		Where("sha256 = ? AND (origin_id IS NULL OR origin_id = '')", sha256).
+		Updates(map[string]interface{}{
+			"origin_id":  originID,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+func (r *blobRepository) Relocate(sha256, filePath string) error {
+	return r.db.Model(&entities.Blob{}).
+		Where("sha256 = ?", sha256).
+		Updates(map[string]interface{}{
+			"file_path":  filePath,
+			"origin_id":  "",
+			"updated_at": time.Now(),
+		}).Error
+}
+
+func (r *blobRepository) Release(sha256 string) (*entities.Blob, error) {
+	var blob entities.Blob
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&blob, "sha256 = ?", sha256).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fe.ErrNotFound(err)
+			}
+			return err
+		}
+
+		blob.RefCount--
+		if blob.RefCount <= 0 {
+			blob.RefCount = 0
+			return tx.Delete(&entities.Blob{}, "sha256 = ?", sha256).Error
+		}
+		return tx.Model(&blob).Updates(map[string]interface{}{
+			"ref_count":  blob.RefCount,
+			"updated_at": time.Now(),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &blob, nil
+}
@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"errors"
+	"file-uploader/internal/domain/entities"
+	"file-uploader/internal/domain/repositories"
+	fe "file-uploader/pkg/errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type encryptedObjectRepository struct {
+	db *gorm.DB
+}
+
+func NewEncryptedObjectRepository(db *gorm.DB) repositories.EncryptedObjectRepository {
+	return &encryptedObjectRepository{
+		db: db,
+	}
+}
+
+func (r *encryptedObjectRepository) Get(backend, location string) (*entities.EncryptedObject, error) {
+	var object entities.EncryptedObject
+	if err := r.db.First(&object, "backend = ? AND location = ?", backend, location).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fe.ErrNotFound(err)
+		}
+		return nil, err
+	}
+	return &object, nil
+}
+
+func (r *encryptedObjectRepository) Save(object *entities.EncryptedObject) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "backend"}, {Name: "location"}},
+		DoUpdates: clause.AssignmentColumns([]string{"key_id", "wrapped_key", "size", "updated_at"}),
+	}).Create(object).Error
+}
+
+func (r *encryptedObjectRepository) Delete(backend, location string) error {
+	return r.db.Where("backend = ? AND location = ?", backend, location).Delete(&entities.EncryptedObject{}).Error
+}
+
+func (r *encryptedObjectRepository) ListWrappedWithOtherKey(keyID, afterBackend, afterLocation string, limit int) ([]entities.EncryptedObject, error) {
+	var objects []entities.EncryptedObject
+	err := r.db.
+		Where("key_id <> ? AND (backend, location) > (?, ?)", keyID, afterBackend, afterLocation).
+		Order("backend, location").
+		Limit(limit).
+		Find(&objects).Error
+	return objects, err
+}
+
+func (r *encryptedObjectRepository) Rewrap(backend, location, oldKeyID, newKeyID string, wrappedKey []byte) (bool, error) {
+	result := r.db.Model(&entities.EncryptedObject{}).
+		Where("backend = ? AND location = ? AND key_id = ?", backend, location, oldKeyID).
+		Updates(map[string]interface{}{"key_id": newKeyID, "wrapped_key": wrappedKey, "updated_at": time.Now()})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *encryptedObjectRepository) CountByKey() (map[string]int64, error) {
+	var rows []struct {
+		KeyID string
+		Count int64
+	}
+	if err := r.db.Model(&entities.EncryptedObject{}).Select("key_id, COUNT(*) AS count").Group("key_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.KeyID] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"errors"
+	"file-uploader/internal/domain/entities"
+	"file-uploader/internal/domain/repositories"
+	fe "file-uploader/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+type failedJobRepository struct {
+	db *gorm.DB
+}
+
+func NewFailedJobRepository(db *gorm.DB) repositories.FailedJobRepository {
+	return &failedJobRepository{
+		db: db,
+	}
+}
+
+func applyFailedJobFilter(query *gorm.DB, filter repositories.FailedJobFilter) *gorm.DB {
+	if len(filter.IDs) > 0 {
+		query = query.Where("id IN ?", filter.IDs)
+	}
+	if filter.JobType != "" {
+		query = query.Where("job_type = ?", filter.JobType)
+	}
+	if filter.Status != "" {
+		query = query.Where("job_status = ?", filter.Status)
+	}
+	if !filter.CreatedBefore.IsZero() {
+		query = query.Where("created_at < ?", filter.CreatedBefore)
+	}
+	if !filter.CreatedAfter.IsZero() {
+		query = query.Where("created_at > ?", filter.CreatedAfter)
+	}
+	return query
+}
+
+func (r *failedJobRepository) List(filter repositories.FailedJobFilter, offset, limit int) ([]entities.FailedJob, int64, error) {
+	var total int64
+	if err := applyFailedJobFilter(r.db.Model(&entities.FailedJob{}), filter).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var jobs []entities.FailedJob
+	err := applyFailedJobFilter(r.db.Model(&entities.FailedJob{}), filter).
+		Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&jobs).Error
+	return jobs, total, err
+}
+
+func (r *failedJobRepository) GetByID(id uint) (*entities.FailedJob, error) {
+	var job entities.FailedJob
+	if err := r.db.First(&job, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fe.ErrNotFound(err)
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *failedJobRepository) Find(filter repositories.FailedJobFilter) ([]entities.FailedJob, error) {
+	var jobs []entities.FailedJob
+	err := applyFailedJobFilter(r.db.Model(&entities.FailedJob{}), filter).
+		Order("created_at, id").
+		Find(&jobs).Error
+	return jobs, err
+}
+
+func (r *failedJobRepository) UpdateStatus(ids []uint, status string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&entities.FailedJob{}).Where("id IN ?", ids).Update("job_status", status).Error
+}
+
+func (r *failedJobRepository) Delete(ids []uint) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	result := r.db.Where("id IN ?", ids).Delete(&entities.FailedJob{})
+	return result.RowsAffected, result.Error
+}
+
+type auditLogRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditLogRepository(db *gorm.DB) repositories.AuditLogRepository {
+	return &auditLogRepository{
+		db: db,
+	}
+}
+
+func (r *auditLogRepository) Record(entry *entities.AdminAuditLog) error {
+	return r.db.Create(entry).Error
+}
+
+func (r *auditLogRepository) List(action string, offset, limit int) ([]entities.AdminAuditLog, int64, error) {
+	scoped := func() *gorm.DB {
+		query := r.db.Model(&entities.AdminAuditLog{})
+		if action != "" {
+			query = query.Where("action = ?", action)
+		}
+		return query
+	}
+
+	var total int64
+	if err := scoped().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []entities.AdminAuditLog
+	err := scoped().Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&entries).Error
+	return entries, total, err
+}
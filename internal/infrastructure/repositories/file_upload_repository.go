@@ -1,8 +1,12 @@
 package repositories
 
 import (
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"errors"
+	"file-uploader/internal/domain/entities"
+	"file-uploader/internal/domain/repositories"
 	fe "file-uploader/pkg/errors"
 	fl "file-uploader/pkg/file"
 	"fmt"
@@ -19,30 +23,35 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// Server'ın stream ettiği, worker tarafından henüz işlenmemiş chunk'ların tutulduğu alt klasör
+const stagingDirName = "staging"
+
 type FileUploadRepository struct {
 	tempDir      string
 	uploadsDir   string
-	mergedChunks map[string]int // key: uploadID + filename, value: merged chunk sayısı
-	chunkMutex   sync.RWMutex
+	chunkPoolDir string
 	fileMutex    sync.Mutex
 	activeOps    map[string]int
 	opsMutex     sync.Mutex
 	db           *gorm.DB
+	storage      repositories.StorageStrategy // birleşik dosyaların yazıldığı backend
 }
 
 func (r *FileUploadRepository) UploadsDir() string {
 	return r.uploadsDir
 }
 
-func NewFileUploadRepository(tempDir, uploadsDir string, db *gorm.DB) *FileUploadRepository {
+func NewFileUploadRepository(tempDir, uploadsDir, chunkPoolDir string, storage repositories.StorageStrategy, db *gorm.DB) *FileUploadRepository {
 	return &FileUploadRepository{
 		tempDir:      tempDir,
 		uploadsDir:   uploadsDir,
-		mergedChunks: make(map[string]int),
+		chunkPoolDir: chunkPoolDir,
 		activeOps:    make(map[string]int),
 		db:           db,
+		storage:      storage,
 	}
 }
 
@@ -154,44 +163,100 @@ func (r *FileUploadRepository) SaveChunkBytes(uploadID, filename string, chunkIn
 	return nil
 }
 
-func (r *FileUploadRepository) ChunkExists(uploadID, filename string, chunkIndex int) bool {
-	saveDir := filepath.Join(r.tempDir, uploadID)
-	finalPath := filepath.Join(saveDir, fmt.Sprintf("%s.part%d", filename, chunkIndex))
-	_, err := os.Stat(finalPath)
-	return err == nil
+// Chunk gövdesi belleğe alınmadan staging klasörüne stream edilir, SHA-256 yazma sırasında hesaplanır.
+// Staging, upload'ın temp klasörü altında olduğu için cleanup job'u ile birlikte silinir
+func (r *FileUploadRepository) StageChunk(uploadID, filename string, chunkIndex int, src io.Reader) (string, string, int64, error) {
+	r.incrementActiveOps(uploadID)
+	defer r.decrementActiveOps(uploadID)
+
+	stagingDir := filepath.Join(r.tempDir, uploadID, stagingDirName)
+	if err := os.MkdirAll(stagingDir, os.ModePerm); err != nil {
+		return "", "", 0, fmt.Errorf("staging klasörü oluşturulamadı: %w", err)
+	}
+
+	stagingPath := filepath.Join(stagingDir, fmt.Sprintf("%s.part%d.%d", filename, chunkIndex, time.Now().UnixNano()))
+	file, err := os.Create(stagingPath)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("staging dosyası oluşturulamadı: %w", err)
+	}
+
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(file, h), src)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(stagingPath)
+		return "", "", 0, fmt.Errorf("chunk staging'e yazılamadı: %w", err)
+	}
+
+	return stagingPath, hex.EncodeToString(h.Sum(nil)), size, nil
 }
 
-func (r *FileUploadRepository) SetUploadedChunks(uploadID, filename string, merged int) error {
-	log.Printf("Buraya eriştin, set uploaded chunks")
-	r.chunkMutex.Lock()
-	defer r.chunkMutex.Unlock()
+// Staging'deki chunk asıl chunk konumuna taşınır (aynı dosya sisteminde rename, değilse kopyalama)
+func (r *FileUploadRepository) CommitStagedChunk(uploadID, filename string, chunkIndex int, stagingPath string) error {
+	if err := r.checkStagingPath(uploadID, stagingPath); err != nil {
+		return err
+	}
+
+	r.incrementActiveOps(uploadID)
+	defer r.decrementActiveOps(uploadID)
 
-	if r.mergedChunks == nil {
-		r.mergedChunks = make(map[string]int)
-		fmt.Printf("DEBUG: mergedChunks map oluşturuldu\n")
+	r.fileMutex.Lock()
+	defer r.fileMutex.Unlock()
+
+	finalPath := filepath.Join(r.tempDir, uploadID, fmt.Sprintf("%s.part%d", filename, chunkIndex))
+	if r.ChunkExists(uploadID, filename, chunkIndex) {
+		os.Remove(stagingPath)
+		return nil
 	}
 
-	key := fl.MakeKey(uploadID, filename)
-	r.mergedChunks[key] = merged
+	if err := os.Rename(stagingPath, finalPath); err != nil {
+		if copyErr := fl.CopyFile(stagingPath, finalPath); copyErr != nil {
+			return fmt.Errorf("chunk yazılamadı: %w", copyErr)
+		}
+		os.Remove(stagingPath)
+	}
+	return nil
+}
 
-	fmt.Printf("DEBUG: SET - Key: %s, Chunk sayısı: %d\n", key, merged)
+// Doğrulanamayan ya da artık gerekmeyen staging dosyasını siler
+func (r *FileUploadRepository) DiscardStagedChunk(uploadID, stagingPath string) error {
+	if err := r.checkStagingPath(uploadID, stagingPath); err != nil {
+		return err
+	}
+	if err := os.Remove(stagingPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("staging dosyası silinemedi: %w", err)
+	}
 	return nil
 }
 
-func (r *FileUploadRepository) GetUploadedChunks(uploadID, filename string) (int, bool) {
-	r.chunkMutex.RLock()
-	defer r.chunkMutex.RUnlock()
+// Job içindeki yolun bu upload'ın staging klasörü dışına çıkmaması için
+func (r *FileUploadRepository) checkStagingPath(uploadID, stagingPath string) error {
+	stagingDir := filepath.Join(r.tempDir, uploadID, stagingDirName)
+	if filepath.Dir(filepath.Clean(stagingPath)) != stagingDir {
+		return fmt.Errorf("geçersiz staging yolu: %s", stagingPath)
+	}
+	return nil
+}
 
-	key := fl.MakeKey(uploadID, filename)
-	fmt.Printf("DEBUG: GET - Looking for key: %s\n", key)
+// Yarım kalmış/doğrulanmamış bir chunk dosyasını siler
+func (r *FileUploadRepository) DeleteChunk(uploadID, filename string, chunkIndex int) error {
+	r.fileMutex.Lock()
+	defer r.fileMutex.Unlock()
 
-	if r.mergedChunks == nil {
-		return 0, false
+	partPath := filepath.Join(r.tempDir, uploadID, fmt.Sprintf("%s.part%d", filename, chunkIndex))
+	if err := os.Remove(partPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("chunk dosyası silinemedi: %w", err)
 	}
+	return nil
+}
 
-	merged, exists := r.mergedChunks[key]
-	fmt.Printf("DEBUG: GET - Found: %t, Chunk sayısı: %d\n", exists, merged)
-	return merged, exists
+func (r *FileUploadRepository) ChunkExists(uploadID, filename string, chunkIndex int) bool {
+	saveDir := filepath.Join(r.tempDir, uploadID)
+	finalPath := filepath.Join(saveDir, fmt.Sprintf("%s.part%d", filename, chunkIndex))
+	_, err := os.Stat(finalPath)
+	return err == nil
 }
 
 func (r *FileUploadRepository) CleanupTempFiles(uploadID string) error {
@@ -226,27 +291,14 @@ func (r *FileUploadRepository) CleanupTempFiles(uploadID string) error {
 			continue
 		}
 
-		// Başarılı, chunk tracking'i temizle
-		r.cleanupChunkTracking(uploadID)
 		return nil
 	}
 
 	return fmt.Errorf("cleanup başarısız (3 deneme): %w", lastErr)
 }
 
-func (r *FileUploadRepository) cleanupChunkTracking(uploadID string) {
-	r.chunkMutex.Lock()
-	defer r.chunkMutex.Unlock()
-
-	// Bu uploadID ile ilgili tüm kayıtları sil
-	for key := range r.mergedChunks {
-		if len(key) > len(uploadID) && key[:len(uploadID)] == uploadID {
-			delete(r.mergedChunks, key)
-		}
-	}
-}
-
-func (r *FileUploadRepository) MergeChunks(uploadID, filename string, totalChunks int) (string, error) {
+// Chunk'lar birleştirilirken dosyanın özetleri hesaplanır, expected içindeki dolu alanlar ile uyuşmazsa birleşik dosya silinir
+func (r *FileUploadRepository) MergeChunks(uploadID, filename string, totalChunks int, expected fl.Digest) (string, fl.Digest, error) {
 	r.incrementActiveOps(uploadID)
 	defer r.decrementActiveOps(uploadID)
 
@@ -255,99 +307,86 @@ func (r *FileUploadRepository) MergeChunks(uploadID, filename string, totalChunk
 
 	saveDir := filepath.Join(r.tempDir, uploadID)
 	finalFileName := fl.MakeKey(uploadID, filename)
-	//finalPath := filepath.Join(r.uploadsDir, "media", "original", finalFileName)
-	isImageFile := fl.IsImageFile(finalFileName)
-	finalPath := ""
-	if isImageFile {
-		finalPath = filepath.Join(r.uploadsDir, "media", "original", finalFileName)
-	} else if fl.IsVideoFile(finalFileName) {
-		finalPath = filepath.Join(r.uploadsDir, "videos", "original", finalFileName)
-	} else {
-		finalPath = filepath.Join(r.uploadsDir, "other", finalFileName) //* video için de isVideoFile fonksiyonu yazılıp buraya eklenecek!!!
-	}
+	key := fl.OriginalKey(finalFileName)
+
+	fmt.Printf("DEBUG: Merging to %s\n", key) // Debug log
 
-	fmt.Printf("DEBUG: Merging to %s\n", finalPath) // Debug log
+	// /chunks/query ile havuzdan bağlanan chunk'ların temp klasörde part dosyası yoktur
+	ownerID := r.sessionOwner(uploadID)
+	pooled, err := r.pooledSources(ownerID, uploadID, filename)
+	if err != nil {
+		log.Printf("UYARI: Havuzdaki chunk'lar okunamadı %s: %v", uploadID, err)
+		pooled = map[int]chunkSource{}
+	}
 
 	// Eksik chunk kontrolü
 	missing := make([]int, 0)
 	for i := 1; i <= totalChunks; i++ {
-		if !r.ChunkExists(uploadID, filename, i) {
+		if _, ok := pooled[i]; !ok && !r.ChunkExists(uploadID, filename, i) {
 			missing = append(missing, i)
 		}
 	}
 	if len(missing) > 0 {
-		return "", fe.ErrMissingChunk(fmt.Errorf("eksik chunk(lar) var: %v", missing))
-	}
-
-	// uploads klasörünü oluştur
-	if err := os.MkdirAll(r.uploadsDir, os.ModePerm); err != nil {
-		return "", fmt.Errorf("uploads klasörü oluşturulamadı: %w", err) // Return boş string ve error
+		return "", fl.Digest{}, fe.ErrMissingChunk(fmt.Errorf("eksik chunk(lar) var: %v", missing))
 	}
 
-	// Eğer dosya zaten varsa, eski olanı backup al
-	if _, err := os.Stat(finalPath); err == nil {
-		backupPath := finalPath + ".backup." + fmt.Sprintf("%d", time.Now().Unix())
-		if renameErr := os.Rename(finalPath, backupPath); renameErr != nil {
-			log.Printf("UYARI: Eski dosya backup alınamadı: %v", renameErr)
-		} else {
-			fmt.Printf("DEBUG: Eski dosya backup alındı: %s\n", backupPath)
-		}
-	}
-
-	outFile, err := os.Create(finalPath)
+	// Dosya önce temp klasörde birleştirilir, doğrulandıktan sonra storage'a yazılır
+	outFile, mergePath, err := r.createMergeFile(finalFileName)
 	if err != nil {
-		/*
-			if cleanupErr := r.CleanupTempFiles(uploadID); cleanupErr != nil {
-				log.Printf("UYARI! Temp klasörü temizlenemedi: %v", cleanupErr)
-			}
-		*/
-		return "", fmt.Errorf("final dosya oluşturulamadı: %w", err) // Return boş string ve error
+		return "", fl.Digest{}, err
 	}
-	defer outFile.Close()
+	defer r.discardMergeFile(outFile, mergePath)
 
-	merged := make([]int, 0, totalChunks)
+	merged := make([]chunkSource, 0, totalChunks)
+	digestWriter := fl.NewDigestWriter()
+	writer := io.MultiWriter(outFile, digestWriter)
 
 	// chunkları birleştir
 	for i := 1; i <= totalChunks; i++ {
-		partPath := filepath.Join(saveDir, fmt.Sprintf("%s.part%d", filename, i))
-		func() {
-			partFile, err := os.Open(partPath)
-			if err != nil {
-				log.Printf("UYARI: Chunk %d açılamadı: %v", i, err)
-				return
-			}
-			defer partFile.Close()
-
-			bytesWritten, err := io.Copy(outFile, partFile)
-			if err != nil {
-				log.Printf("UYARI: Chunk %d kopyalanamadı: %v", i, err)
-				return
-			}
+		source, ok := pooled[i]
+		if !ok {
+			source = chunkSource{Index: i, Path: filepath.Join(saveDir, fmt.Sprintf("%s.part%d", filename, i))}
+		}
+		if err := r.copyChunk(writer, &source); err != nil {
+			log.Printf("UYARI: Chunk %d kopyalanamadı: %v", i, err)
+			continue
+		}
 
-			merged = append(merged, i)
-			fmt.Printf("DEBUG: Chunk %d merged, %d bytes\n", i, bytesWritten)
-		}()
+		merged = append(merged, source)
+		fmt.Printf("DEBUG: Chunk %d merged, %d bytes\n", i, source.Size)
 	}
 
 	// Tüm chunk'ların başarıyla merge edilip edilmediğini kontrol et
 	if len(merged) != totalChunks {
-		return "", fe.ErrChunksNotMerged(fmt.Errorf("%d/%d chunk merge edildi", len(merged), totalChunks))
+		return "", fl.Digest{}, fe.ErrChunksNotMerged(fmt.Errorf("%d/%d chunk merge edildi", len(merged), totalChunks))
 	}
 
-	// Başarılı chunk'ları kaydet
-	r.SetUploadedChunks(uploadID, filename, len(merged))
+	// Bütünlük kontrolü: uyuşmazlıkta chunk'lar incelenebilmesi için silinmez
+	digest := digestWriter.Digest()
+	if err := expected.Verify(digest); err != nil {
+		return "", digest, fe.ErrChecksumMismatch(err)
+	}
 
 	// Dosya boyutunu kontrol et
 	if stat, err := outFile.Stat(); err == nil {
 		fmt.Printf("DEBUG: Final file size: %d bytes\n", stat.Size())
 	}
 
+	// Storage'a yazılamazsa chunk'lar tekrar denenebilmesi için yerinde bırakılır
+	finalPath, err := r.storeMergeFile(outFile, mergePath, key)
+	if err != nil {
+		return "", digest, err
+	}
+
+	r.poolChunks(ownerID, merged)
 	r.cleanupChunkFiles(saveDir, filename, totalChunks)
 
-	return finalPath, nil
+	return finalPath, digest, nil
 }
 
-func (r *FileUploadRepository) RetryMerge(uploadID, filename string) (string, int, error) {
+// Temp klasörde bulunan chunk'lar sırayla birleştirilir, MergeChunks'taki gibi expected özetlerle doğrulanır.
+// totalChunks bilinmiyorsa (0) bulunan chunk'lar 1'den başlayıp boşluksuz ilerlemeli
+func (r *FileUploadRepository) RetryMerge(uploadID, filename string, totalChunks int, expected fl.Digest) (string, int, fl.Digest, error) {
 	log.Printf("DEBUG! Fonksiyon içindesin")
 	r.incrementActiveOps(uploadID)
 	defer r.decrementActiveOps(uploadID)
@@ -357,38 +396,40 @@ func (r *FileUploadRepository) RetryMerge(uploadID, filename string) (string, in
 
 	saveDir := filepath.Join(r.tempDir, uploadID)
 	finalFileName := fl.MakeKey(uploadID, filename)
-
-	type chunkFile struct {
-		Name  string
-		Index int
-	}
-
-	finalPath := ""
-	if fl.IsImageFile(finalFileName) {
-		finalPath = filepath.Join(r.uploadsDir, "media", "original", finalFileName)
-	} else if fl.IsVideoFile(finalFileName) {
-		finalPath = filepath.Join(r.uploadsDir, "videos", "original", finalFileName)
-	} else {
-		finalPath = filepath.Join(r.uploadsDir, "other", finalFileName)
-	}
+	key := fl.OriginalKey(finalFileName)
 
 	// Temp klasördeki mevcut chunkları listele
 	files, err := os.ReadDir(saveDir)
 	if err != nil {
-		return "", 0, fmt.Errorf("temp klasör okunamadı: %w", err)
+		return "", 0, fl.Digest{}, fmt.Errorf("temp klasör okunamadı: %w", err)
 	}
 
-	chunks := make([]chunkFile, 0)
+	chunks := make([]chunkSource, 0)
+	listed := make(map[int]bool)
 	for _, f := range files {
-		if !f.IsDir() && strings.HasPrefix(f.Name(), filename) {
-			ext := filepath.Ext(f.Name())
+		ext := filepath.Ext(f.Name())
+		// Yalnızca "<filename>.partN"; aynı önekle başlayan başka dosyaların part'ları karışmaz
+		if !f.IsDir() && strings.TrimSuffix(f.Name(), ext) == filename {
 			indexStr := strings.TrimPrefix(ext, ".part")
 			index, err := strconv.Atoi(indexStr)
 			if err != nil {
 				log.Printf("UYARI: Geçersiz chunk dosyası ismi: %s", f.Name())
 				continue
 			}
-			chunks = append(chunks, chunkFile{Name: f.Name(), Index: index})
+			chunks = append(chunks, chunkSource{Index: index, Path: filepath.Join(saveDir, f.Name())})
+			listed[index] = true
+		}
+	}
+
+	// Temp klasörde olmayan, havuzdan bağlanmış chunk'lar da birleştirmeye dahil edilir
+	ownerID := r.sessionOwner(uploadID)
+	pooled, err := r.pooledSources(ownerID, uploadID, filename)
+	if err != nil {
+		log.Printf("UYARI: Havuzdaki chunk'lar okunamadı %s: %v", uploadID, err)
+	}
+	for index, source := range pooled {
+		if !listed[index] {
+			chunks = append(chunks, source)
 		}
 	}
 
@@ -397,59 +438,95 @@ func (r *FileUploadRepository) RetryMerge(uploadID, filename string) (string, in
 	})
 
 	if len(chunks) == 0 {
-		return "", 0, fe.ErrMissingChunk(fmt.Errorf("merge için temp chunk bulunamadı"))
+		return "", 0, fl.Digest{}, fe.ErrMissingChunk(fmt.Errorf("merge için temp chunk bulunamadı"))
 	}
-
-	// uploads klasörünü oluştur
-	if err := os.MkdirAll(filepath.Dir(finalPath), os.ModePerm); err != nil {
-		return "", 0, fmt.Errorf("uploads klasörü oluşturulamadı: %w", err)
+	// Eksik chunk'la birleştirilen dosya, client özet göndermediyse fark edilmeden tamamlanır
+	if totalChunks <= 0 {
+		totalChunks = len(chunks)
+	}
+	for i := 0; i < totalChunks; i++ {
+		if i >= len(chunks) || chunks[i].Index != i+1 {
+			return "", 0, fl.Digest{}, fe.ErrMissingChunk(fmt.Errorf("chunk %d bulunamadı (toplam %d)", i+1, totalChunks))
+		}
 	}
+	// Toplamdan sonraki fazla part dosyaları birleştirmeye girmez
+	chunks = chunks[:totalChunks]
 
-	outFile, err := os.Create(finalPath)
+	outFile, mergePath, err := r.createMergeFile(finalFileName)
 	if err != nil {
-		return "", 0, fmt.Errorf("final dosya oluşturulamadı: %w", err)
+		return "", 0, fl.Digest{}, err
 	}
-	defer outFile.Close()
+	defer r.discardMergeFile(outFile, mergePath)
 
-	merged := 0
+	merged := make([]chunkSource, 0, len(chunks))
+	digestWriter := fl.NewDigestWriter()
+	writer := io.MultiWriter(outFile, digestWriter)
 	for _, chunk := range chunks {
-		partPath := filepath.Join(saveDir, chunk.Name)
-		partFile, err := os.Open(partPath)
-		if err != nil {
-			log.Printf("UYARI: Chunk açılamadı %s: %v", chunk.Name, err)
-			continue
-		}
-		bytesWritten, err := io.Copy(outFile, partFile)
-		partFile.Close()
-		if err != nil {
-			log.Printf("UYARI: Chunk kopyalanamadı %s: %v", chunk.Name, err)
-			continue
+		if err := r.copyChunk(writer, &chunk); err != nil {
+			return "", 0, fl.Digest{}, fe.ErrMissingChunk(fmt.Errorf("chunk %d okunamadı: %w", chunk.Index, err))
 		}
-		log.Printf("DEBUG: Chunk %s merged, %d bytes", chunk.Name, bytesWritten)
-		merged++
+		log.Printf("DEBUG: Chunk %s merged, %d bytes", chunk.Path, chunk.Size)
+		merged = append(merged, chunk)
 	}
 
-	if merged == 0 {
-		return "", 0, fe.ErrChunksNotMerged(fmt.Errorf("hiç chunk merge edilemedi"))
+	digest := digestWriter.Digest()
+	if err := expected.Verify(digest); err != nil {
+		return "", 0, digest, fe.ErrChecksumMismatch(err)
 	}
 
-	r.SetUploadedChunks(uploadID, filename, merged)
-	log.Printf("Set uploaded yapıldı")
+	finalPath, err := r.storeMergeFile(outFile, mergePath, key)
+	if err != nil {
+		return "", 0, digest, err
+	}
 
+	r.poolChunks(ownerID, merged)
 	if err := os.RemoveAll(saveDir); err != nil { //bunu düzenlemem lazım
 		log.Printf("UYARI: Temp klasör silinemedi %s: %v", saveDir, err)
 	} else {
 		log.Printf("DEBUG: Temp klasör silindi: %s", saveDir)
 	}
 
-	return finalPath, merged, nil
+	return finalPath, len(merged), digest, nil
+}
+
+// Birleştirme upload'ın temp klasörü dışında yapılır; RetryMerge klasördeki dosyaları chunk olarak listeler
+func (r *FileUploadRepository) createMergeFile(finalFileName string) (*os.File, string, error) {
+	if err := os.MkdirAll(r.tempDir, os.ModePerm); err != nil {
+		return nil, "", fmt.Errorf("temp klasörü oluşturulamadı: %w", err)
+	}
+	mergePath := filepath.Join(r.tempDir, fmt.Sprintf("%s.merging.%d", finalFileName, time.Now().UnixNano()))
+	outFile, err := os.Create(mergePath)
+	if err != nil {
+		return nil, "", fmt.Errorf("final dosya oluşturulamadı: %w", err)
+	}
+	return outFile, mergePath, nil
+}
+
+func (r *FileUploadRepository) storeMergeFile(outFile *os.File, mergePath, key string) (string, error) {
+	if err := outFile.Close(); err != nil {
+		return "", fmt.Errorf("final dosya kapatılamadı: %w", err)
+	}
+	finalPath, err := r.storage.Store(mergePath, key)
+	if err != nil {
+		return "", fmt.Errorf("birleşik dosya storage'a yazılamadı: %w", err)
+	}
+	return finalPath, nil
+}
+
+// Başarısız ya da doğrulanamayan birleştirmenin geçici dosyası silinir (Store sonrası dosya zaten yoktur)
+func (r *FileUploadRepository) discardMergeFile(outFile *os.File, mergePath string) {
+	outFile.Close()
+	if err := os.Remove(mergePath); err != nil && !os.IsNotExist(err) {
+		log.Printf("UYARI: Geçici birleşik dosya silinemedi %s: %v", mergePath, err)
+	}
 }
 
 // Helper function: Chunk dosyalarını temizle
 func (r *FileUploadRepository) cleanupChunkFiles(saveDir, filename string, totalChunks int) {
 	for i := 1; i <= totalChunks; i++ {
 		partPath := filepath.Join(saveDir, fmt.Sprintf("%s.part%d", filename, i))
-		if err := os.Remove(partPath); err != nil {
+		// Havuza taşınan ya da havuzdan okunan chunk'ların part dosyası yoktur
+		if err := os.Remove(partPath); err != nil && !os.IsNotExist(err) {
 			log.Printf("UYARI: Chunk dosyası silinemedi %s: %v", partPath, err)
 		}
 	}
@@ -511,3 +588,185 @@ func (r *FileUploadRepository) UpdateRetryStatus(uploadID, status string) error
 	}
 	return nil
 }
+
+// Merge'de okunacak chunk: temp klasördeki part dosyası ya da havuzdan bağlanmış chunk
+type chunkSource struct {
+	Index  int
+	Path   string
+	Hash   string // havuzdan okunan chunk için beklenen, part dosyası için merge sırasında hesaplanan hash
+	Size   int64
+	Pooled bool
+}
+
+// Her tenant'ın havuzu ayrı klasördedir; bir tenant'ın purge'ü diğerinin dosyasını silmez.
+// Tenant kimliği dosya yolunda ham kullanılmaz, özetlenir
+func (r *FileUploadRepository) pooledChunkPath(ownerID, hash string) string {
+	owner := sha256.Sum256([]byte(ownerID))
+	return filepath.Join(r.chunkPoolDir, hex.EncodeToString(owner[:16]), hash[:2], hash)
+}
+
+// Havuz kayıtları upload'ı açan tenant'a yazılır ve yalnızca onun upload'larına bağlanır
+func (r *FileUploadRepository) sessionOwner(uploadID string) string {
+	var session entities.Upload
+	if err := r.db.Select("owner_id").Where("id = ?", uploadID).First(&session).Error; err != nil {
+		log.Printf("UYARI: Upload oturumu okunamadı %s: %v", uploadID, err)
+		return ""
+	}
+	return session.OwnerID
+}
+
+// upload_chunks'ta hash'i kayıtlı olup temp klasörde part dosyası bulunmayan chunk'lar tenant'ın havuzundan okunur
+func (r *FileUploadRepository) pooledSources(ownerID, uploadID, filename string) (map[int]chunkSource, error) {
+	var chunks []entities.UploadChunk
+	if err := r.db.Where("upload_id = ?", uploadID).Find(&chunks).Error; err != nil {
+		return nil, err
+	}
+
+	candidates := make([]entities.UploadChunk, 0, len(chunks))
+	hashes := make([]string, 0, len(chunks))
+	for _, chunk := range chunks {
+		if _, err := hex.DecodeString(chunk.Hash); err != nil || len(chunk.Hash) != sha256.Size*2 {
+			continue
+		}
+		if r.ChunkExists(uploadID, filename, chunk.ChunkIndex) {
+			continue
+		}
+		candidates = append(candidates, chunk)
+		hashes = append(hashes, chunk.Hash)
+	}
+	pool, err := r.FindPooledChunks(ownerID, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make(map[int]chunkSource)
+	for _, chunk := range candidates {
+		pooled, ok := pool[chunk.Hash]
+		if !ok {
+			continue
+		}
+		sources[chunk.ChunkIndex] = chunkSource{
+			Index:  chunk.ChunkIndex,
+			Path:   pooled.FilePath,
+			Hash:   chunk.Hash,
+			Pooled: true,
+		}
+	}
+	return sources, nil
+}
+
+// Chunk'ı writer'a kopyalarken hash'ini hesaplar; havuzdan okunan chunk bozulmuşsa hata döner
+func (r *FileUploadRepository) copyChunk(writer io.Writer, source *chunkSource) error {
+	file, err := os.Open(source.Path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(writer, h), file)
+	if err != nil {
+		return err
+	}
+
+	hash := hex.EncodeToString(h.Sum(nil))
+	if source.Pooled && hash != source.Hash {
+		return fmt.Errorf("havuzdaki chunk %d bozuk: beklenen %s, hesaplanan %s", source.Index, source.Hash, hash)
+	}
+	source.Hash = hash
+	source.Size = size
+	return nil
+}
+
+// Doğrulanmış merge'e giren part dosyaları tenant'ın havuzuna taşınır, havuzdan okunanların kullanım zamanı güncellenir
+func (r *FileUploadRepository) poolChunks(ownerID string, sources []chunkSource) {
+	now := time.Now()
+	for _, source := range sources {
+		poolPath := source.Path
+		if !source.Pooled {
+			poolPath = r.pooledChunkPath(ownerID, source.Hash)
+			if _, err := os.Stat(poolPath); os.IsNotExist(err) {
+				if err := os.MkdirAll(filepath.Dir(poolPath), os.ModePerm); err != nil {
+					log.Printf("UYARI: Chunk havuzu klasörü oluşturulamadı: %v", err)
+					continue
+				}
+				if err := os.Rename(source.Path, poolPath); err != nil {
+					if copyErr := fl.CopyFile(source.Path, poolPath); copyErr != nil {
+						log.Printf("UYARI: Chunk %d havuza eklenemedi: %v", source.Index, copyErr)
+						continue
+					}
+				}
+			}
+		}
+
+		if err := r.db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "owner_id"}, {Name: "sha256"}},
+			DoUpdates: clause.AssignmentColumns([]string{"file_path", "last_used_at"}),
+		}).Create(&entities.PooledChunk{
+			OwnerID:    ownerID,
+			SHA256:     source.Hash,
+			Size:       source.Size,
+			FilePath:   poolPath,
+			CreatedAt:  now,
+			LastUsedAt: now,
+		}).Error; err != nil {
+			log.Printf("UYARI: Chunk %d havuz kaydı yazılamadı: %v", source.Index, err)
+		}
+	}
+}
+
+// Tenant'ın havuzunda bulunan chunk'ları döner ve kullanım zamanlarını günceller (PurgeChunkPool bu sürede silmez).
+// Başka tenant'ın havuzu sorgulanmaz; aksi halde göndermediği veriyi kendi upload'ına bağlayabilirdi
+func (r *FileUploadRepository) FindPooledChunks(ownerID string, hashes []string) (map[string]*entities.PooledChunk, error) {
+	found := make(map[string]*entities.PooledChunk)
+	if len(hashes) == 0 {
+		return found, nil
+	}
+
+	if err := r.db.Model(&entities.PooledChunk{}).
+		Where("owner_id = ? AND sha256 IN ?", ownerID, hashes).
+		Update("last_used_at", time.Now()).Error; err != nil {
+		return nil, err
+	}
+
+	var chunks []*entities.PooledChunk
+	if err := r.db.Where("owner_id = ? AND sha256 IN ?", ownerID, hashes).Find(&chunks).Error; err != nil {
+		return nil, err
+	}
+	for _, chunk := range chunks {
+		if _, err := os.Stat(chunk.FilePath); err != nil {
+			log.Printf("UYARI: Havuzdaki chunk dosyası bulunamadı %s: %v", chunk.FilePath, err)
+			continue
+		}
+		found[chunk.SHA256] = chunk
+	}
+	return found, nil
+}
+
+// maxAge boyunca hiçbir upload'da kullanılmayan havuz chunk'larını siler
+func (r *FileUploadRepository) PurgeChunkPool(maxAge time.Duration) (int, error) {
+	cutoff := time.Now().Add(-maxAge)
+
+	var stale []*entities.PooledChunk
+	if err := r.db.Where("last_used_at < ?", cutoff).Find(&stale).Error; err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, chunk := range stale {
+		// Bu arada sorgulanıp tekrar kullanılmaya başlanan chunk silinmez
+		result := r.db.Where("owner_id = ? AND sha256 = ? AND last_used_at < ?", chunk.OwnerID, chunk.SHA256, cutoff).Delete(&entities.PooledChunk{})
+		if result.Error != nil {
+			log.Printf("UYARI: Havuz kaydı silinemedi %s: %v", chunk.SHA256, result.Error)
+			continue
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		if err := os.Remove(chunk.FilePath); err != nil && !os.IsNotExist(err) {
+			log.Printf("UYARI: Havuzdaki chunk dosyası silinemedi %s: %v", chunk.FilePath, err)
+		}
+		purged++
+	}
+	return purged, nil
+}
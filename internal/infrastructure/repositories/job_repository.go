@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"errors"
+	"file-uploader/internal/domain/entities"
+	"file-uploader/internal/domain/repositories"
+	"file-uploader/pkg/constants"
+	fe "file-uploader/pkg/errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type jobRepository struct {
+	db *gorm.DB
+}
+
+func NewJobRepository(db *gorm.DB) repositories.JobRepository {
+	return &jobRepository{
+		db: db,
+	}
+}
+
+func (r *jobRepository) Upsert(job *entities.Job) error {
+	now := time.Now()
+	job.CreatedAt = now
+	job.UpdatedAt = now
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"status":      job.Status,
+			"type":        job.Type,
+			"updated_at":  now,
+			"finished_at": nil,
+		}),
+	}).Create(job).Error
+}
+
+func (r *jobRepository) Transition(id, status, lastError string) error {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"status":     status,
+		"updated_at": now,
+	}
+	switch status {
+	case constants.JobStatusRunning:
+		updates["attempts"] = gorm.Expr("attempts + 1")
+		updates["started_at"] = gorm.Expr("COALESCE(started_at, ?)", now)
+	case constants.JobStatusSucceeded, constants.JobStatusFailed:
+		updates["finished_at"] = now
+	}
+	if lastError != "" {
+		updates["last_error"] = lastError
+	}
+
+	result := r.db.Model(&entities.Job{}).Where("id = ?", id).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fe.ErrNotFound(gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+// Job'un sahibi, bağlı olduğu upload oturumunun sahibidir
+func (r *jobRepository) ownedJobs(ownerID string) *gorm.DB {
+	query := r.db.Model(&entities.Job{})
+	if ownerID != "" {
+		query = query.Where("upload_id IN (?)", r.db.Model(&entities.Upload{}).Select("id").Where("owner_id = ?", ownerID))
+	}
+	return query
+}
+
+func (r *jobRepository) GetByID(ownerID, id string) (*entities.Job, error) {
+	var job entities.Job
+	if err := r.ownedJobs(ownerID).First(&job, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fe.ErrNotFound(err)
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *jobRepository) ListByUpload(ownerID, uploadID string) ([]entities.Job, error) {
+	var jobs []entities.Job
+	err := r.ownedJobs(ownerID).Where("upload_id = ?", uploadID).Order("created_at, id").Find(&jobs).Error
+	return jobs, err
+}
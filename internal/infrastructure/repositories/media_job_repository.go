@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"file-uploader/internal/domain/entities"
+	"file-uploader/internal/domain/repositories"
+	fe "file-uploader/pkg/errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type mediaJobRepository struct {
+	db *gorm.DB
+}
+
+func NewMediaJobRepository(db *gorm.DB) repositories.MediaJobRepository {
+	return &mediaJobRepository{
+		db: db,
+	}
+}
+
+func (r *mediaJobRepository) Create(job *entities.MediaJob) error {
+	if job.JobID == "" {
+		job.JobID = uuid.New().String()
+	}
+	return r.db.Create(job).Error
+}
+
+func (r *mediaJobRepository) Finish(jobID, status, outputPath, errMessage string) error {
+	now := time.Now()
+	result := r.db.Model(&entities.MediaJob{}).Where("job_id = ?", jobID).Updates(map[string]interface{}{
+		"status":      status,
+		"output_path": outputPath,
+		"error":       errMessage,
+		"updated_at":  now,
+		"finished_at": now,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fe.ErrNotFound(gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+func (r *mediaJobRepository) ListByMediaID(mediaID string) ([]*entities.MediaJob, error) {
+	var jobs []*entities.MediaJob
+	err := r.db.Where("media_id = ?", mediaID).Order("created_at, job_id").Find(&jobs).Error
+	return jobs, err
+}
+
+func (r *mediaJobRepository) DeleteByMediaID(mediaID string) error {
+	return r.db.Where("media_id = ?", mediaID).Delete(&entities.MediaJob{}).Error
+}
@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"file-uploader/internal/domain/repositories"
+
+	"gorm.io/gorm"
+)
+
+// images ve videos tablolarının ortak listeleme filtresi
+func applyMediaListFilter(query *gorm.DB, filter repositories.MediaListFilter) *gorm.DB {
+	query = query.Scopes(ownedBy(filter.OwnerID))
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if prefix, ok := strings.CutSuffix(filter.FileType, "*"); ok {
+		query = query.Where("file_type LIKE ?", escapeLike(prefix)+"%")
+	} else if filter.FileType != "" {
+		query = query.Where("file_type = ?", filter.FileType)
+	}
+	if !filter.CreatedAfter.IsZero() {
+		query = query.Where("created_at >= ?", filter.CreatedAfter)
+	}
+	if !filter.CreatedBefore.IsZero() {
+		query = query.Where("created_at < ?", filter.CreatedBefore)
+	}
+	if filter.NamePrefix != "" {
+		query = query.Where("original_name LIKE ?", escapeLike(filter.NamePrefix)+"%")
+	}
+	return query
+}
+
+// Sıralama ve cursor koşulu; idColumn tablonun birincil anahtarıdır (id, video_id).
+// id ikincil sıralama olduğu için aynı değere sahip kayıtlar sayfalar arasında kaybolmaz
+func applyMediaListPage(query *gorm.DB, filter repositories.MediaListFilter, idColumn string) (*gorm.DB, error) {
+	switch filter.Sort {
+	case "created_at", "updated_at", "original_name":
+	default:
+		return nil, fmt.Errorf("desteklenmeyen sıralama alanı: %s", filter.Sort)
+	}
+	direction, comparison := "ASC", ">"
+	if filter.Desc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if filter.After != nil {
+		var value interface{} = filter.After.Value
+		if filter.Sort != "original_name" {
+			after, err := time.Parse(time.RFC3339Nano, filter.After.Value)
+			if err != nil {
+				return nil, fmt.Errorf("cursor zamanı geçersiz: %w", err)
+			}
+			value = after
+		}
+		query = query.Where(fmt.Sprintf("(%s, %s) %s (?, ?)", filter.Sort, idColumn, comparison), value, filter.After.ID)
+	}
+	return query.
+		Order(fmt.Sprintf("%s %s, %s %s", filter.Sort, direction, idColumn, direction)).
+		Limit(filter.Limit), nil
+}
+
+// LIKE özel karakterleri (Postgres varsayılan kaçış karakteri \) kaçırılır
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
@@ -1,9 +1,12 @@
 package repositories
 
 import (
+	"errors"
 	"file-uploader/internal/domain/dto"
 	"file-uploader/internal/domain/entities"
 	"file-uploader/internal/domain/repositories"
+	fe "file-uploader/pkg/errors"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -33,15 +36,18 @@ func (r *mediaRepository) CreateMedia(mediaDTO *dto.ImageDTO) error {
 	return nil
 }
 
-func (r *mediaRepository) GetMediaByID(id string) (*dto.ImageDTO, error) {
+func (r *mediaRepository) GetMediaByID(ownerID, id string) (*dto.ImageDTO, error) {
 	// String ID'yi UUID'ye dönüştür
 	parsedID, err := uuid.Parse(id)
 	if err != nil {
-		return nil, err
+		return nil, fe.ErrNotFound(err)
 	}
 
 	var entity entities.Image
-	if err := r.db.First(&entity, "id = ?", parsedID).Error; err != nil {
+	if err := r.db.Scopes(ownedBy(ownerID)).First(&entity, "id = ?", parsedID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fe.ErrNotFound(err)
+		}
 		return nil, err
 	}
 
@@ -58,9 +64,9 @@ func (r *mediaRepository) UpdateMediaStatus(id string, status string) error {
 	return r.db.Model(&entities.Image{}).Where("id = ?", parsedID).Update("status", status).Error
 }
 
-func (r *mediaRepository) GetAllMedia() ([]*dto.ImageDTO, error) {
+func (r *mediaRepository) GetAllMedia(ownerID string) ([]*dto.ImageDTO, error) {
 	var entities []entities.Image
-	if err := r.db.Find(&entities).Error; err != nil {
+	if err := r.db.Scopes(ownedBy(ownerID)).Find(&entities).Error; err != nil {
 		return nil, err
 	}
 
@@ -73,9 +79,9 @@ func (r *mediaRepository) GetAllMedia() ([]*dto.ImageDTO, error) {
 	return dtos, nil
 }
 
-func (r *mediaRepository) GetMediaByStatus(status string) ([]*dto.ImageDTO, error) {
+func (r *mediaRepository) GetMediaByStatus(ownerID, status string) ([]*dto.ImageDTO, error) {
 	var entities []entities.Image
-	if err := r.db.Where("status = ?", status).Find(&entities).Error; err != nil {
+	if err := r.db.Scopes(ownedBy(ownerID)).Where("status = ?", status).Find(&entities).Error; err != nil {
 		return nil, err
 	}
 
@@ -83,11 +89,97 @@ func (r *mediaRepository) GetMediaByStatus(status string) ([]*dto.ImageDTO, erro
 	return r.entitiesToDTOs(entities), nil
 }
 
+func (r *mediaRepository) ListMedia(filter repositories.MediaListFilter) ([]*dto.ImageDTO, int64, error) {
+	var total int64
+	if err := applyMediaListFilter(r.db.Model(&entities.Image{}), filter).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query, err := applyMediaListPage(applyMediaListFilter(r.db.Model(&entities.Image{}), filter), filter, "id")
+	if err != nil {
+		return nil, 0, err
+	}
+	var images []entities.Image
+	if err := query.Find(&images).Error; err != nil {
+		return nil, 0, err
+	}
+	dtos := make([]*dto.ImageDTO, 0, len(images))
+	for i := range images {
+		dtos = append(dtos, r.entityToDTO(&images[i]))
+	}
+	return dtos, total, nil
+}
+
+// Aynı blob'a bağlı kayıtlar referans sayısıyla izlendiği için kayıt kalıcı olarak silinir
+func (r *mediaRepository) DeleteMedia(ownerID, id string) error {
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+	return r.db.Unscoped().Scopes(ownedBy(ownerID)).Delete(&entities.Image{}, "id = ?", parsedID).Error
+}
+
+func (r *mediaRepository) SoftDeleteMedia(ownerID, id string) error {
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+	result := r.db.Scopes(ownedBy(ownerID)).Delete(&entities.Image{}, "id = ?", parsedID)
+	if result.Error == nil && result.RowsAffected == 0 {
+		return fe.ErrNotFound(gorm.ErrRecordNotFound)
+	}
+	return result.Error
+}
+
+func (r *mediaRepository) RestoreMedia(ownerID, id string) error {
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return fe.ErrNotFound(err)
+	}
+	result := r.db.Unscoped().Model(&entities.Image{}).Scopes(ownedBy(ownerID)).
+		Where("id = ? AND deleted_at IS NOT NULL", parsedID).
+		Update("deleted_at", nil)
+	if result.Error == nil && result.RowsAffected == 0 {
+		return fe.ErrNotFound(gorm.ErrRecordNotFound)
+	}
+	return result.Error
+}
+
+func (r *mediaRepository) GetDeletedMedia(ownerID string) ([]*dto.ImageDTO, error) {
+	var entities []entities.Image
+	if err := r.db.Unscoped().Scopes(ownedBy(ownerID)).Where("deleted_at IS NOT NULL").Order("deleted_at DESC").Find(&entities).Error; err != nil {
+		return nil, err
+	}
+	return r.entitiesToDTOs(entities), nil
+}
+
+func (r *mediaRepository) GetMediaDeletedBefore(before time.Time, limit int) ([]*dto.ImageDTO, error) {
+	var entities []entities.Image
+	if err := r.db.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", before).Order("deleted_at").Limit(limit).Find(&entities).Error; err != nil {
+		return nil, err
+	}
+	return r.entitiesToDTOs(entities), nil
+}
+
+func (r *mediaRepository) GetMediaByIDWithDeleted(id string) (*dto.ImageDTO, error) {
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	var entity entities.Image
+	if err := r.db.Unscoped().First(&entity, "id = ?", parsedID).Error; err != nil {
+		return nil, err
+	}
+	return r.entityToDTO(&entity), nil
+}
+
 func (r *mediaRepository) dtoToEntity(mediaDTO *dto.ImageDTO) *entities.Image {
 	media := &entities.Image{
+		OwnerID:      mediaDTO.OwnerID,
 		OriginalName: mediaDTO.OriginalName,
 		FileType:     mediaDTO.FileType,
 		FilePath:     mediaDTO.FilePath,
+		SHA256:       mediaDTO.SHA256,
 		Status:       mediaDTO.Status,
 	}
 	if mediaDTO.ID != "" {
@@ -101,12 +193,15 @@ func (r *mediaRepository) dtoToEntity(mediaDTO *dto.ImageDTO) *entities.Image {
 func (r *mediaRepository) entityToDTO(entity *entities.Image) *dto.ImageDTO {
 	return &dto.ImageDTO{
 		ID:           entity.ID.String(),
+		OwnerID:      entity.OwnerID,
 		OriginalName: entity.OriginalName,
 		FileType:     entity.FileType,
 		FilePath:     entity.FilePath,
+		SHA256:       entity.SHA256,
 		Status:       entity.Status,
 		CreatedAt:    entity.CreatedAt,
 		UpdatedAt:    entity.UpdatedAt,
+		DeletedAt:    deletedAt(entity.DeletedAt),
 	}
 }
 
@@ -84,10 +84,56 @@ func (r *mediaVariantRepository) DeleteVariant(var_id string) error {
 	return r.db.Delete(&dto.MediaVariant{}, var_id).Error
 }
 
+func (r *mediaVariantRepository) GetVariantsByMediaID(ownerID, mediaID string) ([]*dto.MediaVariant, error) {
+	var variants []entities.MediaVariant
+	if err := r.db.Scopes(ownedBy(ownerID)).Where("media_id = ?", mediaID).Find(&variants).Error; err != nil {
+		return nil, err
+	}
+	dtos := make([]*dto.MediaVariant, 0, len(variants))
+	for i := range variants {
+		dtos = append(dtos, r.entityToDTO(&variants[i]))
+	}
+	return dtos, nil
+}
+
+func (r *mediaVariantRepository) DeleteVariantsByMediaID(ownerID, mediaID string) error {
+	return r.db.Unscoped().Scopes(ownedBy(ownerID)).Where("media_id = ?", mediaID).Delete(&entities.MediaVariant{}).Error
+}
+
+// Dedup edilen media'lar varyant dosyalarını paylaşır, dosya silinmeden önce başka referans kalıp kalmadığına bakılır
+func (r *mediaVariantRepository) CountByFilePath(filePath string) (int64, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&entities.MediaVariant{}).Where("file_path = ?", filePath).Count(&count).Error
+	return count, err
+}
+
+func (r *mediaVariantRepository) SoftDeleteVariantsByMediaID(ownerID, mediaID string) error {
+	return r.db.Scopes(ownedBy(ownerID)).Where("media_id = ?", mediaID).Delete(&entities.MediaVariant{}).Error
+}
+
+func (r *mediaVariantRepository) RestoreVariantsByMediaID(ownerID, mediaID string) error {
+	return r.db.Unscoped().Model(&entities.MediaVariant{}).Scopes(ownedBy(ownerID)).
+		Where("media_id = ? AND deleted_at IS NOT NULL", mediaID).
+		Update("deleted_at", nil).Error
+}
+
+func (r *mediaVariantRepository) GetVariantsByMediaIDWithDeleted(mediaID string) ([]*dto.MediaVariant, error) {
+	var variants []entities.MediaVariant
+	if err := r.db.Unscoped().Where("media_id = ?", mediaID).Find(&variants).Error; err != nil {
+		return nil, err
+	}
+	dtos := make([]*dto.MediaVariant, 0, len(variants))
+	for i := range variants {
+		dtos = append(dtos, r.entityToDTO(&variants[i]))
+	}
+	return dtos, nil
+}
+
 func (r *mediaVariantRepository) dtoToEntity(dtoVariant *dto.MediaVariant) *entities.MediaVariant {
 	return &entities.MediaVariant{
 		VariantID:   uuid.MustParse(dtoVariant.VariantID),
 		MediaID:     uuid.MustParse(dtoVariant.MediaID),
+		OwnerID:     dtoVariant.OwnerID,
 		VariantName: dtoVariant.VariantName,
 		Width:       dtoVariant.Width,
 		Height:      dtoVariant.Height,
@@ -100,6 +146,7 @@ func (r *mediaVariantRepository) entityToDTO(entity *entities.MediaVariant) *dto
 	return &dto.MediaVariant{
 		VariantID:   entity.VariantID.String(),
 		MediaID:     entity.MediaID.String(),
+		OwnerID:     entity.OwnerID,
 		VariantName: entity.VariantName,
 		Width:       entity.Width,
 		Height:      entity.Height,
@@ -0,0 +1,166 @@
+package repositories
+
+import (
+	"encoding/json"
+	"file-uploader/internal/domain/entities"
+	"file-uploader/internal/domain/repositories"
+	"file-uploader/pkg/constants"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) repositories.OutboxRepository {
+	return &outboxRepository{
+		db: db,
+	}
+}
+
+// Abonelere gönderilen gövde
+type webhookEnvelope struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+func (r *outboxRepository) Append(events ...*entities.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return r.db.Create(events).Error
+}
+
+func subscribed(sub entities.WebhookSubscription, eventType string) bool {
+	for _, e := range strings.Split(sub.Events, ",") {
+		e = strings.TrimSpace(e)
+		if e == "*" || e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *outboxRepository) FanOut(limit int) (int, error) {
+	dispatched := 0
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var events []entities.OutboxEvent
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("dispatched_at IS NULL").
+			Order("id").
+			Limit(limit).
+			Find(&events).Error; err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		var subs []entities.WebhookSubscription
+		if err := tx.Where("active = ?", true).Find(&subs).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		ids := make([]uint64, 0, len(events))
+		var deliveries []entities.WebhookDelivery
+		for _, event := range events {
+			ids = append(ids, event.ID)
+
+			body, err := json.Marshal(webhookEnvelope{
+				ID:        strconv.FormatUint(event.ID, 10),
+				Type:      event.EventType,
+				CreatedAt: event.CreatedAt,
+				Data:      json.RawMessage(event.Payload),
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, sub := range subs {
+				if !subscribed(sub, event.EventType) {
+					continue
+				}
+				deliveries = append(deliveries, entities.WebhookDelivery{
+					SubscriptionID: sub.ID,
+					EventID:        event.ID,
+					EventType:      event.EventType,
+					Body:           string(body),
+					Status:         constants.DeliveryStatusPending,
+					NextAttemptAt:  now,
+				})
+			}
+		}
+
+		if len(deliveries) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&entities.OutboxEvent{}).Where("id IN ?", ids).Update("dispatched_at", now).Error; err != nil {
+			return err
+		}
+		dispatched = len(events)
+		return nil
+	})
+	return dispatched, err
+}
+
+func (r *outboxRepository) ClaimDeliveries(limit int, lease time.Duration) ([]entities.WebhookDelivery, error) {
+	var deliveries []entities.WebhookDelivery
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ? AND next_attempt_at <= ?", []string{constants.DeliveryStatusPending, constants.DeliveryStatusRetrying}, now).
+			Order("next_attempt_at, id").
+			Limit(limit).
+			Find(&deliveries).Error; err != nil {
+			return err
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		ids := make([]uint64, 0, len(deliveries))
+		for _, d := range deliveries {
+			ids = append(ids, d.ID)
+		}
+		// Lease: teslimat sonuçlanmadan process ölürse süre dolunca tekrar alınır
+		return tx.Model(&entities.WebhookDelivery{}).Where("id IN ?", ids).Update("next_attempt_at", now.Add(lease)).Error
+	})
+	return deliveries, err
+}
+
+func (r *outboxRepository) MarkDelivered(id uint64, statusCode int) error {
+	now := time.Now()
+	return r.db.Model(&entities.WebhookDelivery{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":           constants.DeliveryStatusSucceeded,
+		"attempts":         gorm.Expr("attempts + 1"),
+		"last_status_code": statusCode,
+		"last_error":       "",
+		"delivered_at":     now,
+		"updated_at":       now,
+	}).Error
+}
+
+func (r *outboxRepository) MarkAttemptFailed(id uint64, statusCode int, lastError string, nextAttempt *time.Time) error {
+	updates := map[string]interface{}{
+		"status":           constants.DeliveryStatusFailed,
+		"attempts":         gorm.Expr("attempts + 1"),
+		"last_status_code": statusCode,
+		"last_error":       lastError,
+		"updated_at":       time.Now(),
+	}
+	if nextAttempt != nil {
+		updates["status"] = constants.DeliveryStatusRetrying
+		updates["next_attempt_at"] = *nextAttempt
+	}
+	return r.db.Model(&entities.WebhookDelivery{}).Where("id = ?", id).Updates(updates).Error
+}
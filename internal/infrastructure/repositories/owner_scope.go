@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Sorguyu tenant'a göre kapsamlar; ownerID boşsa (worker, sistem işlemleri, auth kapalıyken) kapsam uygulanmaz.
+// Kimliği doğrulanmış her istek boş olmayan bir ownerID taşır (bkz. auth middleware)
+func ownedBy(ownerID string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if ownerID == "" {
+			return db
+		}
+		return db.Where("owner_id = ?", ownerID)
+	}
+}
+
+// Çöp kutusundaki kayıtlar için silinme zamanı, diğerleri için nil
+func deletedAt(value gorm.DeletedAt) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Time
+}
@@ -0,0 +1,162 @@
+package repositories
+
+import (
+	"errors"
+	"file-uploader/internal/domain/entities"
+	"file-uploader/internal/domain/repositories"
+	consts "file-uploader/pkg/constants"
+	fe "file-uploader/pkg/errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Taşınan dosyanın yolu güncellenen tablo ve kolonlar. media_jobs.output_path dosya listesine eklenmez
+// (iş çıktıları image/varyant/video kayıtlarından zaten taşınır), yalnızca geçmiş yeni yolu göstersin diye güncellenir
+var storageMigrationTables = []struct{ table, column string }{
+	{"images", "file_path"},
+	{"media_variants", "file_path"},
+	{"videos", "file_path"},
+	{"blobs", "file_path"},
+	{"media_jobs", "output_path"},
+}
+
+type storageMigrationRepository struct {
+	db *gorm.DB
+}
+
+func NewStorageMigrationRepository(db *gorm.DB) repositories.StorageMigrationRepository {
+	return &storageMigrationRepository{
+		db: db,
+	}
+}
+
+func (r *storageMigrationRepository) GetMigration(id string) (*entities.StorageMigration, error) {
+	var migration entities.StorageMigration
+	if err := r.db.First(&migration, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fe.ErrNotFound(err)
+		}
+		return nil, err
+	}
+	return &migration, nil
+}
+
+func (r *storageMigrationRepository) CreateMigration(migration *entities.StorageMigration) error {
+	return r.db.Create(migration).Error
+}
+
+func (r *storageMigrationRepository) UpdateMigrationStatus(id, status string) error {
+	updates := map[string]interface{}{"status": status, "updated_at": time.Now()}
+	if status == consts.MigrationStatusCompleted {
+		updates["finished_at"] = time.Now()
+	}
+	return r.db.Model(&entities.StorageMigration{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// Soft delete edilmiş image'ların dosyaları kalıcı silinene kadar storage'da durduğu için onlar da taşınır
+func (r *storageMigrationRepository) ListReferencedPaths() ([]string, error) {
+	var paths []string
+	err := r.db.Raw(`
+		SELECT file_path FROM images WHERE file_path <> ''
+		UNION SELECT file_path FROM media_variants WHERE file_path <> ''
+		UNION SELECT file_path FROM videos WHERE file_path <> ''
+		UNION SELECT file_path FROM blobs WHERE file_path <> ''
+		ORDER BY file_path`).Scan(&paths).Error
+	return paths, err
+}
+
+func (r *storageMigrationRepository) AddItems(migrationID string, paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	now := time.Now()
+	items := make([]entities.StorageMigrationItem, 0, len(paths))
+	for _, path := range paths {
+		items = append(items, entities.StorageMigrationItem{
+			MigrationID: migrationID,
+			SourcePath:  path,
+			Status:      consts.MigrationItemPending,
+			UpdatedAt:   now,
+		})
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(items, 500).Error
+}
+
+func (r *storageMigrationRepository) ListPendingItems(migrationID string, retryFailed bool) ([]entities.StorageMigrationItem, error) {
+	statuses := []string{consts.MigrationItemPending}
+	if retryFailed {
+		statuses = append(statuses, consts.MigrationItemFailed)
+	}
+	var items []entities.StorageMigrationItem
+	err := r.db.Where("migration_id = ? AND status IN ?", migrationID, statuses).
+		Order("source_path").
+		Find(&items).Error
+	return items, err
+}
+
+func (r *storageMigrationRepository) SwitchPath(item *entities.StorageMigrationItem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if item.DestinationPath != item.SourcePath {
+			for _, target := range storageMigrationTables {
+				if err := tx.Table(target.table).
+					Where(target.column+" = ?", item.SourcePath).
+					Update(target.column, item.DestinationPath).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return tx.Model(&entities.StorageMigrationItem{}).
+			Where("migration_id = ? AND source_path = ?", item.MigrationID, item.SourcePath).
+			Updates(map[string]interface{}{
+				"destination_path": item.DestinationPath,
+				"sha256":           item.SHA256,
+				"size":             item.Size,
+				"status":           consts.MigrationItemDone,
+				"attempts":         gorm.Expr("attempts + 1"),
+				"last_error":       "",
+				"updated_at":       time.Now(),
+			}).Error
+	})
+}
+
+func (r *storageMigrationRepository) MarkItemFailed(migrationID, sourcePath, lastError string) error {
+	return r.db.Model(&entities.StorageMigrationItem{}).
+		Where("migration_id = ? AND source_path = ?", migrationID, sourcePath).
+		Updates(map[string]interface{}{
+			"status":     consts.MigrationItemFailed,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": lastError,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+func (r *storageMigrationRepository) Summary(migrationID string) (*repositories.StorageMigrationSummary, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+		Bytes  int64
+	}
+	if err := r.db.Model(&entities.StorageMigrationItem{}).
+		Select("status, COUNT(*) AS count, COALESCE(SUM(size), 0) AS bytes").
+		Where("migration_id = ?", migrationID).
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	summary := &repositories.StorageMigrationSummary{}
+	for _, row := range rows {
+		switch row.Status {
+		case consts.MigrationItemPending:
+			summary.Pending = row.Count
+		case consts.MigrationItemDone:
+			summary.Done = row.Count
+			summary.Bytes = row.Bytes
+		case consts.MigrationItemFailed:
+			summary.Failed = row.Count
+		}
+	}
+	return summary, nil
+}
@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"errors"
+	"file-uploader/internal/domain/entities"
+	"file-uploader/internal/domain/repositories"
+	fe "file-uploader/pkg/errors"
+	fl "file-uploader/pkg/file"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type uploadSessionRepository struct {
+	db *gorm.DB
+}
+
+func NewUploadSessionRepository(db *gorm.DB) repositories.UploadSessionStore {
+	return &uploadSessionRepository{
+		db: db,
+	}
+}
+
+// Aynı upload_id ile tekrar çağrılırsa mevcut oturum korunur
+func (r *uploadSessionRepository) CreateSession(session *entities.Upload) error {
+	now := time.Now()
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = now
+	}
+	session.UpdatedAt = now
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(session).Error
+}
+
+// Başka tenant'ın oturumu, varlığı sızdırılmaması için bulunamadı olarak döner
+func (r *uploadSessionRepository) GetSession(ownerID, uploadID string) (*entities.Upload, error) {
+	var session entities.Upload
+	if err := r.db.Scopes(ownedBy(ownerID)).First(&session, "id = ?", uploadID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fe.ErrNotFound(err)
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *uploadSessionRepository) UpdateStatus(uploadID, status string) error {
+	return r.db.Model(&entities.Upload{}).Where("id = ?", uploadID).Updates(map[string]interface{}{
+		"status":     status,
+		"updated_at": time.Now(),
+	}).Error
+}
+
+func (r *uploadSessionRepository) UpdateStatusWithEvents(uploadID, status string, events ...*entities.OutboxEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entities.Upload{}).Where("id = ?", uploadID).Updates(map[string]interface{}{
+			"status":     status,
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+		return tx.Create(events).Error
+	})
+}
+
+func (r *uploadSessionRepository) SetTotalChunks(uploadID string, totalChunks int) error {
+	return r.db.Model(&entities.Upload{}).Where("id = ?", uploadID).Updates(map[string]interface{}{
+		"total_chunks": totalChunks,
+		"updated_at":   time.Now(),
+	}).Error
+}
+
+func (r *uploadSessionRepository) SetTotalSize(uploadID string, totalSize int64) error {
+	return r.db.Model(&entities.Upload{}).Where("id = ?", uploadID).Updates(map[string]interface{}{
+		"total_size": totalSize,
+		"updated_at": time.Now(),
+	}).Error
+}
+
+// CompleteUpload'da gönderilen özetler merge sırasında doğrulanmak üzere oturuma yazılır
+func (r *uploadSessionRepository) SetExpectedDigest(uploadID string, digest fl.Digest) error {
+	return r.db.Model(&entities.Upload{}).Where("id = ?", uploadID).Updates(map[string]interface{}{
+		"file_hash":   digest.SHA256,
+		"file_md5":    digest.MD5,
+		"file_crc32c": digest.CRC32C,
+		"updated_at":  time.Now(),
+	}).Error
+}
+
+// Aynı chunk tekrar gelirse boyut ve hash güncellenir
+func (r *uploadSessionRepository) RecordChunk(chunk *entities.UploadChunk) error {
+	if chunk.CreatedAt.IsZero() {
+		chunk.CreatedAt = time.Now()
+	}
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "upload_id"}, {Name: "chunk_index"}},
+		DoUpdates: clause.AssignmentColumns([]string{"size", "hash", "etag", "created_at"}),
+	}).Create(chunk).Error
+	if err != nil {
+		return err
+	}
+	return r.db.Model(&entities.Upload{}).Where("id = ?", chunk.UploadID).Update("updated_at", time.Now()).Error
+}
+
+func (r *uploadSessionRepository) DeleteChunks(uploadID string, chunkIndexes []int) error {
+	if len(chunkIndexes) == 0 {
+		return nil
+	}
+	return r.db.Where("upload_id = ? AND chunk_index IN ?", uploadID, chunkIndexes).Delete(&entities.UploadChunk{}).Error
+}
+
+// Oturum satırı transaction boyunca kilitlenir; aynı upload'a gelen paralel istekler (replica'lar arasında da) sırayla işlenir.
+// fn içinde verilen store kullanılmalı, kilitli satırı ayrı bağlantıdan güncellemek deadlock'a yol açar
+func (r *uploadSessionRepository) LockSession(uploadID string, fn func(store repositories.UploadSessionStore) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var session entities.Upload
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&session, "id = ?", uploadID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fe.ErrNotFound(err)
+			}
+			return err
+		}
+		return fn(&uploadSessionRepository{db: tx})
+	})
+}
+
+func (r *uploadSessionRepository) GetChunks(uploadID string) ([]*entities.UploadChunk, error) {
+	var chunks []*entities.UploadChunk
+	if err := r.db.Where("upload_id = ?", uploadID).Order("chunk_index").Find(&chunks).Error; err != nil {
+		return nil, err
+	}
+	return chunks, nil
+}
@@ -0,0 +1,178 @@
+package repositories
+
+import (
+	"errors"
+	"file-uploader/internal/domain/entities"
+	"file-uploader/internal/domain/repositories"
+	consts "file-uploader/pkg/constants"
+	fe "file-uploader/pkg/errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// upload_sessions.mime_type'tan kullanım kırılımındaki medya tipi
+const usageMediaTypeSQL = `CASE WHEN s.mime_type LIKE 'image/%' THEN 'image' WHEN s.mime_type LIKE 'video/%' THEN 'video' ELSE 'other' END`
+
+type usageRepository struct {
+	db *gorm.DB
+}
+
+func NewUsageRepository(db *gorm.DB) repositories.UsageRepository {
+	return &usageRepository{
+		db: db,
+	}
+}
+
+func (r *usageRepository) Add(ownerID, category, mediaType string, delta int64) error {
+	if delta == 0 {
+		return nil
+	}
+	now := time.Now()
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "owner_id"}, {Name: "category"}, {Name: "media_type"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"bytes":      gorm.Expr("GREATEST(storage_usage.bytes + ?, 0)", delta),
+			"updated_at": now,
+		}),
+	}).Create(&entities.StorageUsage{
+		OwnerID:   ownerID,
+		Category:  category,
+		MediaType: mediaType,
+		Bytes:     max(delta, 0),
+		UpdatedAt: now,
+	}).Error
+}
+
+// Toplam birden fazla satıra (kategori/medya tipi) dağıldığı ve ilk satır henüz olmayabileceği için satır kilidi
+// yetmez; aynı tenant'ın rezervasyonları transaction boyunca tutulan advisory lock ile sıraya girer
+func (r *usageRepository) Reserve(ownerID, category, mediaType string, delta, limit int64) (bool, int64, error) {
+	var reserved bool
+	var used int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "storage_usage:"+ownerID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&entities.StorageUsage{}).
+			Select("COALESCE(SUM(bytes), 0)").
+			Where("owner_id = ?", ownerID).
+			Scan(&used).Error; err != nil {
+			return err
+		}
+		if used+delta > limit {
+			return nil
+		}
+		reserved = true
+		return (&usageRepository{db: tx}).Add(ownerID, category, mediaType, delta)
+	})
+	return reserved, used, err
+}
+
+// Boş ownerID de ayrı bir tenant gibi (auth kapalıyken yapılan upload'lar) listelenir
+func (r *usageRepository) ListByOwner(ownerID string) ([]entities.StorageUsage, error) {
+	var usage []entities.StorageUsage
+	err := r.db.Where("owner_id = ?", ownerID).Order("category, media_type").Find(&usage).Error
+	return usage, err
+}
+
+func (r *usageRepository) ReplaceAll(usage []entities.StorageUsage) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&entities.StorageUsage{}).Error; err != nil {
+			return err
+		}
+		if len(usage) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(usage, 500).Error
+	})
+}
+
+func (r *usageRepository) GetQuota(ownerID string) (*entities.TenantQuota, error) {
+	var quota entities.TenantQuota
+	if err := r.db.First(&quota, "owner_id = ?", ownerID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fe.ErrNotFound(err)
+		}
+		return nil, err
+	}
+	return &quota, nil
+}
+
+func (r *usageRepository) SetQuota(quota *entities.TenantQuota) error {
+	quota.UpdatedAt = time.Now()
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "owner_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"max_bytes", "updated_at"}),
+	}).Create(quota).Error
+}
+
+func (r *usageRepository) SumRecorded() ([]entities.StorageUsage, error) {
+	var usage []entities.StorageUsage
+
+	// Başarısız merge'lerin chunk'ları retry için saklandığı için retrying ve failed oturumlar da staging sayılır
+	var staging []entities.StorageUsage
+	if err := r.db.Table("upload_chunks AS c").
+		Select("s.owner_id, ? AS category, "+usageMediaTypeSQL+" AS media_type, COALESCE(SUM(c.size), 0) AS bytes", consts.UsageStaging).
+		Joins("JOIN upload_sessions s ON s.id = c.upload_id").
+		Where("s.status IN ?", []string{consts.StatusInProgress, consts.StatusMerging, consts.StatusRetrying, consts.StatusFailed}).
+		Group("s.owner_id, media_type").
+		Scan(&staging).Error; err != nil {
+		return nil, err
+	}
+	usage = append(usage, staging...)
+
+	// Image ve video orijinalleri media kayıtlarından okunur, diğer dosyalar yalnızca upload oturumunda izlenir
+	var others []entities.StorageUsage
+	if err := r.db.Table("upload_sessions AS s").
+		Select("s.owner_id, ? AS category, ? AS media_type, COALESCE(SUM(s.total_size), 0) AS bytes", consts.UsageOriginal, consts.MediaTypeOther).
+		Where("s.status = ? AND s.mime_type NOT LIKE 'image/%' AND s.mime_type NOT LIKE 'video/%'", consts.StatusCompleted).
+		Group("s.owner_id").
+		Scan(&others).Error; err != nil {
+		return nil, err
+	}
+	return append(usage, others...), nil
+}
+
+// Dedup ile paylaşılan dosyalar her kayıt için ayrı sayılır; kullanım mantıksal boyutu gösterir.
+// Çöp kutusundaki kayıtlar purge'e kadar kotadan düşülmediği için deleted_at'e bakılmaz
+func (r *usageRepository) ListStoredFiles() ([]repositories.StoredFile, error) {
+	var files []repositories.StoredFile
+
+	queries := []struct {
+		sql  string
+		args []interface{}
+	}{
+		{
+			// Orijinal dosya blob'tadır; dedup'tan önce oluşturulan kayıtlarda file_path kullanılır
+			sql: `SELECT i.owner_id, ? AS category, ? AS media_type, COALESCE(b.file_path, i.file_path) AS file_path
+				FROM images i LEFT JOIN blobs b ON b.sha256 = i.sha256`,
+			args: []interface{}{consts.UsageOriginal, consts.MediaTypeImage},
+		},
+		{
+			sql: `SELECT mv.owner_id, ? AS category, ? AS media_type, mv.file_path
+				FROM media_variants mv JOIN images i ON i.id = mv.media_id`,
+			args: []interface{}{consts.UsageVariant, consts.MediaTypeImage},
+		},
+		{
+			sql: `SELECT v.owner_id, ? AS category, ? AS media_type, COALESCE(b.file_path, v.file_path) AS file_path
+				FROM videos v LEFT JOIN blobs b ON b.sha256 = v.sha256`,
+			args: []interface{}{consts.UsageOriginal, consts.MediaTypeVideo},
+		},
+		{
+			// Resize edilen videoda file_path çıktı dosyasını gösterir, orijinal blob'ta kalır
+			sql: `SELECT v.owner_id, ? AS category, ? AS media_type, v.file_path
+				FROM videos v JOIN blobs b ON b.sha256 = v.sha256
+				WHERE v.file_path <> b.file_path`,
+			args: []interface{}{consts.UsageVariant, consts.MediaTypeVideo},
+		},
+	}
+	for _, q := range queries {
+		var batch []repositories.StoredFile
+		if err := r.db.Raw(q.sql, q.args...).Scan(&batch).Error; err != nil {
+			return nil, err
+		}
+		files = append(files, batch...)
+	}
+	return files, nil
+}
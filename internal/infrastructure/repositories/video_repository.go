@@ -1,9 +1,12 @@
 package repositories
 
 import (
+	"errors"
 	"file-uploader/internal/domain/dto"
 	"file-uploader/internal/domain/entities"
+	"file-uploader/internal/domain/repositories"
 	"file-uploader/pkg/constants"
+	fe "file-uploader/pkg/errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -24,37 +27,29 @@ func (r *VideoRepository) CreateVideo(video *dto.VideoDTO) error { //buna db iş
 	}
 	entity := entities.Video{
 		VideoID:      uuid.MustParse(video.VideoID),
+		OwnerID:      video.OwnerID,
 		Width:        video.Width,
 		Height:       video.Height,
 		Status:       video.Status,
 		OriginalName: video.OriginalName,
 		FileType:     video.FileType,
 		FilePath:     video.FilePath,
+		SHA256:       video.SHA256,
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
 	}
 	return r.db.Create(&entity).Error
 }
 
-func (r *VideoRepository) GetVideoByID(id string) (*dto.VideoDTO, error) {
+func (r *VideoRepository) GetVideoByID(ownerID, id string) (*dto.VideoDTO, error) {
 	var entity entities.Video
-	if err := r.db.First(&entity, "video_id = ?", id).Error; err != nil {
+	if err := r.db.Scopes(ownedBy(ownerID)).First(&entity, "video_id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fe.ErrNotFound(err)
+		}
 		return nil, err
 	}
-
-	video := dto.VideoDTO{
-		VideoID:      entity.VideoID.String(),
-		OriginalName: entity.OriginalName,
-		FileType:     entity.FileType,
-		FilePath:     entity.FilePath,
-		Status:       entity.Status,
-		Width:        entity.Width,
-		Height:       entity.Height,
-		CreatedAt:    entity.CreatedAt,
-		UpdatedAt:    entity.UpdatedAt,
-	}
-	return &video, nil
-
+	return videoToDTO(&entity), nil
 }
 
 func (r *VideoRepository) ResizeWidth(video *entities.Video) error { //video boyutlarını güncellemek için
@@ -92,3 +87,99 @@ func (r *VideoRepository) ResizeVideo(video *entities.Video) error {
 	existingVideo.FilePath = video.FilePath
 	return r.db.Save(&existingVideo).Error
 }
+
+func (r *VideoRepository) ListVideos(filter repositories.MediaListFilter) ([]*dto.VideoDTO, int64, error) {
+	var total int64
+	if err := applyMediaListFilter(r.db.Model(&entities.Video{}), filter).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query, err := applyMediaListPage(applyMediaListFilter(r.db.Model(&entities.Video{}), filter), filter, "video_id")
+	if err != nil {
+		return nil, 0, err
+	}
+	var videos []entities.Video
+	if err := query.Find(&videos).Error; err != nil {
+		return nil, 0, err
+	}
+	return videosToDTOs(videos), total, nil
+}
+
+// Aynı blob'a bağlı kayıtlar referans sayısıyla izlendiği için kayıt kalıcı olarak silinir
+func (r *VideoRepository) DeleteVideo(ownerID, id string) error {
+	return r.db.Unscoped().Scopes(ownedBy(ownerID)).Delete(&entities.Video{}, "video_id = ?", id).Error
+}
+
+// Boyutlandırılmış dosya çöp kutusundaki videolarla da paylaşılıyor olabilir
+func (r *VideoRepository) CountByFilePath(filePath string) (int64, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&entities.Video{}).Where("file_path = ?", filePath).Count(&count).Error
+	return count, err
+}
+
+func (r *VideoRepository) SoftDeleteVideo(ownerID, id string) error {
+	result := r.db.Scopes(ownedBy(ownerID)).Delete(&entities.Video{}, "video_id = ?", id)
+	if result.Error == nil && result.RowsAffected == 0 {
+		return fe.ErrNotFound(gorm.ErrRecordNotFound)
+	}
+	return result.Error
+}
+
+func (r *VideoRepository) RestoreVideo(ownerID, id string) error {
+	result := r.db.Unscoped().Model(&entities.Video{}).Scopes(ownedBy(ownerID)).
+		Where("video_id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error == nil && result.RowsAffected == 0 {
+		return fe.ErrNotFound(gorm.ErrRecordNotFound)
+	}
+	return result.Error
+}
+
+func (r *VideoRepository) GetDeletedVideos(ownerID string) ([]*dto.VideoDTO, error) {
+	var videos []entities.Video
+	if err := r.db.Unscoped().Scopes(ownedBy(ownerID)).Where("deleted_at IS NOT NULL").Order("deleted_at DESC").Find(&videos).Error; err != nil {
+		return nil, err
+	}
+	return videosToDTOs(videos), nil
+}
+
+func (r *VideoRepository) GetVideosDeletedBefore(before time.Time, limit int) ([]*dto.VideoDTO, error) {
+	var videos []entities.Video
+	if err := r.db.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", before).Order("deleted_at").Limit(limit).Find(&videos).Error; err != nil {
+		return nil, err
+	}
+	return videosToDTOs(videos), nil
+}
+
+func (r *VideoRepository) GetVideoByIDWithDeleted(id string) (*dto.VideoDTO, error) {
+	var entity entities.Video
+	if err := r.db.Unscoped().First(&entity, "video_id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return videoToDTO(&entity), nil
+}
+
+func videoToDTO(entity *entities.Video) *dto.VideoDTO {
+	return &dto.VideoDTO{
+		VideoID:      entity.VideoID.String(),
+		OwnerID:      entity.OwnerID,
+		OriginalName: entity.OriginalName,
+		FileType:     entity.FileType,
+		FilePath:     entity.FilePath,
+		SHA256:       entity.SHA256,
+		Status:       entity.Status,
+		Width:        entity.Width,
+		Height:       entity.Height,
+		CreatedAt:    entity.CreatedAt,
+		UpdatedAt:    entity.UpdatedAt,
+		DeletedAt:    deletedAt(entity.DeletedAt),
+	}
+}
+
+func videosToDTOs(videos []entities.Video) []*dto.VideoDTO {
+	dtos := make([]*dto.VideoDTO, 0, len(videos))
+	for i := range videos {
+		dtos = append(dtos, videoToDTO(&videos[i]))
+	}
+	return dtos
+}
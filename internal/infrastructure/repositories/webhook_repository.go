@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"errors"
+	"file-uploader/internal/domain/entities"
+	"file-uploader/internal/domain/repositories"
+	fe "file-uploader/pkg/errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) repositories.WebhookRepository {
+	return &webhookRepository{
+		db: db,
+	}
+}
+
+func (r *webhookRepository) CreateSubscription(sub *entities.WebhookSubscription) error {
+	return r.db.Create(sub).Error
+}
+
+func (r *webhookRepository) GetSubscription(id string) (*entities.WebhookSubscription, error) {
+	var sub entities.WebhookSubscription
+	if err := r.db.Where("id = ?", id).First(&sub).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fe.ErrNotFound(err)
+		}
+		return nil, err
+	}
+	return &sub, nil
+}
+
+func (r *webhookRepository) ListSubscriptions() ([]entities.WebhookSubscription, error) {
+	var subs []entities.WebhookSubscription
+	err := r.db.Order("created_at").Find(&subs).Error
+	return subs, err
+}
+
+func (r *webhookRepository) UpdateSubscription(sub *entities.WebhookSubscription) error {
+	sub.UpdatedAt = time.Now()
+	return r.db.Model(&entities.WebhookSubscription{}).Where("id = ?", sub.ID).Updates(map[string]interface{}{
+		"url":         sub.URL,
+		"events":      sub.Events,
+		"description": sub.Description,
+		"active":      sub.Active,
+		"updated_at":  sub.UpdatedAt,
+	}).Error
+}
+
+func (r *webhookRepository) DeleteSubscription(id string) error {
+	result := r.db.Where("id = ?", id).Delete(&entities.WebhookSubscription{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fe.ErrNotFound(gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+func (r *webhookRepository) ListDeliveries(subscriptionID, status string, offset, limit int) ([]entities.WebhookDelivery, int64, error) {
+	scoped := func() *gorm.DB {
+		query := r.db.Model(&entities.WebhookDelivery{}).Where("subscription_id = ?", subscriptionID)
+		if status != "" {
+			query = query.Where("status = ?", status)
+		}
+		return query
+	}
+
+	var total int64
+	if err := scoped().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var deliveries []entities.WebhookDelivery
+	err := scoped().
+		Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, total, err
+}
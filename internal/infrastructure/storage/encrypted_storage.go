@@ -0,0 +1,308 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"os"
+	"path"
+	"path/filepath"
+
+	"file-uploader/internal/domain/entities"
+	"file-uploader/internal/domain/repositories"
+	fe "file-uploader/pkg/errors"
+)
+
+// Başka bir StorageStrategy'yi saran şifreleme katmanı. Yazılan her dosya kendi veri anahtarıyla şifrelenir,
+// sarılmış anahtar ve key ID encrypted_objects'e yazılır. Download yalnızca okunan segmentleri çözer, LocalPath
+// (ffmpeg/imaging) dosyayı geçici bir dosyaya çözer; kaydı olmayan dosyalar (şifreleme açılmadan önce yazılanlar) olduğu gibi okunur
+type EncryptedStorage struct {
+	inner   repositories.StorageStrategy
+	keyring *Keyring
+	objects repositories.EncryptedObjectRepository
+	backend string
+	encrypt bool
+}
+
+// encrypt false ise yeni dosyalar şifresiz yazılır, mevcut şifreli dosyalar okunmaya devam eder
+func NewEncryptedStorage(inner repositories.StorageStrategy, keyring *Keyring, objects repositories.EncryptedObjectRepository, backend string, encrypt bool) *EncryptedStorage {
+	return &EncryptedStorage{
+		inner:   inner,
+		keyring: keyring,
+		objects: objects,
+		backend: backend,
+		encrypt: encrypt,
+	}
+}
+
+// LocalStorage.UploadImage ile aynı klasör düzeni kullanılır
+func (e *EncryptedStorage) UploadImage(file multipart.File, metadata map[string]string) (string, error) {
+	key := e.inner.GetOriginalPath(metadata["name"])
+	if metadata["folder"] == "variants" {
+		key = path.Join("media/variants", metadata["name"])
+	}
+	return e.Save(file, key)
+}
+
+// Dosya çözülüp hedefe yeni veri anahtarıyla yazılır. Kaynak diskteki bir dosya da olabilir (S3Storage.CopyFile gibi)
+func (e *EncryptedStorage) CopyFile(sourcePath, destinationPath string) error {
+	if !e.encrypt {
+		if err := e.inner.CopyFile(sourcePath, destinationPath); err != nil {
+			return err
+		}
+		return e.forget(destinationPath)
+	}
+
+	var source io.ReadCloser
+	if info, err := os.Stat(sourcePath); err == nil && !info.IsDir() {
+		source, err = os.Open(sourcePath)
+		if err != nil {
+			return fmt.Errorf("kaynak dosya açılamadı: %w", err)
+		}
+	} else {
+		source, err = e.Download(sourcePath)
+		if err != nil {
+			return fmt.Errorf("kaynak dosya açılamadı: %w", err)
+		}
+	}
+	defer source.Close()
+
+	_, err := e.write(source, destinationPath, "")
+	return err
+}
+
+func (e *EncryptedStorage) GetVariantPath(originalFilename, variantType string) string {
+	return e.inner.GetVariantPath(originalFilename, variantType)
+}
+
+func (e *EncryptedStorage) GetOriginalPath(filename string) string {
+	return e.inner.GetOriginalPath(filename)
+}
+
+// Dosya zaten yoksa kayıt yine silinir
+func (e *EncryptedStorage) DeleteFile(filePath string) error {
+	err := e.inner.DeleteFile(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if forgetErr := e.forget(filePath); forgetErr != nil {
+		return forgetErr
+	}
+	return err
+}
+
+func (e *EncryptedStorage) Delete(fileID string) error {
+	return e.DeleteFile(fileID)
+}
+
+// Şifreli dosya geçici dosyaya çözülmez; okumalar (Range istekleri dahil) yalnızca dokundukları segmentleri çözer
+func (e *EncryptedStorage) Download(fileID string) (multipart.File, error) {
+	object, err := e.object(fileID)
+	if err != nil {
+		return nil, err
+	}
+	if object == nil {
+		return e.inner.Download(fileID)
+	}
+
+	dataKey, err := e.keyring.Unwrap(object.KeyID, object.WrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := e.inner.Download(fileID)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := newSegmentReader(encrypted, dataKey, object.Size)
+	if err != nil {
+		encrypted.Close()
+		return nil, fmt.Errorf("%s çözülemedi: %w", fileID, err)
+	}
+	return reader, nil
+}
+
+func (e *EncryptedStorage) FileExists(filePath string) bool {
+	return e.inner.FileExists(filePath)
+}
+
+func (e *EncryptedStorage) Save(file multipart.File, key string) (string, error) {
+	if !e.encrypt {
+		location, err := e.inner.Save(file, key)
+		if err != nil {
+			return "", err
+		}
+		return location, e.forget(location)
+	}
+	return e.write(file, key, "")
+}
+
+// Şifreli kopya yerel dosyanın yanında üretilir, böylece local backend'de taşıma aynı dosya sisteminde kalır
+func (e *EncryptedStorage) Store(localPath, key string) (string, error) {
+	if !e.encrypt {
+		location, err := e.inner.Store(localPath, key)
+		if err != nil {
+			return "", err
+		}
+		return location, e.forget(location)
+	}
+
+	file, err := os.Open(localPath)
+	if err != nil {
+		return "", fmt.Errorf("dosya açılamadı: %w", err)
+	}
+	location, err := e.write(file, key, filepath.Dir(localPath))
+	file.Close()
+	if err != nil {
+		return "", err
+	}
+	if err := os.Remove(localPath); err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("yerel dosya silinemedi: %w", err)
+	}
+	return location, nil
+}
+
+// Şifreli dosyalar her çağrıda geçici dosyaya çözülür, iş bitince cleanup ile silinir
+func (e *EncryptedStorage) LocalPath(location string) (string, func(), error) {
+	object, err := e.object(location)
+	if err != nil {
+		return "", nil, err
+	}
+	if object == nil {
+		return e.inner.LocalPath(location)
+	}
+
+	plainPath, err := e.decrypt(location, object)
+	if err != nil {
+		return "", nil, err
+	}
+	return plainPath, func() { os.Remove(plainPath) }, nil
+}
+
+// Şifreli dosyalar için şifresiz boyut döner (kota hesapları ve Content-Length)
+func (e *EncryptedStorage) Size(location string) (int64, error) {
+	object, err := e.object(location)
+	if err != nil {
+		return 0, err
+	}
+	if object == nil {
+		return e.inner.Size(location)
+	}
+	if !e.inner.FileExists(location) {
+		return 0, &os.PathError{Op: "stat", Path: location, Err: os.ErrNotExist}
+	}
+	return object.Size, nil
+}
+
+// Değişiklik zamanı şifreli dosyanınkidir, boyut şifresiz boyuttur
+func (e *EncryptedStorage) Stat(location string) (os.FileInfo, error) {
+	object, err := e.object(location)
+	if err != nil {
+		return nil, err
+	}
+	info, err := e.inner.Stat(location)
+	if err != nil || object == nil {
+		return info, err
+	}
+	return plainFileInfo{FileInfo: info, size: object.Size}, nil
+}
+
+// Dosya tmpDir'de şifrelenip iç storage'a taşınır, ardından sarılmış veri anahtarı kaydedilir.
+// Kayıt yazılamazsa okunamayacak dosya bırakılmaz
+func (e *EncryptedStorage) write(src io.Reader, key, tmpDir string) (string, error) {
+	dataKey, err := newDataKey()
+	if err != nil {
+		return "", fmt.Errorf("veri anahtarı üretilemedi: %w", err)
+	}
+	keyID, wrappedKey, err := e.keyring.Wrap(dataKey)
+	if err != nil {
+		return "", fmt.Errorf("veri anahtarı sarılamadı: %w", err)
+	}
+
+	tmpFile, err := os.CreateTemp(tmpDir, ".encrypt-*")
+	if err != nil {
+		return "", fmt.Errorf("geçici dosya oluşturulamadı: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	size, err := encryptStream(tmpFile, src, dataKey)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("dosya şifrelenemedi: %w", err)
+	}
+
+	location, err := e.inner.Store(tmpFile.Name(), key)
+	if err != nil {
+		return "", err
+	}
+	if err := e.objects.Save(&entities.EncryptedObject{
+		Backend:    e.backend,
+		Location:   location,
+		KeyID:      keyID,
+		WrappedKey: wrappedKey,
+		Size:       size,
+	}); err != nil {
+		if deleteErr := e.inner.DeleteFile(location); deleteErr != nil {
+			log.Printf("UYARI: anahtarı kaydedilemeyen şifreli dosya silinemedi %s: %v", location, deleteErr)
+		}
+		return "", fmt.Errorf("şifreleme anahtarı kaydedilemedi: %w", err)
+	}
+	return location, nil
+}
+
+// Çözülen dosyanın uzantısı ve değişiklik zamanı korunur (ffmpeg/imaging ve content endpoint'lerinin Last-Modified'ı için)
+func (e *EncryptedStorage) decrypt(location string, object *entities.EncryptedObject) (string, error) {
+	dataKey, err := e.keyring.Unwrap(object.KeyID, object.WrappedKey)
+	if err != nil {
+		return "", err
+	}
+
+	encryptedPath, cleanup, err := e.inner.LocalPath(location)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+	encrypted, err := os.Open(encryptedPath)
+	if err != nil {
+		return "", err
+	}
+	defer encrypted.Close()
+
+	plainFile, err := os.CreateTemp("", "decrypt-*"+path.Ext(location))
+	if err != nil {
+		return "", fmt.Errorf("geçici dosya oluşturulamadı: %w", err)
+	}
+	err = decryptStream(plainFile, encrypted, dataKey)
+	if closeErr := plainFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(plainFile.Name())
+		return "", fmt.Errorf("%s çözülemedi: %w", location, err)
+	}
+	if info, err := encrypted.Stat(); err == nil {
+		os.Chtimes(plainFile.Name(), info.ModTime(), info.ModTime())
+	}
+	return plainFile.Name(), nil
+}
+
+// Kaydı olmayan dosya şifresizdir
+func (e *EncryptedStorage) object(location string) (*entities.EncryptedObject, error) {
+	object, err := e.objects.Get(e.backend, location)
+	if err != nil {
+		if uploadErr, ok := err.(*fe.UploadError); ok && uploadErr.Code == "not_found" {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("şifreleme anahtarı okunamadı: %w", err)
+	}
+	return object, nil
+}
+
+// Şifresiz yazılan ya da silinen dosyanın eski anahtar kaydı kaldırılır
+func (e *EncryptedStorage) forget(location string) error {
+	if err := e.objects.Delete(e.backend, location); err != nil {
+		return fmt.Errorf("şifreleme anahtarı silinemedi: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,396 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"mime/multipart"
+	"os"
+	"strings"
+	"sync"
+
+	"file-uploader/pkg/config"
+)
+
+// Şifreli dosya biçimi: magic + nonce öneki, ardından 64 KiB'lik AES-256-GCM segmentleri.
+// Segment nonce'u önek + sayaç + son segment bayrağıdır; segmentlerin sırası değiştirilemez,
+// dosya kesilirse son segment doğrulanamaz. Dosyalar bellekte tutulmadan segment segment işlenir;
+// segmentler sabit boyutlu olduğundan herhangi bir konumun segmenti baştan çözmeden bulunur
+const (
+	segmentSize     = 64 * 1024
+	noncePrefixSize = 7
+	dataKeySize     = 32
+)
+
+var encryptedMagic = []byte("FUENC\x01")
+
+// Master key'ler; veri anahtarları aktif anahtarla sarılır, eski anahtarlar rotasyon bitene kadar açmak için tutulur
+type Keyring struct {
+	keys   map[string][]byte
+	active string
+}
+
+// STORAGE_ENCRYPTION_KEYS ve anahtar dosyası birleştirilir; aktif anahtar belirtilmezse tek anahtar aktif sayılır
+func NewKeyring(cfg config.EncryptionConfig) (*Keyring, error) {
+	k := &Keyring{
+		keys:   make(map[string][]byte),
+		active: cfg.ActiveKey,
+	}
+	for id, encoded := range cfg.Keys {
+		if err := k.add(id, encoded); err != nil {
+			return nil, fmt.Errorf("STORAGE_ENCRYPTION_KEYS: %w", err)
+		}
+	}
+	if cfg.KeyFile != "" {
+		content, err := os.ReadFile(cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("anahtar dosyası okunamadı: %w", err)
+		}
+		for i, line := range strings.Split(string(content), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			id, encoded, _ := strings.Cut(line, ":")
+			if err := k.add(strings.TrimSpace(id), strings.TrimSpace(encoded)); err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", cfg.KeyFile, i+1, err)
+			}
+		}
+	}
+
+	if len(k.keys) == 0 {
+		return nil, fmt.Errorf("şifreleme için STORAGE_ENCRYPTION_KEYS ya da STORAGE_ENCRYPTION_KEYFILE gerekli")
+	}
+	if k.active == "" {
+		if len(k.keys) > 1 {
+			return nil, fmt.Errorf("birden fazla şifreleme anahtarı var, STORAGE_ENCRYPTION_ACTIVE_KEY belirtilmeli")
+		}
+		for id := range k.keys {
+			k.active = id
+		}
+	}
+	if _, ok := k.keys[k.active]; !ok {
+		return nil, fmt.Errorf("STORAGE_ENCRYPTION_ACTIVE_KEY %q tanımlı anahtarlar içinde yok", k.active)
+	}
+	return k, nil
+}
+
+func (k *Keyring) add(id, encoded string) error {
+	if id == "" {
+		return fmt.Errorf("key ID boş")
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("%s anahtarı base64 değil: %w", id, err)
+	}
+	if len(key) != dataKeySize {
+		return fmt.Errorf("%s anahtarı %d byte olmalı, %d byte", id, dataKeySize, len(key))
+	}
+	k.keys[id] = key
+	return nil
+}
+
+func (k *Keyring) ActiveKeyID() string {
+	return k.active
+}
+
+// Veri anahtarı aktif master key ile sarılır: nonce + GCM çıktısı. Key ID ek veri olarak doğrulanır
+func (k *Keyring) Wrap(dataKey []byte) (string, []byte, error) {
+	aead, err := newGCM(k.keys[k.active])
+	if err != nil {
+		return "", nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", nil, err
+	}
+	return k.active, aead.Seal(nonce, nonce, dataKey, []byte(k.active)), nil
+}
+
+func (k *Keyring) Unwrap(keyID string, wrapped []byte) ([]byte, error) {
+	key, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%s şifreleme anahtarı tanımlı değil", keyID)
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, fmt.Errorf("sarılmış anahtar geçersiz")
+	}
+	dataKey, err := aead.Open(nil, wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():], []byte(keyID))
+	if err != nil {
+		return nil, fmt.Errorf("veri anahtarı %s ile açılamadı: %w", keyID, err)
+	}
+	return dataKey, nil
+}
+
+func newDataKey() ([]byte, error) {
+	key := make([]byte, dataKeySize)
+	_, err := rand.Read(key)
+	return key, err
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// src'yi şifreleyerek dst'ye yazar, şifresiz boyutu döner
+func encryptStream(dst io.Writer, src io.Reader, dataKey []byte) (int64, error) {
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return 0, err
+	}
+	prefix := make([]byte, noncePrefixSize)
+	if _, err := rand.Read(prefix); err != nil {
+		return 0, err
+	}
+	if _, err := dst.Write(append(append([]byte{}, encryptedMagic...), prefix...)); err != nil {
+		return 0, err
+	}
+
+	reader := bufio.NewReaderSize(src, segmentSize)
+	plain := make([]byte, segmentSize)
+	sealed := make([]byte, 0, segmentSize+aead.Overhead())
+	var size int64
+	for counter := uint32(0); ; counter++ {
+		n, final, err := readSegment(reader, plain)
+		if err != nil {
+			return size, err
+		}
+		sealed = aead.Seal(sealed[:0], segmentNonce(prefix, counter, final), plain[:n], nil)
+		if _, err := dst.Write(sealed); err != nil {
+			return size, err
+		}
+		size += int64(n)
+		if final {
+			return size, nil
+		}
+		if counter == math.MaxUint32 {
+			return size, fmt.Errorf("dosya şifrelenemeyecek kadar büyük")
+		}
+	}
+}
+
+// Segmentler doğrulanarak çözülür; bozuk ya da kesilmiş dosyada hata döner
+func decryptStream(dst io.Writer, src io.Reader, dataKey []byte) error {
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return err
+	}
+	header := make([]byte, len(encryptedMagic)+noncePrefixSize)
+	if _, err := io.ReadFull(src, header); err != nil || !bytes.Equal(header[:len(encryptedMagic)], encryptedMagic) {
+		return fmt.Errorf("dosya şifreli biçimde değil")
+	}
+	prefix := header[len(encryptedMagic):]
+
+	reader := bufio.NewReaderSize(src, segmentSize+aead.Overhead())
+	sealed := make([]byte, segmentSize+aead.Overhead())
+	plain := make([]byte, 0, segmentSize)
+	for counter := uint32(0); ; counter++ {
+		n, final, err := readSegment(reader, sealed)
+		if err != nil {
+			return err
+		}
+		plain, err = aead.Open(plain[:0], segmentNonce(prefix, counter, final), sealed[:n], nil)
+		if err != nil {
+			return fmt.Errorf("şifreli dosyanın %d. segmenti doğrulanamadı", counter)
+		}
+		if _, err := dst.Write(plain); err != nil {
+			return err
+		}
+		if final {
+			return nil
+		}
+	}
+}
+
+// buf dolana kadar okur; ardından veri kalmadıysa segment sonuncudur
+func readSegment(reader *bufio.Reader, buf []byte) (int, bool, error) {
+	n, err := io.ReadFull(reader, buf)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return n, true, nil
+	}
+	if err != nil {
+		return n, false, err
+	}
+	if _, err := reader.Peek(1); err == io.EOF {
+		return n, true, nil
+	} else if err != nil {
+		return n, false, err
+	}
+	return n, false, nil
+}
+
+func segmentNonce(prefix []byte, counter uint32, final bool) []byte {
+	nonce := make([]byte, noncePrefixSize+5)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[noncePrefixSize:], counter)
+	if final {
+		nonce[len(nonce)-1] = 1
+	}
+	return nonce
+}
+
+// Şifreli nesne üzerinde rastgele erişim (multipart.File): yalnızca okunan byte'ların düştüğü segmentler
+// çözülür, son çözülen segment bir sonraki okuma için tutulur. size şifresiz boyuttur (encrypted_objects.size)
+type segmentReader struct {
+	src    multipart.File
+	aead   cipher.AEAD
+	prefix []byte
+	size   int64
+	last   int64 // son segmentin indeksi, nonce'unda son segment bayrağı vardır
+
+	mu      sync.Mutex
+	offset  int64 // Read/Seek konumu
+	current int64 // plain'deki segmentin indeksi, -1 ise yok
+	plain   []byte
+	sealed  []byte
+}
+
+func newSegmentReader(src multipart.File, dataKey []byte, size int64) (*segmentReader, error) {
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, len(encryptedMagic)+noncePrefixSize)
+	if _, err := src.ReadAt(header, 0); err != nil || !bytes.Equal(header[:len(encryptedMagic)], encryptedMagic) {
+		return nil, fmt.Errorf("dosya şifreli biçimde değil")
+	}
+	var last int64
+	if size > 0 {
+		last = (size - 1) / segmentSize
+	}
+	return &segmentReader{
+		src:     src,
+		aead:    aead,
+		prefix:  header[len(encryptedMagic):],
+		size:    size,
+		last:    last,
+		current: -1,
+		plain:   make([]byte, 0, segmentSize),
+		sealed:  make([]byte, segmentSize+aead.Overhead()),
+	}, nil
+}
+
+func (s *segmentReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("geçersiz offset: %d", off)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for n < len(p) && off < s.size {
+		index := off / segmentSize
+		if err := s.load(index); err != nil {
+			return n, err
+		}
+		copied := copy(p[n:], s.plain[off-index*segmentSize:])
+		n += copied
+		off += int64(copied)
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// index. segment şifreli dosyadaki konumundan okunup doğrulanarak çözülür
+func (s *segmentReader) load(index int64) error {
+	if s.current == index {
+		return nil
+	}
+	sealedSize := segmentSize + s.aead.Overhead()
+	if index == s.last {
+		sealedSize = int(s.size-index*segmentSize) + s.aead.Overhead()
+	}
+	offset := int64(len(encryptedMagic)+noncePrefixSize) + index*int64(segmentSize+s.aead.Overhead())
+	n, err := s.src.ReadAt(s.sealed[:sealedSize], offset)
+	if n < sealedSize {
+		if err == nil || err == io.EOF {
+			err = fmt.Errorf("şifreli dosya kesilmiş")
+		}
+		return err
+	}
+
+	s.current = -1
+	plain, err := s.aead.Open(s.plain[:0], segmentNonce(s.prefix, uint32(index), index == s.last), s.sealed[:sealedSize], nil)
+	if err != nil {
+		return fmt.Errorf("şifreli dosyanın %d. segmenti doğrulanamadı", index)
+	}
+	s.plain = plain
+	s.current = index
+	return nil
+}
+
+func (s *segmentReader) Read(p []byte) (int, error) {
+	s.mu.Lock()
+	offset := s.offset
+	s.mu.Unlock()
+	n, err := s.ReadAt(p, offset)
+	s.mu.Lock()
+	s.offset = offset + int64(n)
+	s.mu.Unlock()
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+func (s *segmentReader) Seek(offset int64, whence int) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += s.offset
+	case io.SeekEnd:
+		offset += s.size
+	default:
+		return 0, fmt.Errorf("geçersiz whence: %d", whence)
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("negatif konum: %d", offset)
+	}
+	s.offset = offset
+	return offset, nil
+}
+
+// Şifreli dosyanın değişiklik zamanı, şifresiz boyutla döner (content endpoint'lerinin Last-Modified/ETag'i için)
+func (s *segmentReader) Stat() (os.FileInfo, error) {
+	stat, ok := s.src.(interface{ Stat() (os.FileInfo, error) })
+	if !ok {
+		return nil, fmt.Errorf("kaynak dosya stat desteklemiyor")
+	}
+	info, err := stat.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return plainFileInfo{FileInfo: info, size: s.size}, nil
+}
+
+func (s *segmentReader) Close() error {
+	return s.src.Close()
+}
+
+type plainFileInfo struct {
+	os.FileInfo
+	size int64
+}
+
+func (i plainFileInfo) Size() int64 {
+	return i.size
+}
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"fmt"
+
+	"file-uploader/internal/domain/repositories"
+	"file-uploader/pkg/config"
+)
+
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+// STORAGE_BACKEND'e göre storage oluşturur; local backend dosyaları basePath (UPLOAD_DIR) altına yazar
+func New(cfg config.StorageConfig, basePath string) (repositories.StorageStrategy, error) {
+	switch cfg.Backend {
+	case BackendLocal, "":
+		return NewLocalStorage(basePath), nil
+	case BackendS3:
+		if cfg.S3Bucket == "" {
+			return nil, fmt.Errorf("s3 storage için STORAGE_S3_BUCKET gerekli")
+		}
+		return NewS3Storage(cfg)
+	default:
+		return nil, fmt.Errorf("bilinmeyen storage backend'i: %s", cfg.Backend)
+	}
+}
+
+// Şifreleme anahtarı tanımlıysa storage EncryptedStorage ile sarılır; şifreleme kapalı olsa da
+// daha önce şifrelenmiş dosyalar okunabilsin diye anahtarlar verildiği sürece sarmalama yapılır
+func WithEncryption(inner repositories.StorageStrategy, storageCfg config.StorageConfig, cfg config.EncryptionConfig, objects repositories.EncryptedObjectRepository) (repositories.StorageStrategy, error) {
+	if !cfg.Enabled && len(cfg.Keys) == 0 && cfg.KeyFile == "" {
+		return inner, nil
+	}
+	keyring, err := NewKeyring(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewEncryptedStorage(inner, keyring, objects, Namespace(storageCfg), cfg.Enabled), nil
+}
+
+// encrypted_objects kayıtlarının ait olduğu backend. Local'de klasör yolu kullanılmaz, UPLOAD_DIR taşınabilir
+func Namespace(cfg config.StorageConfig) string {
+	if cfg.Backend == BackendS3 {
+		return BackendS3 + ":" + cfg.S3Bucket
+	}
+	return BackendLocal
+}
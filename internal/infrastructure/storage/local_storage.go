@@ -5,10 +5,15 @@ import (
 	"io"
 	"mime/multipart"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
+
+	fl "file-uploader/pkg/file"
 )
 
+// Dosyalar BasePath (UPLOAD_DIR) altında mantıksal key'leriyle tutulur (ör. media/original/<ad>).
+// Key'lere geçmeden önce yazılmış kayıtlardaki yollar da okunabilir, bkz. resolve
 type LocalStorage struct {
 	BasePath string
 }
@@ -20,45 +25,25 @@ func NewLocalStorage(basePath string) *LocalStorage {
 }
 
 func (l *LocalStorage) Upload(file multipart.File, metadata map[string]string) (string, error) {
-	filename := metadata["filename"]
-	folder := metadata["folder"]
-	fullPath := filepath.Join(l.BasePath, folder, filename)
-
-	if err := os.MkdirAll(filepath.Dir(fullPath), os.ModePerm); err != nil {
-		return "", fmt.Errorf("klasör oluşturulamadı: %w", err)
-	}
-
-	outFile, err := os.Create(fullPath)
-	if err != nil {
-		return "", fmt.Errorf("dosya oluşturulamadı: %w", err)
-	}
-	defer outFile.Close()
-
-	if _, err := io.Copy(outFile, file); err != nil {
-		return "", fmt.Errorf("dosya yazılamadı: %w", err)
-	}
-
-	return fullPath, nil
+	return l.Save(file, path.Join(metadata["folder"], metadata["filename"]))
 }
 
 func (l *LocalStorage) Download(fileID string) (multipart.File, error) {
-	return os.Open(filepath.Join(l.BasePath, fileID))
+	return os.Open(l.resolve(fileID))
 }
 
 func (l *LocalStorage) Delete(fileID string) error {
-	return os.Remove(filepath.Join(l.BasePath, fileID))
+	return l.DeleteFile(fileID)
 }
 
-// UploadOriginal - Original image'i uploads/original klasörüne kaydeder
+// UploadOriginal - Original image'i media/original altına kaydeder
 func (m *LocalStorage) UploadOriginal(file multipart.File, filename string) (string, error) {
-	originalPath := filepath.Join(m.BasePath, "media/original")
-	return m.uploadFile(file, originalPath, filename)
+	return m.Save(file, m.GetOriginalPath(filename))
 }
 
-// UploadVariant - Variant image'i uploads/media/variants klasörüne kaydeder
+// UploadVariant - Variant image'i media/variants altına kaydeder
 func (m *LocalStorage) UploadVariant(file multipart.File, filename string) (string, error) {
-	variantsPath := filepath.Join(m.BasePath, "media/variants")
-	return m.uploadFile(file, variantsPath, filename)
+	return m.Save(file, path.Join("media/variants", filename))
 }
 
 // Upload - StorageStrategy interface'i için genel upload metodu
@@ -76,62 +61,48 @@ func (m *LocalStorage) UploadImage(file multipart.File, metadata map[string]stri
 	return m.UploadOriginal(file, filename)
 }
 
-// uploadFile - Genel dosya upload metodu
-func (m *LocalStorage) uploadFile(file multipart.File, folderPath, filename string) (string, error) {
-	// Klasörü oluştur
-	if err := os.MkdirAll(folderPath, os.ModePerm); err != nil {
+// Dosya önce geçici isimle yazılır, tamamlanınca key'e taşınır; yarım dosya okunamaz
+func (l *LocalStorage) Save(file multipart.File, key string) (string, error) {
+	fullPath := l.resolve(key)
+	if err := os.MkdirAll(filepath.Dir(fullPath), os.ModePerm); err != nil {
 		return "", fmt.Errorf("klasör oluşturulamadı: %w", err)
 	}
 
-	// Dosya yolunu oluştur
-	fullPath := filepath.Join(folderPath, filename)
-
-	// Dosyayı oluştur
-	outFile, err := os.Create(fullPath)
+	outFile, err := os.CreateTemp(filepath.Dir(fullPath), ".upload-*")
 	if err != nil {
 		return "", fmt.Errorf("dosya oluşturulamadı: %w", err)
 	}
-	defer outFile.Close()
+	defer os.Remove(outFile.Name())
 
-	// Dosyayı kopyala
 	if _, err := io.Copy(outFile, file); err != nil {
+		outFile.Close()
 		return "", fmt.Errorf("dosya yazılamadı: %w", err)
 	}
-
-	return fullPath, nil
+	if err := outFile.Close(); err != nil {
+		return "", fmt.Errorf("dosya yazılamadı: %w", err)
+	}
+	if err := os.Rename(outFile.Name(), fullPath); err != nil {
+		return "", fmt.Errorf("dosya yazılamadı: %w", err)
+	}
+	return l.key(key), nil
 }
 
 // CopyFile - Bir dosyayı başka bir yere kopyalar (variant oluşturmak için)
 func (m *LocalStorage) CopyFile(sourcePath, destinationPath string) error {
+	destinationPath = m.resolve(destinationPath)
+
 	// Hedef klasörü oluştur
-	destDir := filepath.Dir(destinationPath)
-	if err := os.MkdirAll(destDir, os.ModePerm); err != nil {
+	if err := os.MkdirAll(filepath.Dir(destinationPath), os.ModePerm); err != nil {
 		return fmt.Errorf("hedef klasör oluşturulamadı: %w", err)
 	}
 
-	// Kaynak dosyayı aç
-	sourceFile, err := os.Open(sourcePath)
-	if err != nil {
-		return fmt.Errorf("kaynak dosya açılamadı: %w", err)
-	}
-	defer sourceFile.Close()
-
-	// Hedef dosyayı oluştur
-	destFile, err := os.Create(destinationPath)
-	if err != nil {
-		return fmt.Errorf("hedef dosya oluşturulamadı: %w", err)
-	}
-	defer destFile.Close()
-
-	// Dosyayı kopyala
-	if _, err := io.Copy(destFile, sourceFile); err != nil {
+	if err := fl.CopyFile(m.resolve(sourcePath), destinationPath); err != nil {
 		return fmt.Errorf("dosya kopyalanamadı: %w", err)
 	}
-
 	return nil
 }
 
-// GetVariantPath - Variant dosyası için path oluşturur
+// GetVariantPath - Variant dosyası için key oluşturur
 func (m *LocalStorage) GetVariantPath(originalFilename, variantType string) string {
 	// Dosya uzantısını al
 	ext := filepath.Ext(originalFilename)
@@ -140,21 +111,83 @@ func (m *LocalStorage) GetVariantPath(originalFilename, variantType string) stri
 	// Variant dosya adını oluştur
 	variantFilename := fmt.Sprintf("%s_%s%s", nameWithoutExt, variantType, ext)
 
-	return filepath.Join(m.BasePath, "media/variants", variantFilename)
+	return path.Join("media/variants", variantFilename)
 }
 
-// GetOriginalPath - Original dosya için path oluşturur
+// GetOriginalPath - Original dosya için key oluşturur
 func (m *LocalStorage) GetOriginalPath(filename string) string {
-	return filepath.Join(m.BasePath, "media/original", filename)
+	return path.Join("media/original", filename)
 }
 
-// DeleteFile - Dosyayı siler
+// DeleteFile - Dosyayı siler, boşalan klasörleri BasePath'e kadar kaldırır (ör. media/variants/<media_id>)
 func (m *LocalStorage) DeleteFile(filePath string) error {
-	return os.Remove(filePath)
+	fullPath := m.resolve(filePath)
+	if err := os.Remove(fullPath); err != nil {
+		return err
+	}
+
+	base := filepath.Clean(m.BasePath)
+	for dir := filepath.Dir(fullPath); strings.HasPrefix(dir, base+string(filepath.Separator)); dir = filepath.Dir(dir) {
+		// Klasör boş değilse os.Remove başarısız olur
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+	return nil
 }
 
 // FileExists - Dosyanın var olup olmadığını kontrol eder
 func (m *LocalStorage) FileExists(filePath string) bool {
-	_, err := os.Stat(filePath)
+	_, err := os.Stat(m.resolve(filePath))
 	return err == nil
 }
+
+// Dosya BasePath/key konumuna taşınır (farklı dosya sistemindeyse kopyalanır), key'i döner
+func (l *LocalStorage) Store(localPath, key string) (string, error) {
+	fullPath := l.resolve(key)
+	if err := os.MkdirAll(filepath.Dir(fullPath), os.ModePerm); err != nil {
+		return "", fmt.Errorf("klasör oluşturulamadı: %w", err)
+	}
+	if err := os.Rename(localPath, fullPath); err != nil {
+		if copyErr := fl.CopyFile(localPath, fullPath); copyErr != nil {
+			return "", fmt.Errorf("dosya taşınamadı: %w", copyErr)
+		}
+		os.Remove(localPath)
+	}
+	return l.key(key), nil
+}
+
+// Dosyalar zaten diskte olduğu için cleanup bir şey yapmaz
+func (l *LocalStorage) LocalPath(location string) (string, func(), error) {
+	fullPath := l.resolve(location)
+	if _, err := os.Stat(fullPath); err != nil {
+		return "", nil, err
+	}
+	return fullPath, func() {}, nil
+}
+
+func (l *LocalStorage) Size(location string) (int64, error) {
+	info, err := os.Stat(l.resolve(location))
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+func (l *LocalStorage) Stat(location string) (os.FileInfo, error) {
+	return os.Stat(l.resolve(location))
+}
+
+// Key'ler BasePath'e göre çözülür. Key'lere geçmeden önce yazılmış kayıtlar olduğu gibi kullanılır:
+// mutlak yollar (merge, video resize), ./uploads/... (media/video handler'ları) ve uploads/... (varyantlar)
+func (l *LocalStorage) resolve(location string) string {
+	if filepath.IsAbs(location) || strings.HasPrefix(location, "./") || strings.HasPrefix(location, "uploads/") {
+		return location
+	}
+	return filepath.Join(l.BasePath, filepath.FromSlash(l.key(location)))
+}
+
+// Key'ler her zaman "/" ile ayrılır; ".." ile BasePath dışına çıkılamaz
+func (l *LocalStorage) key(location string) string {
+	return strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(location)), "/")
+}
@@ -2,31 +2,57 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
+	"net/url"
 	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	appconfig "file-uploader/pkg/config"
+	"file-uploader/pkg/helper"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
+// Dosya konumları bucket içindeki object key'lerdir (ör. media/original/<upload>_<ad>)
 type S3Storage struct {
 	client     *s3.Client
 	bucketName string
 	region     string
 }
 
-func NewS3Storage(bucketName, region string) (*S3Storage, error) {
-	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(region))
+// Endpoint verilirse (MinIO vb.) istekler oraya gider; anahtar verilmezse AWS varsayılan kimlik zinciri kullanılır
+func NewS3Storage(cfg appconfig.StorageConfig) (*S3Storage, error) {
+	options := []func(*config.LoadOptions) error{config.WithRegion(cfg.S3Region)}
+	if cfg.S3AccessKey != "" {
+		options = append(options, config.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(cfg.S3AccessKey, cfg.S3SecretKey, "")))
+	}
+	awsCfg, err := config.LoadDefaultConfig(context.TODO(), options...)
 	if err != nil {
 		return nil, fmt.Errorf("AWS config yüklenemedi: %w", err)
 	}
+
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if cfg.S3Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.S3Endpoint)
+		}
+		o.UsePathStyle = cfg.S3PathStyle
+	})
 	return &S3Storage{
-		client:     s3.NewFromConfig(cfg),
-		bucketName: bucketName,
-		region:     region,
+		client:     client,
+		bucketName: cfg.S3Bucket,
+		region:     cfg.S3Region,
 	}, nil
 }
 
@@ -35,57 +61,334 @@ func (s *S3Storage) Upload(file multipart.File, metadata map[string]string) (str
 	if folder, ok := metadata["folder"]; ok {
 		key = folder + "/" + key
 	}
+	if err := s.put(key, file, metadata); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// LocalStorage.UploadImage ile aynı klasör düzeni kullanılır
+func (s *S3Storage) UploadImage(file multipart.File, metadata map[string]string) (string, error) {
+	key := s.GetOriginalPath(metadata["name"])
+	if metadata["folder"] == "variants" {
+		key = path.Join("media/variants", metadata["name"])
+	}
+	if err := s.put(key, file, nil); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// Object indirilmez: boyut ve değişiklik zamanı HeadObject'ten alınır (content endpoint'lerindeki Last-Modified/ETag için),
+// okumalar yalnızca istenen aralığı Range'li GetObject ile çeker
+func (s *S3Storage) Download(fileID string) (multipart.File, error) {
+	head, err := s.head(fileID)
+	if err != nil {
+		return nil, err
+	}
+	return &s3Object{
+		storage: s,
+		key:     s.key(fileID),
+		etag:    head.ETag,
+		size:    aws.ToInt64(head.ContentLength),
+		modTime: aws.ToTime(head.LastModified),
+	}, nil
+}
+
+func (s *S3Storage) Delete(fileID string) error {
+	_, err := s.client.DeleteObject(context.TODO(), &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(s.key(fileID)),
+	})
+	return err
+}
+
+// Kaynak diskte bir dosyaysa yüklenir, değilse bucket içinde sunucu tarafında kopyalanır
+func (s *S3Storage) CopyFile(sourcePath, destinationPath string) error {
+	if info, err := os.Stat(sourcePath); err == nil && !info.IsDir() {
+		file, err := os.Open(sourcePath)
+		if err != nil {
+			return fmt.Errorf("kaynak dosya açılamadı: %w", err)
+		}
+		defer file.Close()
+		return s.put(destinationPath, file, nil)
+	}
+
+	_, err := s.client.CopyObject(context.TODO(), &s3.CopyObjectInput{
+		Bucket:     aws.String(s.bucketName),
+		Key:        aws.String(s.key(destinationPath)),
+		CopySource: aws.String(s.bucketName + "/" + (&url.URL{Path: s.key(sourcePath)}).EscapedPath()),
+	})
+	if err != nil {
+		return fmt.Errorf("S3 kopyalama hatası: %w", err)
+	}
+	return nil
+}
+
+func (s *S3Storage) GetVariantPath(originalFilename, variantType string) string {
+	ext := filepath.Ext(originalFilename)
+	nameWithoutExt := strings.TrimSuffix(originalFilename, ext)
+	return path.Join("media/variants", fmt.Sprintf("%s_%s%s", nameWithoutExt, variantType, ext))
+}
+
+func (s *S3Storage) GetOriginalPath(filename string) string {
+	return path.Join("media/original", filename)
+}
+
+func (s *S3Storage) DeleteFile(filePath string) error {
+	return s.Delete(filePath)
+}
 
+func (s *S3Storage) FileExists(filePath string) bool {
+	_, err := s.head(filePath)
+	return err == nil
+}
+
+func (s *S3Storage) Save(file multipart.File, key string) (string, error) {
+	if err := s.put(key, file, nil); err != nil {
+		return "", err
+	}
+	return s.key(key), nil
+}
+
+// Dosya yüklendikten sonra yerel kopya silinir
+func (s *S3Storage) Store(localPath, key string) (string, error) {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return "", fmt.Errorf("dosya açılamadı: %w", err)
+	}
+	err = s.put(key, file, nil)
+	file.Close()
+	if err != nil {
+		return "", err
+	}
+	if err := os.Remove(localPath); err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("yerel dosya silinemedi: %w", err)
+	}
+	return s.key(key), nil
+}
+
+// Geçici dosyanın uzantısı key ile aynıdır; ffmpeg/imaging formatı uzantıdan da çıkarabilir
+func (s *S3Storage) LocalPath(location string) (string, func(), error) {
+	tmpFile, err := s.download(location)
+	if err != nil {
+		return "", nil, err
+	}
+	tmpFile.Close()
+	return tmpFile.Name(), func() { os.Remove(tmpFile.Name()) }, nil
+}
+
+func (s *S3Storage) Size(location string) (int64, error) {
+	head, err := s.head(location)
+	if err != nil {
+		return 0, err
+	}
+	return aws.ToInt64(head.ContentLength), nil
+}
+
+func (s *S3Storage) Stat(location string) (os.FileInfo, error) {
+	head, err := s.head(location)
+	if err != nil {
+		return nil, err
+	}
+	return s3ObjectInfo{key: s.key(location), size: aws.ToInt64(head.ContentLength), modTime: aws.ToTime(head.LastModified)}, nil
+}
+
+func (s *S3Storage) put(key string, body io.ReadSeeker, metadata map[string]string) error {
 	_, err := s.client.PutObject(context.TODO(), &s3.PutObjectInput{
-		Bucket:   aws.String(s.bucketName),
-		Key:      aws.String(key),
-		Body:     file,
-		Metadata: metadata,
+		Bucket:      aws.String(s.bucketName),
+		Key:         aws.String(s.key(key)),
+		Body:        body,
+		ContentType: aws.String(helper.GetMimeTypeFromExtension(key)),
+		Metadata:    metadata,
 	})
 	if err != nil {
-		return "", fmt.Errorf("S3 upload hatası: %w", err)
+		return fmt.Errorf("S3 upload hatası: %w", err)
 	}
+	return nil
+}
 
-	// URL artık region alanından okunuyor
-	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.bucketName, s.region, key), nil
+func (s *S3Storage) head(location string) (*s3.HeadObjectOutput, error) {
+	head, err := s.client.HeadObject(context.TODO(), &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(s.key(location)),
+	})
+	return head, notExist(location, err)
 }
 
-func (s *S3Storage) Download(fileID string) (multipart.File, error) {
+func (s *S3Storage) download(location string) (*os.File, error) {
 	resp, err := s.client.GetObject(context.TODO(), &s3.GetObjectInput{
 		Bucket: aws.String(s.bucketName),
-		Key:    aws.String(fileID),
+		Key:    aws.String(s.key(location)),
 	})
 	if err != nil {
-		return nil, err
+		return nil, notExist(location, err)
 	}
 	defer resp.Body.Close()
 
-	// Read the object into a buffer
-	tmpFile, err := os.CreateTemp("", "s3download-*")
+	tmpFile, err := os.CreateTemp("", "s3download-*"+path.Ext(location))
 	if err != nil {
 		return nil, fmt.Errorf("geçici dosya oluşturulamadı: %w", err)
 	}
-
 	if _, err := io.Copy(tmpFile, resp.Body); err != nil {
 		tmpFile.Close()
 		os.Remove(tmpFile.Name())
 		return nil, fmt.Errorf("S3 dosyası kopyalanamadı: %w", err)
 	}
-
-	// Seek to beginning for reading
+	if resp.LastModified != nil {
+		os.Chtimes(tmpFile.Name(), *resp.LastModified, *resp.LastModified)
+	}
 	if _, err := tmpFile.Seek(0, io.SeekStart); err != nil {
 		tmpFile.Close()
 		os.Remove(tmpFile.Name())
 		return nil, fmt.Errorf("dosya başına alınamadı: %w", err)
 	}
-
 	return tmpFile, nil
 }
 
-func (s *S3Storage) Delete(fileID string) error {
-	_, err := s.client.DeleteObject(context.TODO(), &s3.DeleteObjectInput{
-		Bucket: aws.String(s.bucketName),
-		Key:    aws.String(fileID),
+// Key'ler her zaman "/" ile ayrılır, baştaki "/" ve "./" atılır
+func (s *S3Storage) key(location string) string {
+	return strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(location)), "/")
+}
+
+// Bulunamayan object'ler için *os.PathError döner; çağıranlar yerel dosyadaki gibi os.IsNotExist ile kontrol edebilir
+func notExist(location string, err error) error {
+	var noSuchKey *types.NoSuchKey
+	var notFound *types.NotFound
+	if errors.As(err, &noSuchKey) || errors.As(err, &notFound) {
+		return &os.PathError{Op: "s3", Path: location, Err: os.ErrNotExist}
+	}
+	return err
+}
+
+// S3 object üzerinde rastgele erişim (multipart.File). Sıralı Read'ler tek bir GetObject gövdesinden akar,
+// ReadAt ve Seek sonrası okumalar yalnızca gereken aralığı ister. Okuma sırasında object değişirse (IfMatch) hata döner
+type s3Object struct {
+	storage *S3Storage
+	key     string
+	etag    *string
+	size    int64
+	modTime time.Time
+
+	mu     sync.Mutex
+	offset int64
+	body   io.ReadCloser // offset'ten başlayan açık gövde, yoksa nil
+}
+
+// start ve end dahil
+func (o *s3Object) get(start, end int64) (io.ReadCloser, error) {
+	resp, err := o.storage.client.GetObject(context.TODO(), &s3.GetObjectInput{
+		Bucket:  aws.String(o.storage.bucketName),
+		Key:     aws.String(o.key),
+		Range:   aws.String(fmt.Sprintf("bytes=%d-%d", start, end)),
+		IfMatch: o.etag,
 	})
+	if err != nil {
+		return nil, notExist(o.key, err)
+	}
+	return resp.Body, nil
+}
+
+func (o *s3Object) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("geçersiz offset: %d", off)
+	}
+	if off >= o.size {
+		return 0, io.EOF
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	end := min(off+int64(len(p)), o.size)
+	body, err := o.get(off, end-1)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+	n, err := io.ReadFull(body, p[:end-off])
+	if err != nil {
+		return n, err
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (o *s3Object) Read(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.offset >= o.size {
+		return 0, io.EOF
+	}
+	if o.body == nil {
+		body, err := o.get(o.offset, o.size-1)
+		if err != nil {
+			return 0, err
+		}
+		o.body = body
+	}
+	n, err := o.body.Read(p)
+	o.offset += int64(n)
+	if err == io.EOF && o.offset < o.size {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// Konum değişirse açık gövde kapatılır, sonraki Read yeni konumdan ister
+func (o *s3Object) Seek(offset int64, whence int) (int64, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += o.offset
+	case io.SeekEnd:
+		offset += o.size
+	default:
+		return 0, fmt.Errorf("geçersiz whence: %d", whence)
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("negatif konum: %d", offset)
+	}
+	if offset != o.offset {
+		o.closeBody()
+		o.offset = offset
+	}
+	return offset, nil
+}
+
+func (o *s3Object) Stat() (os.FileInfo, error) {
+	return s3ObjectInfo{key: o.key, size: o.size, modTime: o.modTime}, nil
+}
+
+func (o *s3Object) Close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.closeBody()
+}
+
+// o.mu tutulurken çağrılır
+func (o *s3Object) closeBody() error {
+	if o.body == nil {
+		return nil
+	}
+	err := o.body.Close()
+	o.body = nil
 	return err
 }
+
+// HeadObject'ten alınan bilgiler
+type s3ObjectInfo struct {
+	key     string
+	size    int64
+	modTime time.Time
+}
+
+func (i s3ObjectInfo) Name() string       { return path.Base(i.key) }
+func (i s3ObjectInfo) Size() int64        { return i.size }
+func (i s3ObjectInfo) Mode() os.FileMode  { return 0o444 }
+func (i s3ObjectInfo) ModTime() time.Time { return i.modTime }
+func (i s3ObjectInfo) IsDir() bool        { return false }
+func (i s3ObjectInfo) Sys() any           { return nil }
@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	appconfig "file-uploader/pkg/config"
+
+	"github.com/google/uuid"
+)
+
+// MinIO ile çalışır: docker compose --profile s3 up -d minio minio-init
+// STORAGE_S3_TEST_ENDPOINT=http://localhost:9000 go test ./internal/infrastructure/storage/
+func newTestS3Storage(t *testing.T) *S3Storage {
+	t.Helper()
+	endpoint := os.Getenv("STORAGE_S3_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("STORAGE_S3_TEST_ENDPOINT tanımlı değil")
+	}
+	storage, err := NewS3Storage(appconfig.StorageConfig{
+		S3Bucket:    envOr("STORAGE_S3_TEST_BUCKET", "file-uploader"),
+		S3Region:    "us-east-1",
+		S3Endpoint:  endpoint,
+		S3AccessKey: envOr("STORAGE_S3_TEST_ACCESS_KEY", "minioadmin"),
+		S3SecretKey: envOr("STORAGE_S3_TEST_SECRET_KEY", "minioadmin"),
+		S3PathStyle: true,
+	})
+	if err != nil {
+		t.Fatalf("NewS3Storage: %v", err)
+	}
+	return storage
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// Birden fazla 64 KiB'lik okuma tamponuna yayılan rastgele içerikli object yükler
+func putTestObject(t *testing.T, storage *S3Storage, size int) (string, []byte) {
+	t.Helper()
+	data := make([]byte, size)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+	localPath := filepath.Join(t.TempDir(), "object.bin")
+	if err := os.WriteFile(localPath, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	location, err := storage.Store(localPath, "tests/"+uuid.New().String()+".bin")
+	if err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+	t.Cleanup(func() { storage.Delete(location) })
+	return location, data
+}
+
+func TestS3StorageDownloadStat(t *testing.T) {
+	storage := newTestS3Storage(t)
+	location, data := putTestObject(t, storage, 300*1024+7)
+
+	file, err := storage.Download(location)
+	if err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	defer file.Close()
+
+	info, err := file.(interface{ Stat() (os.FileInfo, error) }).Stat()
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if info.Size() != int64(len(data)) {
+		t.Errorf("Size = %d, beklenen %d", info.Size(), len(data))
+	}
+	if time.Since(info.ModTime()) > time.Hour {
+		t.Errorf("ModTime = %v, object'in LastModified'ı bekleniyordu", info.ModTime())
+	}
+}
+
+func TestS3StorageDownloadRanges(t *testing.T) {
+	storage := newTestS3Storage(t)
+	location, data := putTestObject(t, storage, 300*1024+7)
+
+	file, err := storage.Download(location)
+	if err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	defer file.Close()
+
+	// Ortadan bir aralık
+	buf := make([]byte, 1000)
+	n, err := file.ReadAt(buf, 100*1024)
+	if err != nil || n != len(buf) {
+		t.Fatalf("ReadAt = %d, %v", n, err)
+	}
+	if !bytes.Equal(buf, data[100*1024:100*1024+1000]) {
+		t.Error("ReadAt içeriği uyuşmuyor")
+	}
+
+	// Sona taşan aralık kısa okunur ve io.EOF döner
+	n, err = file.ReadAt(buf, int64(len(data)-10))
+	if err != io.EOF || n != 10 || !bytes.Equal(buf[:n], data[len(data)-10:]) {
+		t.Errorf("sona taşan ReadAt = %d, %v", n, err)
+	}
+	if n, err := file.ReadAt(buf, int64(len(data))); err != io.EOF || n != 0 {
+		t.Errorf("sondan ReadAt = %d, %v", n, err)
+	}
+
+	// Seek sonrası sıralı okuma
+	if _, err := file.Seek(-500, io.SeekEnd); err != nil {
+		t.Fatalf("Seek: %v", err)
+	}
+	tail, err := io.ReadAll(file)
+	if err != nil || !bytes.Equal(tail, data[len(data)-500:]) {
+		t.Errorf("Seek sonrası okuma uyuşmuyor (%d byte, %v)", len(tail), err)
+	}
+
+	// Baştan tamamı
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		t.Fatalf("Seek: %v", err)
+	}
+	all, err := io.ReadAll(file)
+	if err != nil || !bytes.Equal(all, data) {
+		t.Errorf("tam okuma uyuşmuyor (%d byte, %v)", len(all), err)
+	}
+}
+
+func TestS3StorageDownloadNotExist(t *testing.T) {
+	storage := newTestS3Storage(t)
+	if _, err := storage.Download("tests/" + uuid.New().String()); !os.IsNotExist(err) {
+		t.Errorf("Download hatası = %v, os.IsNotExist bekleniyordu", err)
+	}
+}
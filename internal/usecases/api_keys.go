@@ -0,0 +1,85 @@
+package usecases
+
+import (
+	"fmt"
+
+	"file-uploader/internal/domain/dto"
+	"file-uploader/internal/domain/entities"
+	"file-uploader/internal/domain/repositories"
+	"file-uploader/internal/infrastructure/auth"
+	"file-uploader/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+type APIKeyService interface {
+	CreateKey(req *dto.APIKeyCreateRequestDTO) (*dto.APIKeyResponse, error)
+	ListKeys(ownerID string) (*dto.APIKeyListResponse, error)
+	RevokeKey(id string) error
+}
+
+type apiKeyService struct {
+	repo repositories.APIKeyRepository
+}
+
+func NewAPIKeyService(repo repositories.APIKeyRepository) APIKeyService {
+	return &apiKeyService{
+		repo: repo,
+	}
+}
+
+func (s *apiKeyService) CreateKey(req *dto.APIKeyCreateRequestDTO) (*dto.APIKeyResponse, error) {
+	// Boş owner tüm tenant'ları görebileceği için anahtar mutlaka bir tenant'a bağlanır
+	if req.OwnerID == "" {
+		return nil, errors.ErrInvalidRequest(fmt.Errorf("owner_id zorunlu"))
+	}
+
+	key, prefix, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+	entity := &entities.APIKey{
+		ID:      uuid.New().String(),
+		OwnerID: req.OwnerID,
+		Name:    req.Name,
+		Prefix:  prefix,
+		KeyHash: hash,
+		Admin:   req.Admin,
+	}
+	if err := s.repo.Create(entity); err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+
+	resp := toAPIKeyResponse(entity)
+	resp.Key = key
+	return &resp, nil
+}
+
+func (s *apiKeyService) ListKeys(ownerID string) (*dto.APIKeyListResponse, error) {
+	keys, err := s.repo.List(ownerID)
+	if err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+	items := make([]dto.APIKeyResponse, 0, len(keys))
+	for i := range keys {
+		items = append(items, toAPIKeyResponse(&keys[i]))
+	}
+	return &dto.APIKeyListResponse{Items: items}, nil
+}
+
+func (s *apiKeyService) RevokeKey(id string) error {
+	return s.repo.Revoke(id)
+}
+
+func toAPIKeyResponse(key *entities.APIKey) dto.APIKeyResponse {
+	return dto.APIKeyResponse{
+		ID:         key.ID,
+		OwnerID:    key.OwnerID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Admin:      key.Admin,
+		CreatedAt:  key.CreatedAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+	}
+}
@@ -2,16 +2,20 @@ package usecases
 
 import (
 	"file-uploader/internal/domain/repositories"
+	"file-uploader/pkg/config"
 	"file-uploader/pkg/errors"
 	"log"
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/robfig/cron/v3"
 )
 
 type CleanupService interface {
 	CleanupTempFiles(uploadID string) error
 	CleanupOldTempFiles(maxAge time.Duration) error
+	CleanupChunkPool(maxAge time.Duration) error
 }
 
 type cleanupService struct {
@@ -56,3 +60,34 @@ func (s *cleanupService) CleanupOldTempFiles(maxAge time.Duration) error {
 	}
 	return nil
 }
+
+func (s *cleanupService) CleanupChunkPool(maxAge time.Duration) error {
+	purged, err := s.repo.PurgeChunkPool(maxAge)
+	if err != nil {
+		return err
+	}
+	if purged > 0 {
+		log.Printf("Chunk havuzundan %d kullanılmayan chunk silindi", purged)
+	}
+	return nil
+}
+
+// Periyodik temizlik job'larını başlatır; worker'ın ayrı çalışmadığı tek binary kurulumda server da kullanır
+func ScheduleCleanup(cleanup CleanupService, cfg config.UploadConfig) *cron.Cron {
+	c := cron.New(cron.WithSeconds())
+
+	c.AddFunc("0 0 * * * *", func() { // her saat başı çalışır
+		log.Println("Running scheduled cleanup of old temp files...")
+		if err := cleanup.CleanupOldTempFiles(2 * time.Hour); err != nil { // 2 saatten eski temp dosyaları siler
+			log.Printf("Error cleaning up old temp files: %v", err)
+		}
+	})
+	c.AddFunc("0 30 3 * * *", func() { // her gün 03:30'da çalışır
+		log.Println("Running scheduled chunk pool cleanup...")
+		if err := cleanup.CleanupChunkPool(cfg.ChunkPoolTTL); err != nil {
+			log.Printf("Error cleaning up chunk pool: %v", err)
+		}
+	})
+	c.Start() // cron job'u başlatmak için
+	return c
+}
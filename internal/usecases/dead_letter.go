@@ -0,0 +1,278 @@
+package usecases
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"file-uploader/internal/domain/dto"
+	"file-uploader/internal/domain/entities"
+	"file-uploader/internal/domain/repositories"
+	"file-uploader/internal/infrastructure/queue"
+	consts "file-uploader/pkg/constants"
+	"file-uploader/pkg/errors"
+)
+
+const (
+	AuditActionRequeue = "failed_jobs.requeue"
+	AuditActionPurge   = "failed_jobs.purge"
+
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// failed_jobs tablosu üzerinden dead-letter yönetimi: listeleme, inceleme, toplu requeue ve silme
+type DeadLetterService interface {
+	ListFailedJobs(req *dto.FailedJobListRequestDTO) (*dto.FailedJobListResponse, error)
+	GetFailedJob(id uint) (*dto.FailedJobDetailResponse, error)
+	RequeueFailedJobs(req *dto.FailedJobBulkRequestDTO) (*dto.FailedJobBulkResponse, error)
+	PurgeFailedJobs(req *dto.FailedJobBulkRequestDTO) (*dto.FailedJobBulkResponse, error)
+	ListAuditLogs(action string, page, limit int) (*dto.AuditLogListResponse, error)
+}
+
+type deadLetterService struct {
+	failedJobs repositories.FailedJobRepository
+	audit      repositories.AuditLogRepository
+	sessions   repositories.UploadSessionStore
+	jobs       queue.JobQueue
+	tracker    repositories.JobRepository
+}
+
+func NewDeadLetterService(failedJobs repositories.FailedJobRepository, audit repositories.AuditLogRepository, sessions repositories.UploadSessionStore, jobs queue.JobQueue, tracker repositories.JobRepository) DeadLetterService {
+	return &deadLetterService{
+		failedJobs: failedJobs,
+		audit:      audit,
+		sessions:   sessions,
+		jobs:       jobs,
+		tracker:    tracker,
+	}
+}
+
+func (s *deadLetterService) ListFailedJobs(req *dto.FailedJobListRequestDTO) (*dto.FailedJobListResponse, error) {
+	filter, err := buildFailedJobFilter(nil, req.JobType, req.Status, req.OlderThan, req.NewerThan)
+	if err != nil {
+		return nil, err
+	}
+	page, limit := normalizePage(req.Page, req.Limit)
+
+	jobs, total, err := s.failedJobs.List(filter, (page-1)*limit, limit)
+	if err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+
+	items := make([]dto.FailedJobItem, 0, len(jobs))
+	for i := range jobs {
+		items = append(items, toFailedJobItem(&jobs[i]))
+	}
+	return &dto.FailedJobListResponse{Items: items, Page: page, Limit: limit, Total: total}, nil
+}
+
+func (s *deadLetterService) GetFailedJob(id uint) (*dto.FailedJobDetailResponse, error) {
+	job, err := s.failedJobs.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.FailedJobDetailResponse{FailedJobItem: toFailedJobItem(job)}
+	if json.Valid(job.Payload) {
+		response.Payload = json.RawMessage(job.Payload)
+	} else if len(job.Payload) > 0 {
+		response.PayloadBase64 = base64.StdEncoding.EncodeToString(job.Payload)
+	}
+	return response, nil
+}
+
+// Yalnızca "failed" durumundaki kayıtlar kuyruğa geri atılır; kayıt silinmez, durumu "queued" olur.
+// İş tekrar başarısız olursa worker yeni bir failed_jobs kaydı açar
+func (s *deadLetterService) RequeueFailedJobs(req *dto.FailedJobBulkRequestDTO) (*dto.FailedJobBulkResponse, error) {
+	jobs, err := s.matchBulk(req)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.FailedJobBulkResponse{Action: AuditActionRequeue, Matched: len(jobs)}
+	var requeued []uint
+	for i := range jobs {
+		if reason := s.requeue(&jobs[i]); reason != "" {
+			response.Skipped = append(response.Skipped, dto.FailedJobSkip{ID: jobs[i].ID, Reason: reason})
+			continue
+		}
+		requeued = append(requeued, jobs[i].ID)
+	}
+
+	if err := s.failedJobs.UpdateStatus(requeued, consts.StatusQueued); err != nil {
+		// İşler kuyruğa girdi, yalnızca durum güncellenemedi: kayıt yine de denetim loguna yazılır
+		log.Printf("Requeue edilen failed job durumları güncellenemedi %v: %v", requeued, err)
+	}
+	response.Affected = len(requeued)
+	response.AuditID = s.record(AuditActionRequeue, req, requeued)
+	return response, nil
+}
+
+func (s *deadLetterService) requeue(failed *entities.FailedJob) string {
+	if failed.JobStatus != consts.StatusFailed {
+		return fmt.Sprintf("durum %q, yalnızca failed kayıtlar requeue edilebilir", failed.JobStatus)
+	}
+	if len(failed.Payload) == 0 {
+		return "payload boş"
+	}
+	job, err := queue.DeserializeJob(string(failed.Payload))
+	if err != nil {
+		return "payload çözülemedi"
+	}
+
+	// Elle requeue edilen iş otomatik retry hakkını baştan kazanır; job ID varsa aynı kayıt queued'a döner
+	job.RetryCount = 0
+	job.LastError = ""
+	job.ErrorType = ""
+	job.Status = ""
+
+	if job.Type == queue.JobMerge || job.Type == queue.JobRetry {
+		if err := s.sessions.UpdateStatus(job.UploadID, consts.StatusMerging); err != nil {
+			log.Printf("Upload durumu güncellenemedi %s: %v", job.UploadID, err)
+		}
+	}
+	if err := queue.Submit(context.Background(), s.jobs, s.tracker, job, 0); err != nil {
+		log.Printf("Failed job %d kuyruğa eklenemedi: %v", failed.ID, err)
+		return "kuyruğa eklenemedi"
+	}
+	log.Printf("Failed job %d (%s, UploadID: %s) tekrar kuyruğa eklendi", failed.ID, job.Type, job.UploadID)
+	return ""
+}
+
+func (s *deadLetterService) PurgeFailedJobs(req *dto.FailedJobBulkRequestDTO) (*dto.FailedJobBulkResponse, error) {
+	jobs, err := s.matchBulk(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(jobs))
+	for i := range jobs {
+		ids = append(ids, jobs[i].ID)
+	}
+	deleted, err := s.failedJobs.Delete(ids)
+	if err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+	log.Printf("%d failed job kaydı silindi", deleted)
+
+	return &dto.FailedJobBulkResponse{
+		Action:   AuditActionPurge,
+		Matched:  len(jobs),
+		Affected: int(deleted),
+		AuditID:  s.record(AuditActionPurge, req, ids),
+	}, nil
+}
+
+func (s *deadLetterService) ListAuditLogs(action string, page, limit int) (*dto.AuditLogListResponse, error) {
+	page, limit = normalizePage(page, limit)
+	entries, total, err := s.audit.List(action, (page-1)*limit, limit)
+	if err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+
+	items := make([]dto.AuditLogItem, 0, len(entries))
+	for _, e := range entries {
+		items = append(items, dto.AuditLogItem{
+			ID:        e.ID,
+			Action:    e.Action,
+			Actor:     e.Actor,
+			Criteria:  e.Criteria,
+			TargetIDs: e.TargetIDs,
+			Affected:  e.Affected,
+			CreatedAt: e.CreatedAt,
+		})
+	}
+	return &dto.AuditLogListResponse{Items: items, Page: page, Limit: limit, Total: total}, nil
+}
+
+// Filtresiz toplu işlem tüm tabloyu etkileyeceği için reddedilir
+func (s *deadLetterService) matchBulk(req *dto.FailedJobBulkRequestDTO) ([]entities.FailedJob, error) {
+	if len(req.IDs) == 0 && req.JobType == "" && req.Status == "" && req.OlderThan == "" && req.NewerThan == "" {
+		return nil, errors.ErrInvalidRequest(fmt.Errorf("ids ya da en az bir filtre (job_type, status, older_than, newer_than) gerekli"))
+	}
+	filter, err := buildFailedJobFilter(req.IDs, req.JobType, req.Status, req.OlderThan, req.NewerThan)
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := s.failedJobs.Find(filter)
+	if err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+	return jobs, nil
+}
+
+// İşlem yapıldıktan sonra yazılır; log yazılamazsa işlem geri alınmaz, hata loglanır
+func (s *deadLetterService) record(action string, req *dto.FailedJobBulkRequestDTO, ids []uint) uint {
+	criteria, _ := json.Marshal(req)
+	targets := make([]string, 0, len(ids))
+	for _, id := range ids {
+		targets = append(targets, strconv.FormatUint(uint64(id), 10))
+	}
+
+	actor := req.Actor
+	if actor == "" {
+		actor = "unknown"
+	}
+	entry := &entities.AdminAuditLog{
+		Action:    action,
+		Actor:     actor,
+		Criteria:  string(criteria),
+		TargetIDs: strings.Join(targets, ","),
+		Affected:  len(ids),
+	}
+	if err := s.audit.Record(entry); err != nil {
+		log.Printf("UYARI: admin işlemi denetim loguna yazılamadı (%s, actor: %s, ids: %s): %v", action, actor, entry.TargetIDs, err)
+		return 0
+	}
+	return entry.ID
+}
+
+func buildFailedJobFilter(ids []uint, jobType, status, olderThan, newerThan string) (repositories.FailedJobFilter, error) {
+	filter := repositories.FailedJobFilter{IDs: ids, JobType: jobType, Status: status}
+	now := time.Now()
+	if olderThan != "" {
+		age, err := time.ParseDuration(olderThan)
+		if err != nil {
+			return filter, errors.ErrInvalidRequest(fmt.Errorf("older_than: %w", err))
+		}
+		filter.CreatedBefore = now.Add(-age)
+	}
+	if newerThan != "" {
+		age, err := time.ParseDuration(newerThan)
+		if err != nil {
+			return filter, errors.ErrInvalidRequest(fmt.Errorf("newer_than: %w", err))
+		}
+		filter.CreatedAfter = now.Add(-age)
+	}
+	return filter, nil
+}
+
+func normalizePage(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	return page, limit
+}
+
+func toFailedJobItem(job *entities.FailedJob) dto.FailedJobItem {
+	return dto.FailedJobItem{
+		ID:        job.ID,
+		UploadID:  job.UploadID,
+		JobType:   job.JobType,
+		Status:    job.JobStatus,
+		LastError: job.LastError,
+		CreatedAt: job.CreatedAt,
+	}
+}
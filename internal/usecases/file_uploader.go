@@ -2,71 +2,254 @@ package usecases
 
 import (
 	"context"
-	"encoding/json"
-	"io"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
 	"log"
 	"mime/multipart"
 	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"file-uploader/internal/domain/dto"
+	"file-uploader/internal/domain/entities"
 	"file-uploader/internal/domain/repositories"
+	"file-uploader/internal/infrastructure/events"
 	"file-uploader/internal/infrastructure/processor"
 	"file-uploader/internal/infrastructure/queue"
+	"file-uploader/pkg/config"
 	consts "file-uploader/pkg/constants"
 	"file-uploader/pkg/errors"
+	fl "file-uploader/pkg/file"
 	"file-uploader/pkg/helper"
 
-	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
 )
 
 type UploadService interface {
+	InitUpload(req *dto.InitUploadRequestDTO) (*dto.InitUploadResponse, error)
 	GetUploadStatus(req *dto.UploadStatusRequestDTO) (*dto.UploadStatusResponse, error)
 	UploadChunk(req *dto.UploadChunkRequestDTO, fileHeader *multipart.FileHeader) (*dto.UploadChunkResponse, error)
+	QueryChunks(req *dto.ChunkQueryRequestDTO) (*dto.ChunkQueryResponse, error)
 	CompleteUpload(req *dto.CompleteUploadRequestDTO) (*dto.CompleteUploadResponse, error)
 	CancelUpload(req *dto.CancelUploadRequestDTO) (*dto.CancelUploadResponse, error)
-	HandleMergeSuccess(uploadID, filename, mergedFilePath string, totalChunks int) error
-	RetryMerge(uploadID, filename string) (string, error)
+	HandleMergeSuccess(uploadID, filename, mergedFilePath string, totalChunks int, sha256 string) error
+	// İşleme kalıcı olarak başarısız olduğunda upload'ı failed yapar ve upload.failed olayını yazar
+	FailUpload(uploadID, jobID, filename, reason string) error
+	RetryMerge(ownerID, uploadID, filename string) (string, error)
 }
 
 type uploadService struct { //* sadece jobları kuyruğa atacak
 	repo         repositories.FileUploadRepository
+	sessions     repositories.UploadSessionStore
 	storage      repositories.StorageStrategy
 	mu           sync.Mutex
-	rdb          *redis.Client
+	jobs         queue.JobQueue
+	tracker      repositories.JobRepository
+	events       events.Publisher
 	mediaService MediaService
+	quota        QuotaService
+	cfg          config.UploadConfig
 }
 
-func NewUploadService(repo repositories.FileUploadRepository, storage repositories.StorageStrategy, rdb *redis.Client, mediaService MediaService) UploadService {
+func NewUploadService(repo repositories.FileUploadRepository, sessions repositories.UploadSessionStore, storage repositories.StorageStrategy, jobs queue.JobQueue, tracker repositories.JobRepository, publisher events.Publisher, mediaService MediaService, quota QuotaService, cfg config.UploadConfig) UploadService {
 	return &uploadService{
 		repo:         repo,
+		sessions:     sessions,
 		storage:      storage,
 		mu:           sync.Mutex{}, //sonradan ekledim
-		rdb:          rdb,
+		jobs:         jobs,
+		tracker:      tracker,
+		events:       publisher,
 		mediaService: mediaService,
+		quota:        quota,
+		cfg:          cfg,
 	}
 }
 
+func (s *uploadService) InitUpload(req *dto.InitUploadRequestDTO) (*dto.InitUploadResponse, error) {
+	safeFilename := filepath.Base(req.Filename)
+	if req.Filename == "" || safeFilename == "." || safeFilename == string(filepath.Separator) {
+		return nil, errors.ErrInvalidRequest(fmt.Errorf("filename zorunlu"))
+	}
+	if req.TotalSize <= 0 || req.TotalSize > s.cfg.MaxFileSize {
+		return nil, errors.ErrInvalidRequest(fmt.Errorf("total_size 1 ile %d arasında olmalı", s.cfg.MaxFileSize))
+	}
+	if req.ChunkSize <= 0 || req.ChunkSize > s.cfg.MaxChunkSize {
+		return nil, errors.ErrInvalidRequest(fmt.Errorf("chunk_size 1 ile %d arasında olmalı", s.cfg.MaxChunkSize))
+	}
+	if req.SHA256 != "" && !isSHA256Hex(req.SHA256) {
+		return nil, errors.ErrInvalidRequest(fmt.Errorf("sha256 64 karakterlik hex olmalı"))
+	}
+
+	mimeType := req.MimeType
+	if mimeType == "" {
+		mimeType = helper.GetMimeTypeFromExtension(safeFilename)
+	}
+
+	session := &entities.Upload{
+		ID:          uuid.New().String(),
+		OwnerID:     req.OwnerID,
+		Filename:    safeFilename,
+		TotalSize:   req.TotalSize,
+		ChunkSize:   req.ChunkSize,
+		TotalChunks: int((req.TotalSize + req.ChunkSize - 1) / req.ChunkSize),
+		MimeType:    mimeType,
+		FileHash:    strings.ToLower(req.SHA256),
+		Status:      consts.StatusInProgress,
+		ExpiresAt:   time.Now().Add(s.cfg.SessionTTL),
+	}
+	if err := s.sessions.CreateSession(session); err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+
+	return &dto.InitUploadResponse{
+		UploadID:    session.ID,
+		Filename:    session.Filename,
+		TotalSize:   session.TotalSize,
+		ChunkSize:   session.ChunkSize,
+		TotalChunks: session.TotalChunks,
+		Status:      session.Status,
+		ExpiresAt:   session.ExpiresAt,
+	}, nil
+}
+
+// Chunk ve complete istekleri sadece tenant'a ait, aktif, süresi dolmamış ve dosya adı eşleşen oturumlar için kabul edilir.
+// Eksik chunk'lar için retry bekleyen oturum da aktiftir; merge'i zamanlanmış retry job'u yapar
+func (s *uploadService) activeSession(ownerID, uploadID, filename string) (*entities.Upload, error) {
+	session, err := s.sessions.GetSession(ownerID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Filename != filename {
+		return nil, errors.ErrFilenameMismatch(fmt.Errorf("beklenen: %s, gelen: %s", session.Filename, filename))
+	}
+	if isExpired(session) {
+		if err := s.sessions.UpdateStatus(uploadID, consts.StatusExpired); err != nil {
+			log.Printf("Upload durumu güncellenemedi %s: %v", uploadID, err)
+		}
+		return nil, errors.ErrUploadExpired(fmt.Errorf("oturum %s tarihinde sona erdi", session.ExpiresAt.Format(time.RFC3339)))
+	}
+	if session.Status != consts.StatusInProgress && session.Status != consts.StatusRetrying {
+		return nil, errors.ErrUploadNotActive(fmt.Errorf("upload durumu: %s", session.Status))
+	}
+	return session, nil
+}
+
+// Son chunk hariç tüm chunk'lar oturumdaki chunk_size kadar olmalı
+func expectedChunkSize(session *entities.Upload, chunkIndex int) int64 {
+	if chunkIndex < session.TotalChunks {
+		return session.ChunkSize
+	}
+	return session.TotalSize - int64(session.TotalChunks-1)*session.ChunkSize
+}
+
+func isExpired(session *entities.Upload) bool {
+	return session.Status == consts.StatusInProgress && !session.ExpiresAt.IsZero() && time.Now().After(session.ExpiresAt)
+}
+
+func isSHA256Hex(value string) bool {
+	return isHexDigest(value, sha256.Size)
+}
+
+func isHexDigest(value string, size int) bool {
+	if len(value) != size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(value)
+	return err == nil
+}
+
+// sha256 gönderilmezse init'te bildirilen değer kullanılır, ikisi birden gönderildiyse aynı olmalı
+func expectedDigest(session *entities.Upload, req *dto.CompleteUploadRequestDTO) (fl.Digest, error) {
+	digest := fl.Digest{
+		SHA256: strings.ToLower(req.SHA256),
+		MD5:    strings.ToLower(req.MD5),
+		CRC32C: strings.ToLower(req.CRC32C),
+	}
+	if digest.SHA256 == "" {
+		digest.SHA256 = session.FileHash
+	} else if session.FileHash != "" && digest.SHA256 != session.FileHash {
+		return digest, errors.ErrInvalidRequest(fmt.Errorf("sha256 init isteğinde bildirilen değerle uyuşmuyor"))
+	}
+
+	if digest.SHA256 != "" && !isSHA256Hex(digest.SHA256) {
+		return digest, errors.ErrInvalidRequest(fmt.Errorf("sha256 64 karakterlik hex olmalı"))
+	}
+	if digest.MD5 != "" && !isHexDigest(digest.MD5, md5.Size) {
+		return digest, errors.ErrInvalidRequest(fmt.Errorf("md5 32 karakterlik hex olmalı"))
+	}
+	if digest.CRC32C != "" && !isHexDigest(digest.CRC32C, crc32.Size) {
+		return digest, errors.ErrInvalidRequest(fmt.Errorf("crc32c 8 karakterlik hex olmalı"))
+	}
+	return digest, nil
+}
+
 func (s *uploadService) GetUploadStatus(req *dto.UploadStatusRequestDTO) (*dto.UploadStatusResponse, error) {
-	// Repository'den merge edilen chunk sayısını al
-	mergedChunkCount, exists := s.repo.GetUploadedChunks(req.UploadID, req.Filename)
-
-	var uploadedChunks int
-	var uploadedStatus string
-	if exists && mergedChunkCount > 0 {
-		uploadedChunks = mergedChunkCount
-		uploadedStatus = consts.StatusCompleted
-	} else {
-		uploadedChunks = 0
-		uploadedStatus = consts.StatusFailed
+	// Oturum ve chunk kayıtları server/worker ortak store'dan okunur
+	session, err := s.sessions.GetSession(req.OwnerID, req.UploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks, err := s.sessions.GetChunks(req.UploadID)
+	if err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+
+	received := make(map[int]bool, len(chunks))
+	var uploadedBytes int64
+	maxIndex := 0
+	for _, chunk := range chunks {
+		received[chunk.ChunkIndex] = true
+		uploadedBytes += chunk.Size
+		if chunk.ChunkIndex > maxIndex {
+			maxIndex = chunk.ChunkIndex
+		}
+	}
+
+	// Toplam chunk sayısı init ile belirlenir, bilinmiyorsa en büyük index'e kadar olan boşluklar eksik sayılır
+	expected := session.TotalChunks
+	if expected <= 0 {
+		expected = maxIndex
+	}
+
+	missing := make([]int, 0)
+	for i := 1; i <= expected; i++ {
+		if !received[i] {
+			missing = append(missing, i)
+		}
+	}
+
+	status := session.Status
+	if isExpired(session) {
+		status = consts.StatusExpired
+	}
+
+	progress := 0
+	if session.Status == consts.StatusCompleted {
+		progress = 100
+	} else if session.TotalChunks > 0 {
+		progress = len(received) * 100 / session.TotalChunks
 	}
 
 	response := &dto.UploadStatusResponse{
-		UploadID:       req.UploadID,
-		Filename:       req.Filename,
-		UploadedChunks: uploadedChunks,
-		Status:         uploadedStatus,
+		UploadID:       session.ID,
+		Filename:       session.Filename,
+		TotalChunks:    session.TotalChunks,
+		UploadedChunks: len(received),
+		UploadedBytes:  uploadedBytes,
+		MissingChunks:  missing,
+		Progress:       progress,
+		Status:         status,
+		ExpiresAt:      session.ExpiresAt,
+		CreatedAt:      session.CreatedAt,
+		UpdatedAt:      session.UpdatedAt,
 	}
 
 	return response, nil
@@ -81,16 +264,51 @@ func (s *uploadService) UploadChunk(req *dto.UploadChunkRequestDTO, fileHeader *
 		return nil, errors.ErrInvalidChunk(err)
 	}
 
+	// Oturum /upload/init ile açılmış olmalı
+	session, err := s.activeSession(req.OwnerID, req.UploadID, safeFilename)
+	if err != nil {
+		return nil, err
+	}
+	if idx > session.TotalChunks {
+		return nil, errors.ErrInvalidChunk(fmt.Errorf("chunk index %d, toplam chunk sayısı %d", idx, session.TotalChunks))
+	}
+	if expected := expectedChunkSize(session, idx); fileHeader.Size != expected {
+		return nil, errors.ErrChunkSizeMismatch(fmt.Errorf("chunk %d için beklenen %d byte, gelen %d byte", idx, expected, fileHeader.Size))
+	}
+
 	// Idempotent kontrol
+	mediaType := usageMediaType(safeFilename)
 	if s.repo.ChunkExists(req.UploadID, safeFilename, idx) {
-		return &dto.UploadChunkResponse{
-			Status:     consts.StatusOK,
-			UploadID:   req.UploadID,
-			ChunkIndex: idx,
-			Filename:   safeFilename,
-			Message:    "chunk zaten var",
-		}, nil
+		recorded, err := s.chunkRecorded(req.UploadID, idx)
+		if err != nil {
+			return nil, errors.ErrInternal(err)
+		}
+		if recorded {
+			return &dto.UploadChunkResponse{
+				Status:     consts.StatusOK,
+				UploadID:   req.UploadID,
+				ChunkIndex: idx,
+				Filename:   safeFilename,
+				Message:    "chunk zaten var",
+			}, nil
+		}
+		// Dosya taşınmış ama kaydı yazılamamış; kayıtsız dosya silinir ve chunk yeniden işlenir.
+		// Önceki istekte ayrılan kota da dosyayla birlikte geri bırakılır
+		if err := s.repo.DeleteChunk(req.UploadID, safeFilename, idx); err != nil {
+			return nil, errors.ErrChunkNotSave(err)
+		}
+		s.quota.Track(session.OwnerID, consts.UsageStaging, mediaType, -expectedChunkSize(session, idx))
 	}
+	// Kota chunk stage edilmeden ayrılır; chunk kuyruğa alınamazsa geri bırakılır
+	if err := s.quota.Reserve(session.OwnerID, consts.UsageStaging, mediaType, fileHeader.Size); err != nil {
+		return nil, err
+	}
+	queued := false
+	defer func() {
+		if !queued {
+			s.quota.Track(session.OwnerID, consts.UsageStaging, mediaType, -fileHeader.Size)
+		}
+	}()
 
 	// Dosyayı aç
 	file, err := fileHeader.Open()
@@ -99,42 +317,193 @@ func (s *uploadService) UploadChunk(req *dto.UploadChunkRequestDTO, fileHeader *
 	}
 	defer file.Close()
 
-	fileBytes, err := io.ReadAll(file)
+	// Chunk Redis job'una gömülmez, staging'e stream edilir; job yalnızca dosya yolunu ve hash'i taşır
+	stagingPath, chunkHash, size, err := s.repo.StageChunk(req.UploadID, safeFilename, idx, file)
 	if err != nil {
 		return nil, errors.ErrTmpFile(err)
 	}
+	if req.ChunkHash != "" && !strings.EqualFold(req.ChunkHash, chunkHash) {
+		if err := s.repo.DiscardStagedChunk(req.UploadID, stagingPath); err != nil {
+			log.Printf("Staging dosyası silinemedi %s: %v", stagingPath, err)
+		}
+		return nil, errors.ErrChecksumMismatch(fmt.Errorf("chunk %d hash uyuşmuyor", idx))
+	}
 
 	chunkJob := queue.Job{
-		UploadID:    req.UploadID,
-		Type:        queue.JobSaveChunk,
-		Filename:    safeFilename,
-		ChunkIndex:  idx,
-		FileContent: fileBytes,
-		ChunkHash:   req.ChunkHash,
+		UploadID:   req.UploadID,
+		Type:       queue.JobSaveChunk,
+		Filename:   safeFilename,
+		ChunkIndex: idx,
+		FilePath:   stagingPath,
+		ChunkHash:  chunkHash,
+		ChunkSize:  size,
 	}
 
-	serialized, err := json.Marshal(chunkJob)
-	if err != nil {
-		log.Println("Failed to serialize chunk job:", err)
-		return nil, err
+	if err := queue.Submit(context.Background(), s.jobs, s.tracker, &chunkJob, 0); err != nil {
+		if discardErr := s.repo.DiscardStagedChunk(req.UploadID, stagingPath); discardErr != nil {
+			log.Printf("Staging dosyası silinemedi %s: %v", stagingPath, discardErr)
+		}
+		return nil, errors.ErrInternal(fmt.Errorf("chunk job kuyruğa eklenemedi: %w", err))
 	}
-	s.rdb.LPush(context.Background(), "job_queue", serialized)
+	queued = true
 
 	return &dto.UploadChunkResponse{
 		Status:     consts.StatusQueued,
 		UploadID:   req.UploadID,
 		ChunkIndex: idx,
 		Filename:   safeFilename,
+		JobID:      chunkJob.ID,
 		Message:    "chunk işleme kuyruğuna alındı",
 	}, nil
 }
 
+func (s *uploadService) chunkRecorded(uploadID string, chunkIndex int) (bool, error) {
+	chunks, err := s.sessions.GetChunks(uploadID)
+	if err != nil {
+		return false, err
+	}
+	for _, chunk := range chunks {
+		if chunk.ChunkIndex == chunkIndex {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Tenant'ın havuzunda bulunan chunk'lar oturuma kaydedilir, merge sırasında havuzdan okunur; client yalnızca missing listesini gönderir
+func (s *uploadService) QueryChunks(req *dto.ChunkQueryRequestDTO) (*dto.ChunkQueryResponse, error) {
+	session, err := s.sessions.GetSession(req.OwnerID, req.UploadID)
+	if err != nil {
+		return nil, err
+	}
+	if session, err = s.activeSession(req.OwnerID, req.UploadID, session.Filename); err != nil {
+		return nil, err
+	}
+	if len(req.Chunks) == 0 {
+		return nil, errors.ErrInvalidRequest(fmt.Errorf("en az bir chunk gönderilmeli"))
+	}
+
+	hashes := make([]string, 0, len(req.Chunks))
+	seen := make(map[int]bool, len(req.Chunks))
+	for i := range req.Chunks {
+		item := &req.Chunks[i]
+		if item.Index < 1 || (session.TotalChunks > 0 && item.Index > session.TotalChunks) {
+			return nil, errors.ErrInvalidChunk(fmt.Errorf("chunk index %d, toplam chunk sayısı %d", item.Index, session.TotalChunks))
+		}
+		if seen[item.Index] {
+			return nil, errors.ErrInvalidRequest(fmt.Errorf("chunk %d birden fazla kez gönderildi", item.Index))
+		}
+		item.Hash = strings.ToLower(item.Hash)
+		if !isSHA256Hex(item.Hash) {
+			return nil, errors.ErrInvalidRequest(fmt.Errorf("chunk %d için hash 64 karakterlik hex olmalı", item.Index))
+		}
+		seen[item.Index] = true
+		hashes = append(hashes, item.Hash)
+	}
+
+	recorded, err := s.sessions.GetChunks(req.UploadID)
+	if err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+	received := make(map[int]bool, len(recorded))
+	for _, chunk := range recorded {
+		received[chunk.ChunkIndex] = true
+	}
+
+	// Yalnızca aynı tenant'ın havuzu sorgulanır; Existing/Missing başka tenant'ın verisini açığa çıkarmaz
+	pooled, err := s.repo.FindPooledChunks(session.OwnerID, hashes)
+	if err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+
+	response := &dto.ChunkQueryResponse{
+		UploadID: req.UploadID,
+		Existing: make([]int, 0),
+		Missing:  make([]int, 0),
+	}
+	// Havuzdan bağlanan chunk'lar da tenant'ın staging kullanımına yazılır
+	mediaType := usageMediaType(session.Filename)
+	for _, item := range req.Chunks {
+		if received[item.Index] {
+			response.Existing = append(response.Existing, item.Index)
+			continue
+		}
+
+		chunk, ok := pooled[item.Hash]
+		if !ok || (item.Size > 0 && item.Size != chunk.Size) || (session.TotalSize > 0 && chunk.Size != expectedChunkSize(session, item.Index)) {
+			response.Missing = append(response.Missing, item.Index)
+			continue
+		}
+		if err := s.quota.Reserve(session.OwnerID, consts.UsageStaging, mediaType, chunk.Size); err != nil {
+			return nil, err
+		}
+
+		if err := s.sessions.RecordChunk(&entities.UploadChunk{
+			UploadID:   req.UploadID,
+			ChunkIndex: item.Index,
+			Filename:   session.Filename,
+			Size:       chunk.Size,
+			Hash:       chunk.SHA256,
+		}); err != nil {
+			s.quota.Track(session.OwnerID, consts.UsageStaging, mediaType, -chunk.Size)
+			return nil, errors.ErrInternal(err)
+		}
+		response.Existing = append(response.Existing, item.Index)
+		events.Publish(s.events, events.Event{
+			Type:       events.ChunkReceived,
+			UploadID:   req.UploadID,
+			Filename:   session.Filename,
+			ChunkIndex: events.ChunkIndex(item.Index),
+			Size:       chunk.Size,
+		})
+	}
+
+	sort.Ints(response.Existing)
+	sort.Ints(response.Missing)
+	log.Printf("Chunk sorgusu %s: %d chunk havuzdan bağlandı/mevcut, %d chunk eksik", req.UploadID, len(response.Existing), len(response.Missing))
+	return response, nil
+}
+
 func (s *uploadService) CompleteUpload(req *dto.CompleteUploadRequestDTO) (*dto.CompleteUploadResponse, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	safeFilename := filepath.Base(req.Filename)
 
+	session, err := s.activeSession(req.OwnerID, req.UploadID, safeFilename)
+	if err != nil {
+		return nil, err
+	}
+	// Retry job'u zaten kuyrukta; ikinci bir merge aynı upload'ı iki kez işler
+	if session.Status == consts.StatusRetrying {
+		return nil, errors.ErrUploadNotActive(fmt.Errorf("merge eksik chunk'lar için otomatik retry bekliyor"))
+	}
+	// total_chunks opsiyonel, gönderildiyse oturumla aynı olmalı
+	if req.TotalChunks == 0 {
+		req.TotalChunks = session.TotalChunks
+	}
+	if req.TotalChunks != session.TotalChunks {
+		return nil, errors.ErrInvalidChunk(fmt.Errorf("total_chunks %d, oturumda beklenen %d", req.TotalChunks, session.TotalChunks))
+	}
+	// Chunk'lar zaten staging'de sayıldığı için yalnızca kota düşürüldüyse ya da aşıldıysa reddedilir
+	if err := s.quota.CheckQuota(session.OwnerID, 0); err != nil {
+		return nil, err
+	}
+
+	// Beklenen özetler oturuma yazılır, worker merge sırasında bunlarla doğrular
+	expected, err := expectedDigest(session, req)
+	if err != nil {
+		return nil, err
+	}
+	if expected != session.ExpectedDigest() {
+		if err := s.sessions.SetExpectedDigest(req.UploadID, expected); err != nil {
+			return nil, errors.ErrInternal(err)
+		}
+	}
+	if err := s.sessions.UpdateStatus(req.UploadID, consts.StatusMerging); err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+
 	mergeJob := queue.Job{
 		UploadID:    req.UploadID,
 		Type:        queue.JobMerge,
@@ -142,64 +511,323 @@ func (s *uploadService) CompleteUpload(req *dto.CompleteUploadRequestDTO) (*dto.
 		TotalChunks: req.TotalChunks,
 	}
 
-	serialized, err := json.Marshal(mergeJob)
-	if err != nil {
-		log.Printf("Merge job marshal failed: %v", err)
-	} else {
-		log.Printf("Merge job serialized: %s", string(serialized))
+	if err := queue.Submit(context.Background(), s.jobs, s.tracker, &mergeJob, 0); err != nil {
+		// Client complete isteğini tekrar gönderebilsin diye oturum aktif duruma döner
+		if statusErr := s.sessions.UpdateStatus(req.UploadID, consts.StatusInProgress); statusErr != nil {
+			log.Printf("Upload durumu güncellenemedi %s: %v", req.UploadID, statusErr)
+		}
+		return nil, errors.ErrInternal(fmt.Errorf("merge job kuyruğa eklenemedi: %w", err))
 	}
-	s.rdb.LPush(context.Background(), "job_queue", serialized)
 
 	return &dto.CompleteUploadResponse{
 		Status:   consts.StatusQueued,
 		Message:  "Chunked dosyalar işleme kuyruğuna alındı",
 		Filename: req.Filename,
+		JobID:    mergeJob.ID,
 	}, nil
 }
 
-func (s *uploadService) HandleMergeSuccess(uploadID, filename, mergedFilePath string, totalChunks int) error {
-	s.repo.SetUploadedChunks(uploadID, filename, totalChunks) //* status failed olarak gözüküyordu, bunu düzeltmek adına merge success'in başarılı olma durumunda status set edildi
-	if helper.IsImageFile(mergedFilePath) {
-		return processor.ProcessImageFile(s.mediaService, filename, mergedFilePath)
+func (s *uploadService) HandleMergeSuccess(uploadID, filename, mergedFilePath string, totalChunks int, sha256 string) error {
+	//* status failed olarak gözüküyordu, bunu düzeltmek adına merge success'in başarılı olma durumunda status set edildi
+	if err := s.sessions.UpdateStatus(uploadID, consts.StatusCompleted); err != nil {
+		log.Printf("Upload durumu güncellenemedi %s: %v", uploadID, err)
+	}
+	// Oluşan media kayıtları upload'ı başlatan tenant'a yazılır
+	var ownerID string
+	if session, err := s.sessions.GetSession("", uploadID); err == nil {
+		ownerID = session.OwnerID
+	} else {
+		log.Printf("UYARI: upload oturumu okunamadı %s: %v", uploadID, err)
+	}
+	isImage, isVideo := helper.IsImageFile(mergedFilePath), helper.IsVideoFile(mergedFilePath)
+	if !isImage && !isVideo {
+		log.Printf("INFO: image olmayan bir dosya yüklendi: %s", filename)
+		if err := s.completeUpload(uploadID, filename, sha256, "", false, false); err != nil {
+			return err
+		}
+		s.trackMergedUsage(uploadID, ownerID, filename, "", false)
+		return nil
+	}
+
+	// İçerik adresli dedup: aynı SHA-256 daha önce işlendiyse ikinci kopya tutulmaz, varyantlar yeniden üretilmez
+	if sha256 == "" {
+		localPath, cleanup, err := s.storage.LocalPath(mergedFilePath)
+		if err != nil {
+			return err
+		}
+		hash, err := fl.CalculateFileHash(localPath)
+		cleanup()
+		if err != nil {
+			return err
+		}
+		sha256 = hash
 	}
-	if helper.IsVideoFile(mergedFilePath) {
-		return processor.ProcessVideoFile(s.mediaService, filename, mergedFilePath)
+	blob, err := s.mediaService.AcquireBlob(sha256, mergedFilePath)
+	if err != nil {
+		return err
+	}
+
+	var mediaID string
+	if blob.OriginID != "" {
+		log.Printf("INFO: %s mevcut içeriğe bağlanıyor (origin: %s)", filename, blob.OriginID)
+		if isImage {
+			media := &dto.ImageDTO{
+				OwnerID:      ownerID,
+				OriginalName: filename,
+				FileType:     helper.GetMimeTypeFromExtension(filename),
+				SHA256:       sha256,
+			}
+			err = s.mediaService.LinkMedia(media, blob.OriginID)
+			mediaID = media.ID
+		} else {
+			video := &dto.VideoDTO{
+				OwnerID:      ownerID,
+				OriginalName: filename,
+				FileType:     helper.GetMimeTypeFromExtension(filename),
+				SHA256:       sha256,
+			}
+			err = s.mediaService.LinkVideo(video, blob.OriginID)
+			mediaID = video.VideoID
+		}
+	} else if isImage {
+		mediaID, err = processor.ProcessImageFile(s.mediaService, ownerID, filename, blob.FilePath, sha256)
+	} else {
+		mediaID, err = processor.ProcessVideoFile(s.mediaService, ownerID, filename, blob.FilePath, sha256)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Webhook olayları işleme bittikten sonra yazılır; hata durumunda listener retry ettiğinde tekrar üretilmez
+	if err := s.completeUpload(uploadID, filename, sha256, mediaID, isImage, isVideo); err != nil {
+		return err
+	}
+
+	s.trackMergedUsage(uploadID, ownerID, filename, mediaID, isImage)
+	s.publishVariants(uploadID, filename, mediaID, isImage)
+	events.Publish(s.events, events.Event{Type: events.Completed, UploadID: uploadID, Filename: filename, MediaID: mediaID})
+	return nil
+}
+
+// Merge sonrası chunk byte'ları staging'den orijinale taşınır, üretilen varyantlar ayrıca sayılır.
+// Dedup ile bağlanan kayıtlar da tenant'ın kullanımına yazılır; reconcile aynı kuralla hesaplar
+func (s *uploadService) trackMergedUsage(uploadID, ownerID, filename, mediaID string, isImage bool) {
+	mediaType := usageMediaType(filename)
+	if chunks, err := s.sessions.GetChunks(uploadID); err == nil {
+		size := chunksSize(chunks)
+		s.quota.Track(ownerID, consts.UsageStaging, mediaType, -size)
+		s.quota.Track(ownerID, consts.UsageOriginal, mediaType, size)
+	} else {
+		log.Printf("UYARI: kullanım için chunk'lar okunamadı %s: %v", uploadID, err)
+	}
+	if mediaID == "" {
+		return
+	}
+
+	var variantBytes int64
+	if isImage {
+		variants, err := s.mediaService.GetMediaVariants("", mediaID)
+		if err != nil {
+			return
+		}
+		for _, v := range variants {
+			variantBytes += fileSize(s.storage, v.FilePath)
+		}
+	} else {
+		video, err := s.mediaService.GetVideoByID("", mediaID)
+		if err != nil || video.Status != consts.VideoStatusResized {
+			return
+		}
+		variantBytes = fileSize(s.storage, video.FilePath)
+	}
+	s.quota.Track(ownerID, consts.UsageVariant, mediaType, variantBytes)
+}
+
+// completed durumu ve webhook olayları (upload.completed, media.processed / video.resized) tek transaction'da yazılır
+func (s *uploadService) completeUpload(uploadID, filename, sha256, mediaID string, isImage, isVideo bool) error {
+	data := map[string]interface{}{
+		"upload_id": uploadID,
+		"filename":  filename,
+		"sha256":    sha256,
+		"media_id":  mediaID,
+	}
+	completed, err := entities.NewOutboxEvent(entities.EventUploadCompleted, uploadID, data)
+	if err != nil {
+		return errors.ErrInternal(err)
+	}
+	outbox := []*entities.OutboxEvent{completed}
+
+	if isImage {
+		variants, err := s.mediaService.GetMediaVariants("", mediaID)
+		if err != nil {
+			return err
+		}
+		names := make([]string, 0, len(variants))
+		for _, v := range variants {
+			names = append(names, v.VariantName)
+		}
+		processed, err := entities.NewOutboxEvent(entities.EventMediaProcessed, uploadID, map[string]interface{}{
+			"upload_id": uploadID,
+			"media_id":  mediaID,
+			"filename":  filename,
+			"variants":  names,
+		})
+		if err != nil {
+			return errors.ErrInternal(err)
+		}
+		outbox = append(outbox, processed)
+	} else if isVideo {
+		video, err := s.mediaService.GetVideoByID("", mediaID)
+		if err != nil {
+			return err
+		}
+		eventType := entities.EventMediaProcessed
+		if video.Status == consts.VideoStatusResized {
+			eventType = entities.EventVideoResized
+		}
+		processed, err := entities.NewOutboxEvent(eventType, uploadID, map[string]interface{}{
+			"upload_id": uploadID,
+			"video_id":  mediaID,
+			"filename":  filename,
+			"status":    video.Status,
+			"width":     video.Width,
+			"height":    video.Height,
+		})
+		if err != nil {
+			return errors.ErrInternal(err)
+		}
+		outbox = append(outbox, processed)
+	}
+
+	if err := s.sessions.UpdateStatusWithEvents(uploadID, consts.StatusCompleted, outbox...); err != nil {
+		return errors.ErrInternal(err)
+	}
+	return nil
+}
+
+func (s *uploadService) FailUpload(uploadID, jobID, filename, reason string) error {
+	event, err := entities.NewOutboxEvent(entities.EventUploadFailed, uploadID, map[string]interface{}{
+		"upload_id": uploadID,
+		"job_id":    jobID,
+		"filename":  filename,
+		"error":     reason,
+	})
+	if err != nil {
+		return errors.ErrInternal(err)
+	}
+	if err := s.sessions.UpdateStatusWithEvents(uploadID, consts.StatusFailed, event); err != nil {
+		return errors.ErrInternal(err)
 	}
-	log.Printf("INFO: image olmayan bir dosya yüklendi: %s", filename)
 	return nil
 }
 
+// Oluşan (ya da dedup ile bağlanan) varyantlar için variant_created olayı yayınlanır
+func (s *uploadService) publishVariants(uploadID, filename, mediaID string, isImage bool) {
+	if s.events == nil || mediaID == "" {
+		return
+	}
+	if isImage {
+		variants, err := s.mediaService.GetMediaVariants("", mediaID)
+		if err != nil {
+			log.Printf("Varyantlar okunamadı %s: %v", mediaID, err)
+			return
+		}
+		for _, v := range variants {
+			events.Publish(s.events, events.Event{Type: events.VariantCreated, UploadID: uploadID, Filename: filename, MediaID: mediaID, Variant: v.VariantName})
+		}
+		return
+	}
+
+	video, err := s.mediaService.GetVideoByID("", mediaID)
+	if err != nil {
+		log.Printf("Video okunamadı %s: %v", mediaID, err)
+		return
+	}
+	if video.Status == consts.VideoStatusResized {
+		events.Publish(s.events, events.Event{Type: events.VariantCreated, UploadID: uploadID, Filename: filename, MediaID: mediaID, Variant: fmt.Sprintf("%dx%d", video.Width, video.Height)})
+	}
+}
+
 func (s *uploadService) CancelUpload(req *dto.CancelUploadRequestDTO) (*dto.CancelUploadResponse, error) {
 	//* complete upload ile race condition yaşamaması adına lock eklendi, aksi takdirde cleanup işlemi tetiklenmiyordu, çünkü sıra ona gelmiyordu
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	// Başka tenant'ın upload'ı iptal edilemez
+	if _, err := s.sessions.GetSession(req.OwnerID, req.UploadID); err != nil {
+		return nil, err
+	}
+
+	// Durum kontrolü ve güncellemesi oturum satırı kilitliyken yapılır; s.mu worker process'indeki merge'e karşı korumaz.
+	// Tamamlanan, merge edilen ya da zaten iptal edilen upload'ın dosyaları cleanup ile silinmemeli
+	var session *entities.Upload
+	var staged int64
+	err := s.sessions.LockSession(req.UploadID, func(store repositories.UploadSessionStore) error {
+		var err error
+		if session, err = store.GetSession(req.OwnerID, req.UploadID); err != nil {
+			return err
+		}
+		switch session.Status {
+		case consts.StatusInProgress, consts.StatusRetrying, consts.StatusFailed:
+		default:
+			return errors.ErrUploadNotActive(fmt.Errorf("upload durumu %s, iptal edilemez", session.Status))
+		}
+		chunks, err := store.GetChunks(req.UploadID)
+		if err != nil {
+			return errors.ErrInternal(err)
+		}
+		staged = chunksSize(chunks)
+		if err := store.UpdateStatus(req.UploadID, consts.StatusCancelled); err != nil {
+			return errors.ErrInternal(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Temizlenecek chunk'lar staging kullanımından düşülür
+	s.quota.Track(session.OwnerID, consts.UsageStaging, usageMediaType(session.Filename), -staged)
+
 	cleanupJob := queue.Job{
 		UploadID: req.UploadID,
 		Type:     queue.JobCleanup,
 	}
 
-	serialized, _ := json.Marshal(cleanupJob)
-	s.rdb.LPush(context.Background(), "job_queue", serialized)
+	if err := queue.Submit(context.Background(), s.jobs, s.tracker, &cleanupJob, 0); err != nil {
+		log.Printf("Cleanup job kuyruğa eklenemedi %s: %v", req.UploadID, err)
+	}
 
 	return &dto.CancelUploadResponse{
 		Status:  consts.StatusQueued,
 		Message: "Upload iptal edildi",
+		JobID:   cleanupJob.ID,
 	}, nil
 }
 
-func (s *uploadService) RetryMerge(uploadID, filename string) (string, error) {
+func (s *uploadService) RetryMerge(ownerID, uploadID, filename string) (string, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	finalPath, merged, err := s.repo.RetryMerge(uploadID, filename)
+	// Retry'da da CompleteUpload'da gönderilen özetler doğrulanır
+	session, err := s.sessions.GetSession(ownerID, uploadID)
+	if err != nil {
+		return "", err
+	}
+	expected := session.ExpectedDigest()
+
+	finalPath, merged, digest, err := s.repo.RetryMerge(uploadID, filename, session.TotalChunks, expected)
 	if err != nil {
 		log.Printf("Retry merge işlemi dosya %s için başarısız oldu: %v", filename, err)
+		if uploadErr, ok := err.(*errors.UploadError); ok && uploadErr.Code == "checksum_mismatch" {
+			if statusErr := s.sessions.UpdateStatus(uploadID, consts.StatusFailed); statusErr != nil {
+				log.Printf("Upload durumu güncellenemedi %s: %v", uploadID, statusErr)
+			}
+		}
 		return "", err
 	}
 	log.Printf("Retry merge işlemi dosya %s için başarıyla gerçekleşti: %s", filename, finalPath)
 
-	if err := s.HandleMergeSuccess(uploadID, filename, finalPath, merged); err != nil {
+	if err := s.HandleMergeSuccess(uploadID, filename, finalPath, merged, digest.SHA256); err != nil {
 		log.Printf("Merge'den sonra verinin işlenmesi başarıyla gerçekleşti %s: %v", filename, err)
 		return finalPath, err
 	}
@@ -0,0 +1,67 @@
+package usecases
+
+import (
+	"file-uploader/internal/domain/dto"
+	"file-uploader/internal/domain/entities"
+	"file-uploader/internal/domain/repositories"
+	"file-uploader/pkg/errors"
+)
+
+// İşler upload oturumunun tenant'ına göre kapsamlanır; başka tenant'ın işleri not_found döner
+type JobService interface {
+	GetJob(ownerID, id string) (*dto.JobResponse, error)
+	ListUploadJobs(ownerID, uploadID string) (*dto.UploadJobsResponse, error)
+}
+
+type jobService struct {
+	jobs     repositories.JobRepository
+	sessions repositories.UploadSessionStore
+}
+
+func NewJobService(jobs repositories.JobRepository, sessions repositories.UploadSessionStore) JobService {
+	return &jobService{
+		jobs:     jobs,
+		sessions: sessions,
+	}
+}
+
+func (s *jobService) GetJob(ownerID, id string) (*dto.JobResponse, error) {
+	job, err := s.jobs.GetByID(ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	response := toJobResponse(job)
+	return &response, nil
+}
+
+// Upload'a ait tüm işler oluşturulma sırasıyla döner (chunk'lar, merge, cleanup)
+func (s *jobService) ListUploadJobs(ownerID, uploadID string) (*dto.UploadJobsResponse, error) {
+	if _, err := s.sessions.GetSession(ownerID, uploadID); err != nil {
+		return nil, err
+	}
+	jobs, err := s.jobs.ListByUpload(ownerID, uploadID)
+	if err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+
+	response := &dto.UploadJobsResponse{UploadID: uploadID, Jobs: make([]dto.JobResponse, 0, len(jobs))}
+	for i := range jobs {
+		response.Jobs = append(response.Jobs, toJobResponse(&jobs[i]))
+	}
+	return response, nil
+}
+
+func toJobResponse(job *entities.Job) dto.JobResponse {
+	return dto.JobResponse{
+		ID:         job.ID,
+		UploadID:   job.UploadID,
+		Type:       job.Type,
+		Status:     job.Status,
+		Attempts:   job.Attempts,
+		LastError:  job.LastError,
+		CreatedAt:  job.CreatedAt,
+		UpdatedAt:  job.UpdatedAt,
+		StartedAt:  job.StartedAt,
+		FinishedAt: job.FinishedAt,
+	}
+}
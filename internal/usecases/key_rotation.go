@@ -0,0 +1,78 @@
+package usecases
+
+import (
+	"fmt"
+	"log"
+
+	"file-uploader/internal/domain/repositories"
+	"file-uploader/internal/infrastructure/storage"
+)
+
+type KeyRotationReport struct {
+	Rotated int
+	Failed  int
+	Keys    map[string]int64 // rotasyon sonrası key ID başına kayıt sayısı
+}
+
+// Eski master key'lerle sarılmış veri anahtarlarını aktif anahtarla yeniden sarar.
+// Dosyalar yeniden şifrelenmez; yalnızca encrypted_objects kayıtları güncellenir
+type KeyRotationService struct {
+	objects repositories.EncryptedObjectRepository
+	keyring *storage.Keyring
+}
+
+func NewKeyRotationService(objects repositories.EncryptedObjectRepository, keyring *storage.Keyring) *KeyRotationService {
+	return &KeyRotationService{
+		objects: objects,
+		keyring: keyring,
+	}
+}
+
+// Açılamayan kayıtlar (anahtarı keyring'de olmayan) atlanıp sayılır; tekrar çalıştırmak güvenlidir
+func (s *KeyRotationService) Rotate(batchSize int) (*KeyRotationReport, error) {
+	if batchSize < 1 {
+		batchSize = 500
+	}
+	active := s.keyring.ActiveKeyID()
+	report := &KeyRotationReport{}
+
+	afterBackend, afterLocation := "", ""
+	for {
+		objects, err := s.objects.ListWrappedWithOtherKey(active, afterBackend, afterLocation, batchSize)
+		if err != nil {
+			return report, fmt.Errorf("şifreli dosya kayıtları alınamadı: %w", err)
+		}
+		if len(objects) == 0 {
+			break
+		}
+		for _, object := range objects {
+			afterBackend, afterLocation = object.Backend, object.Location
+
+			dataKey, err := s.keyring.Unwrap(object.KeyID, object.WrappedKey)
+			if err != nil {
+				log.Printf("UYARI: %s/%s anahtarı açılamadı: %v", object.Backend, object.Location, err)
+				report.Failed++
+				continue
+			}
+			keyID, wrappedKey, err := s.keyring.Wrap(dataKey)
+			if err != nil {
+				return report, err
+			}
+			// Kayıt bu arada yeniden yazıldıysa zaten aktif anahtarla sarılmıştır
+			rewrapped, err := s.objects.Rewrap(object.Backend, object.Location, object.KeyID, keyID, wrappedKey)
+			if err != nil {
+				return report, fmt.Errorf("%s/%s anahtarı güncellenemedi: %w", object.Backend, object.Location, err)
+			}
+			if rewrapped {
+				report.Rotated++
+			}
+		}
+	}
+
+	keys, err := s.objects.CountByKey()
+	if err != nil {
+		return report, err
+	}
+	report.Keys = keys
+	return report, nil
+}
@@ -5,25 +5,39 @@ import (
 	"file-uploader/internal/domain/entities"
 	"file-uploader/internal/domain/repositories"
 	"file-uploader/internal/infrastructure/processor"
+	consts "file-uploader/pkg/constants"
+	"file-uploader/pkg/errors"
+	fl "file-uploader/pkg/file"
 	"file-uploader/pkg/helper"
 	"fmt"
 	"log"
+	"mime/multipart"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// ownerID alan metotlar tenant'a göre kapsamlanır; boş ownerID worker ve sistem işlemleri içindir
 type MediaService interface { //video da eklenecek
 	// Images
+	StoreOriginal(file multipart.File, filename string) (string, error)
 	CreateMedia(media *dto.ImageDTO, filepath string) error
-	GetMediaByID(id string) (*dto.ImageDTO, error)
+	GetMediaByID(ownerID, id string) (*dto.ImageDTO, error)
 	UpdateMediaStatus(id string, status string) error
-	GetAllMedia() ([]*dto.ImageDTO, error)
+	GetAllMedia(ownerID string) ([]*dto.ImageDTO, error)
+	// Image ya da video; varyantları ve media_jobs geçmişiyle birlikte (GET /media/{id})
+	GetMediaDetail(ownerID, id string) (*dto.Media, error)
+	// Filtreli, cursor ile sayfalanan listeleme (GET /media, GET /video)
+	ListMedia(ownerID string, req *dto.MediaListRequestDTO) (*dto.MediaListResponse, error)
+	ListVideos(ownerID string, req *dto.MediaListRequestDTO) (*dto.VideoListResponse, error)
 
 	// Media Variant
-	CreateVariantsForMedia(mediaID, originalPath string) error
+	CreateVariantsForMedia(ownerID, mediaID, originalPath string) error
+	GetMediaVariants(ownerID, mediaID string) ([]*dto.MediaVariant, error)
 
 	// Media Size
 	CreateSize(size *dto.MediaSize) error
@@ -31,10 +45,29 @@ type MediaService interface { //video da eklenecek
 
 	//Video
 	CreateVideo(video *dto.VideoDTO) error
-	GetVideoByID(id string) (*dto.VideoDTO, error)
+	GetVideoByID(ownerID, id string) (*dto.VideoDTO, error)
 	ResizeByWidth(id string, width int64, video *dto.VideoDTO) error
 	ResizeByHeight(id string, height int64, video *dto.VideoDTO) error
 	ResizeVideo(id string, width int64, height int64, video *dto.VideoDTO) error
+
+	// Dedup
+	AcquireBlob(sha256, mergedFilePath string) (*entities.Blob, error)
+	MarkBlobOrigin(sha256, originID string) error
+	LinkMedia(media *dto.ImageDTO, originID string) error
+	LinkVideo(video *dto.VideoDTO, originID string) error
+
+	// Delete: kayıt çöp kutusuna taşınır, dosyalar retention süresi dolunca PurgeDeleted ile silinir
+	DeleteMedia(ownerID, id string) error
+	DeleteVideo(ownerID, id string) error
+	GetDeletedMedia(ownerID string) ([]*dto.ImageDTO, error)
+	GetDeletedVideos(ownerID string) ([]*dto.VideoDTO, error)
+	RestoreMedia(ownerID, id string) error
+	RestoreVideo(ownerID, id string) error
+	PurgeDeleted(before time.Time) (int, error)
+
+	// Content (StorageStrategy.Download ile stream edilir)
+	GetMediaContent(ownerID, id, variant string) (*dto.MediaContent, error)
+	GetVideoContent(ownerID, id, variant string) (*dto.MediaContent, error)
 }
 
 type mediaService struct {
@@ -43,6 +76,9 @@ type mediaService struct {
 	sizeRepo    repositories.MediaSizeRepository
 	storage     repositories.StorageStrategy
 	videoRepo   repositories.VideoRepository
+	blobRepo    repositories.BlobRepository
+	jobRepo     repositories.MediaJobRepository
+	quota       QuotaService
 }
 
 func NewMediaService(
@@ -51,6 +87,9 @@ func NewMediaService(
 	sizeRepo repositories.MediaSizeRepository,
 	storage repositories.StorageStrategy,
 	videoRepo repositories.VideoRepository,
+	blobRepo repositories.BlobRepository,
+	jobRepo repositories.MediaJobRepository,
+	quota QuotaService,
 ) MediaService {
 	return &mediaService{
 		mediaRepo:   mediaRepo,
@@ -58,10 +97,18 @@ func NewMediaService(
 		sizeRepo:    sizeRepo,
 		storage:     storage,
 		videoRepo:   videoRepo,
+		blobRepo:    blobRepo,
+		jobRepo:     jobRepo,
+		quota:       quota,
 	}
 }
 
 // Images
+// Doğrudan yüklenen (chunk'sız) dosya türüne göre orijinal key'ine yazılır, saklandığı konum döner
+func (s *mediaService) StoreOriginal(file multipart.File, filename string) (string, error) {
+	return s.storage.Save(file, fl.OriginalKey(filepath.Base(filename)))
+}
+
 func (u *mediaService) CreateMedia(media *dto.ImageDTO, finalPath string) error {
 	// İş mantığı: desteklenen dosya tiplerini kontrol et
 	if media.FileType != "image/png" && media.FileType != "image/jpeg" && media.FileType != "image/jpg" && media.FileType != "image/gif" && media.FileType != "image/bmp" && media.FileType != "image/tiff" && media.FileType != "image/webp" && media.FileType != "image/svg+xml" {
@@ -78,14 +125,14 @@ func (s *mediaService) CreateVideo(video *dto.VideoDTO) error {
 	if video.FileType != "video/mp4" && video.FileType != "video/avi" && video.FileType != "video/mkv" {
 		return fmt.Errorf("unsupported file type: %s", video.FileType)
 	}
-	if _, err := os.Stat(video.FilePath); os.IsNotExist(err) {
+	if !s.storage.FileExists(video.FilePath) {
 		return fmt.Errorf("video file does not exist at path: %s", video.FilePath)
 	}
 	return s.videoRepo.CreateVideo(video)
 }
 
-func (s *mediaService) GetMediaByID(id string) (*dto.ImageDTO, error) {
-	return s.mediaRepo.GetMediaByID(id)
+func (s *mediaService) GetMediaByID(ownerID, id string) (*dto.ImageDTO, error) {
+	return s.mediaRepo.GetMediaByID(ownerID, id)
 }
 
 func (s *mediaService) UpdateMediaStatus(id string, status string) error {
@@ -101,32 +148,53 @@ func contains(slice []string, item string) bool {
 	return false
 }
 
-func (u *mediaService) GetAllMedia() ([]*dto.ImageDTO, error) {
-	return u.mediaRepo.GetAllMedia()
+func (u *mediaService) GetAllMedia(ownerID string) ([]*dto.ImageDTO, error) {
+	return u.mediaRepo.GetAllMedia(ownerID)
 }
 
-func (s *mediaService) CreateVariantsForMedia(mediaID, originalPath string) error {
+// Varyantlar media ile aynı tenant'a yazılır
+func (s *mediaService) CreateVariantsForMedia(ownerID, mediaID, originalPath string) error {
 	sizes, err := s.sizeRepo.GetAllSizes()
 	if err != nil {
 		return fmt.Errorf("failed to get media sizes: %w", err)
 	}
 
+	// Orijinal S3'teyse bir kez indirilir, tüm boyutlar aynı yerel kopyadan üretilir
+	inputPath, cleanup, err := s.storage.LocalPath(originalPath)
+	if err != nil {
+		return fmt.Errorf("orijinal dosya okunamadı: %w", err)
+	}
+	defer cleanup()
+
 	for _, size := range sizes {
 		baseName := filepath.Base(originalPath)
 		ext := filepath.Ext(baseName)
 		nameWithoutExt := strings.TrimSuffix(baseName, ext)
-		outputDir := filepath.Join("uploads", "media", "variants", fmt.Sprintf("%s", mediaID)) // id klasörü içerisinde oluşturuldu ki karmaşıklık yaşanmasın
-		if err := os.MkdirAll(outputDir, os.ModePerm); err != nil {
-			return fmt.Errorf("variants klasörü oluşturulamadı: %w", err)
-		}
 
 		variantName := fmt.Sprintf("%s_%s_%dx%d", nameWithoutExt, size.VariantType, size.Width, size.Height)
 
-		outputPath := filepath.Join(outputDir, fmt.Sprintf("%s_%s_%dx%d%s", nameWithoutExt, size.VariantType, size.Width, size.Height, ext)) // isimlendirme
-		resizedPath, err := processor.ResizeImage(originalPath, outputPath, processor.ResizeOption{
-			Width:   size.Width,
-			Height:  size.Height,
-			Quality: 100,
+		// id klasörü içerisinde oluşturuldu ki karmaşıklık yaşanmasın
+		key := fl.VariantKey(mediaID, variantName+ext) // isimlendirme
+		job := &entities.MediaJob{
+			MediaID:   mediaID,
+			MediaType: consts.MediaTypeImage,
+			OwnerID:   ownerID,
+			Type:      consts.MediaJobVariant,
+			Params: map[string]string{
+				"variant_type": size.VariantType,
+				"width":        strconv.Itoa(size.Width),
+				"height":       strconv.Itoa(size.Height),
+			},
+		}
+		resizedPath, err := s.runJob(job, func() (string, error) {
+			return s.storeProcessed(key, func(outputPath string) error {
+				_, err := processor.ResizeImage(inputPath, outputPath, processor.ResizeOption{
+					Width:   size.Width,
+					Height:  size.Height,
+					Quality: 100,
+				})
+				return err
+			})
 		})
 
 		if err != nil {
@@ -136,6 +204,7 @@ func (s *mediaService) CreateVariantsForMedia(mediaID, originalPath string) erro
 		variant := &dto.MediaVariant{
 			VariantID:   uuid.New().String(),
 			MediaID:     mediaID,
+			OwnerID:     ownerID,
 			FilePath:    resizedPath,
 			Width:       size.Width,
 			Height:      size.Height,
@@ -150,6 +219,10 @@ func (s *mediaService) CreateVariantsForMedia(mediaID, originalPath string) erro
 	return nil
 }
 
+func (s *mediaService) GetMediaVariants(ownerID, mediaID string) ([]*dto.MediaVariant, error) {
+	return s.variantRepo.GetVariantsByMediaID(ownerID, mediaID)
+}
+
 // Media Size
 func (s *mediaService) CreateSize(size *dto.MediaSize) error {
 	return s.sizeRepo.CreateSize(size)
@@ -160,12 +233,17 @@ func (s *mediaService) UpdateSize(size *dto.MediaSize) error {
 }
 
 // Video:
-func (s *mediaService) GetVideoByID(id string) (*dto.VideoDTO, error) {
-	return s.videoRepo.GetVideoByID(id)
+func (s *mediaService) GetVideoByID(ownerID, id string) (*dto.VideoDTO, error) {
+	return s.videoRepo.GetVideoByID(ownerID, id)
 }
 
 func (s *mediaService) ResizeByWidth(id string, width int64, video *dto.VideoDTO) error {
-	inputPath := fmt.Sprintf("./uploads/videos/original/%s%s", video.VideoID, filepath.Ext(video.FilePath))
+	inputPath, cleanup, err := s.storage.LocalPath(video.FilePath)
+	if err != nil {
+		return fmt.Errorf("orijinal video okunamadı: %w", err)
+	}
+	defer cleanup()
+
 	if video.Width <= 0 || video.Height <= 0 {
 		origWidth, origHeight, err := helper.GetVideoDimensions(inputPath)
 		log.Printf("Orijinal video boyutları: %dx%d", origWidth, origHeight)
@@ -186,8 +264,13 @@ func (s *mediaService) ResizeByWidth(id string, width int64, video *dto.VideoDTO
 	video.Width = width
 
 	// Fiziksel dosya resize
-	outputPath := fmt.Sprintf("./uploads/videos/resized/%s_%dx%d%s", video.VideoID, video.Width, video.Height, filepath.Ext(video.FilePath))
-	if err := processor.ResizeByWidth(inputPath, outputPath, video.Width); err != nil {
+	job := videoResizeJob(video, map[string]string{"width": strconv.FormatInt(width, 10)})
+	outputPath, err := s.runJob(job, func() (string, error) {
+		return s.storeProcessed(resizedVideoKey(video), func(outputPath string) error {
+			return processor.ResizeByWidth(inputPath, outputPath, video.Width)
+		})
+	})
+	if err != nil {
 		return err
 	}
 	video.FilePath = outputPath
@@ -219,9 +302,20 @@ func (s *mediaService) ResizeByHeight(id string, height int64, video *dto.VideoD
 	video.Height = height
 	video.Status = "resized"
 
+	inputPath, cleanup, err := s.storage.LocalPath(video.FilePath)
+	if err != nil {
+		return fmt.Errorf("orijinal video okunamadı: %w", err)
+	}
+	defer cleanup()
+
 	// Fiziksel dosya resize
-	outputPath := fmt.Sprintf("./uploads/videos/resized/%s_%dx%d%s", video.VideoID, video.Width, video.Height, filepath.Ext(video.FilePath))
-	if err := processor.ResizeByHeight(video.FilePath, outputPath, video.Height); err != nil {
+	job := videoResizeJob(video, map[string]string{"height": strconv.FormatInt(height, 10)})
+	outputPath, err := s.runJob(job, func() (string, error) {
+		return s.storeProcessed(resizedVideoKey(video), func(outputPath string) error {
+			return processor.ResizeByHeight(inputPath, outputPath, video.Height)
+		})
+	})
+	if err != nil {
 		return err
 	}
 	video.FilePath = outputPath
@@ -243,23 +337,23 @@ func (s *mediaService) ResizeVideo(id string, width int64, height int64, video *
 	video.Width = width
 	video.Height = height
 
-	baseDir, err := os.Getwd() // absolute path
+	inputPath, cleanup, err := s.storage.LocalPath(video.FilePath)
 	if err != nil {
-		return fmt.Errorf("failed to get working directory: %w", err)
-	}
-
-	resizedDir := filepath.Join(baseDir, "uploads", "videos", "resized")
-	if err := os.MkdirAll(resizedDir, os.ModePerm); err != nil {
-		return fmt.Errorf("failed to create resized dir: %w", err)
+		return fmt.Errorf("failed to read video: %w", err)
 	}
-
-	outputPath := filepath.Join(
-		resizedDir,
-		fmt.Sprintf("%s_%dx%d%s", video.VideoID, video.Width, video.Height, filepath.Ext(video.FilePath)),
-	)
+	defer cleanup()
 
 	// ffmpeg ile resize
-	if err := processor.ResizeVideo(video.FilePath, outputPath, width, height); err != nil {
+	job := videoResizeJob(video, map[string]string{
+		"width":  strconv.FormatInt(width, 10),
+		"height": strconv.FormatInt(height, 10),
+	})
+	outputPath, err := s.runJob(job, func() (string, error) {
+		return s.storeProcessed(resizedVideoKey(video), func(outputPath string) error {
+			return processor.ResizeVideo(inputPath, outputPath, width, height)
+		})
+	})
+	if err != nil {
 		return fmt.Errorf("failed to resize video: %w", err)
 	}
 
@@ -279,3 +373,232 @@ func (s *mediaService) ResizeVideo(id string, width int64, height int64, video *
 
 	return s.videoRepo.ResizeVideo(&entity)
 }
+
+func resizedVideoKey(video *dto.VideoDTO) string {
+	return fl.ResizedVideoKey(video.VideoID, video.Width, video.Height, filepath.Ext(video.FilePath))
+}
+
+// Varyant/boyutlandırılmış video önce geçici dosyaya üretilir, ardından storage'a key ile yazılır.
+// Geçici dosya key ile aynı uzantıyı taşır (imaging ve ffmpeg çıktı formatını uzantıdan belirler)
+func (s *mediaService) storeProcessed(key string, produce func(outputPath string) error) (string, error) {
+	tmpDir, err := os.MkdirTemp("", "processed-*")
+	if err != nil {
+		return "", fmt.Errorf("geçici klasör oluşturulamadı: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	outputPath := filepath.Join(tmpDir, filepath.Base(key))
+	if err := produce(outputPath); err != nil {
+		return "", err
+	}
+	return s.storage.Store(outputPath, key)
+}
+
+// Dedup:
+// Merge edilen dosya SHA-256'sına göre blob'a bağlanır; içerik daha önce yüklendiyse yeni kopya silinir ve
+// dönen blob'un FilePath'i kullanılır. OriginID doluysa varyantlar yeniden üretilmeden Link* ile kayıt açılır
+func (s *mediaService) AcquireBlob(sha256, mergedFilePath string) (*entities.Blob, error) {
+	size, err := s.storage.Size(mergedFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("merge edilen dosya okunamadı: %w", err)
+	}
+
+	blob, created, err := s.blobRepo.Acquire(sha256, mergedFilePath, size)
+	if err != nil {
+		return nil, fmt.Errorf("blob alınamadı: %w", err)
+	}
+	if created || blob.FilePath == mergedFilePath {
+		return blob, nil
+	}
+
+	// Blob'un dosyası diskte yoksa tek sağlam kopya yeni merge edilen dosyadır
+	if !s.storage.FileExists(blob.FilePath) {
+		log.Printf("UYARI: blob %s dosyası bulunamadı (%s), yeni kopya kullanılacak", sha256, blob.FilePath)
+		if err := s.blobRepo.Relocate(sha256, mergedFilePath); err != nil {
+			return nil, fmt.Errorf("blob taşınamadı: %w", err)
+		}
+		blob.FilePath = mergedFilePath
+		blob.OriginID = ""
+		return blob, nil
+	}
+
+	s.removeFile(mergedFilePath)
+	log.Printf("INFO: %s içeriği zaten mevcut, mevcut blob kullanılacak: %s (referans: %d)", sha256, blob.FilePath, blob.RefCount)
+	return blob, nil
+}
+
+func (s *mediaService) MarkBlobOrigin(sha256, originID string) error {
+	return s.blobRepo.SetOrigin(sha256, originID)
+}
+
+// Origin media'nın varyant dosyaları paylaşılır, yalnızca yeni varyant kayıtları açılır.
+// Origin başka bir tenant'a ait olabilir; yeni kayıtlar media.OwnerID ile açılır
+func (s *mediaService) LinkMedia(media *dto.ImageDTO, originID string) error {
+	// Origin çöp kutusunda olabilir, dosyaları kalıcı silinene kadar paylaşılır
+	origin, err := s.mediaRepo.GetMediaByIDWithDeleted(originID)
+	if err != nil {
+		s.releaseBlob(media.SHA256)
+		return fmt.Errorf("origin media bulunamadı: %w", err)
+	}
+	variants, err := s.variantRepo.GetVariantsByMediaIDWithDeleted(originID)
+	if err != nil {
+		s.releaseBlob(media.SHA256)
+		return fmt.Errorf("origin media varyantları alınamadı: %w", err)
+	}
+
+	media.FilePath = origin.FilePath
+	media.Status = origin.Status
+	if err := s.CreateMedia(media, origin.FilePath); err != nil {
+		s.releaseBlob(media.SHA256)
+		return err
+	}
+
+	for _, variant := range variants {
+		if err := s.variantRepo.CreateVariant(&dto.MediaVariant{
+			VariantID:   uuid.New().String(),
+			MediaID:     media.ID,
+			OwnerID:     media.OwnerID,
+			FilePath:    variant.FilePath,
+			Width:       variant.Width,
+			Height:      variant.Height,
+			VariantName: variant.VariantName,
+		}, s.mediaRepo); err != nil {
+			return fmt.Errorf("media varyantı oluşturulamadı: %w", err)
+		}
+	}
+	return nil
+}
+
+// Origin videonun (boyutlandırılmış) dosyası ve boyutları paylaşılır
+func (s *mediaService) LinkVideo(video *dto.VideoDTO, originID string) error {
+	origin, err := s.videoRepo.GetVideoByIDWithDeleted(originID)
+	if err != nil {
+		s.releaseBlob(video.SHA256)
+		return fmt.Errorf("origin video bulunamadı: %w", err)
+	}
+
+	video.FilePath = origin.FilePath
+	video.Width = origin.Width
+	video.Height = origin.Height
+	video.Status = origin.Status
+	if err := s.CreateVideo(video); err != nil {
+		s.releaseBlob(video.SHA256)
+		return err
+	}
+	return nil
+}
+
+// Purge:
+// Kayıt ve varyant satırları kalıcı silinir; dosyalar yalnızca onlara başka referans kalmadıysa silinir
+func (s *mediaService) purgeMedia(media *dto.ImageDTO) error {
+	id := media.ID
+	variants, err := s.variantRepo.GetVariantsByMediaIDWithDeleted(id)
+	if err != nil {
+		return errors.ErrInternal(err)
+	}
+
+	if err := s.variantRepo.DeleteVariantsByMediaID("", id); err != nil {
+		return errors.ErrInternal(err)
+	}
+	if err := s.mediaRepo.DeleteMedia("", id); err != nil {
+		return errors.ErrInternal(err)
+	}
+	if err := s.jobRepo.DeleteByMediaID(id); err != nil {
+		log.Printf("UYARI: media %s iş geçmişi silinemedi: %v", id, err)
+	}
+
+	var variantBytes int64
+	for _, variant := range variants {
+		variantBytes += fileSize(s.storage, variant.FilePath)
+	}
+	for _, variant := range variants {
+		count, err := s.variantRepo.CountByFilePath(variant.FilePath)
+		if err != nil {
+			log.Printf("Varyant referansları sayılamadı %s: %v", variant.FilePath, err)
+			continue
+		}
+		if count == 0 {
+			s.removeFile(variant.FilePath)
+		}
+	}
+
+	_, originalBytes := s.releaseOriginal(media.SHA256, media.FilePath)
+	s.quota.Track(media.OwnerID, consts.UsageOriginal, consts.MediaTypeImage, -originalBytes)
+	s.quota.Track(media.OwnerID, consts.UsageVariant, consts.MediaTypeImage, -variantBytes)
+	return nil
+}
+
+func (s *mediaService) purgeVideo(video *dto.VideoDTO) error {
+	if err := s.videoRepo.DeleteVideo("", video.VideoID); err != nil {
+		return errors.ErrInternal(err)
+	}
+	if err := s.jobRepo.DeleteByMediaID(video.VideoID); err != nil {
+		log.Printf("UYARI: video %s iş geçmişi silinemedi: %v", video.VideoID, err)
+	}
+	videoBytes := fileSize(s.storage, video.FilePath)
+
+	// Boyutlandırılmış dosya aynı içeriğe sahip videolarca paylaşılıyor olabilir
+	count, err := s.videoRepo.CountByFilePath(video.FilePath)
+	if err != nil {
+		log.Printf("Video referansları sayılamadı %s: %v", video.FilePath, err)
+	} else if count == 0 {
+		defer s.removeFile(video.FilePath)
+	}
+
+	originalPath, originalBytes := s.releaseOriginal(video.SHA256, "")
+	switch {
+	case originalPath == "":
+		// Blob'u olmayan kayıtta video dosyasının kendisi orijinaldir
+		s.quota.Track(video.OwnerID, consts.UsageOriginal, consts.MediaTypeVideo, -videoBytes)
+	case originalPath != video.FilePath:
+		s.quota.Track(video.OwnerID, consts.UsageOriginal, consts.MediaTypeVideo, -originalBytes)
+		s.quota.Track(video.OwnerID, consts.UsageVariant, consts.MediaTypeVideo, -videoBytes)
+	default:
+		s.quota.Track(video.OwnerID, consts.UsageOriginal, consts.MediaTypeVideo, -originalBytes)
+	}
+	return nil
+}
+
+// Blob referansı bırakılır, son referans gittiyse orijinal dosya silinir.
+// Dedup öncesi oluşturulan (blob'u olmayan) kayıtlarda fallbackPath doğrudan silinir.
+// Kullanımdan düşülmesi için orijinalin yolu ve boyutu döner
+func (s *mediaService) releaseOriginal(sha256, fallbackPath string) (string, int64) {
+	if sha256 == "" {
+		size := fileSize(s.storage, fallbackPath)
+		s.removeFile(fallbackPath)
+		return fallbackPath, size
+	}
+	blob, err := s.blobRepo.Release(sha256)
+	if err != nil {
+		if uploadErr, ok := err.(*errors.UploadError); ok && uploadErr.Code == "not_found" {
+			size := fileSize(s.storage, fallbackPath)
+			s.removeFile(fallbackPath)
+			return fallbackPath, size
+		}
+		log.Printf("Blob referansı bırakılamadı %s: %v", sha256, err)
+		return "", 0
+	}
+	if blob.RefCount == 0 {
+		log.Printf("INFO: blob %s için son referans silindi, dosya kaldırılıyor: %s", sha256, blob.FilePath)
+		s.removeFile(blob.FilePath)
+	}
+	return blob.FilePath, blob.Size
+}
+
+func (s *mediaService) releaseBlob(sha256 string) {
+	if sha256 == "" {
+		return
+	}
+	if _, err := s.blobRepo.Release(sha256); err != nil {
+		log.Printf("Blob referansı bırakılamadı %s: %v", sha256, err)
+	}
+}
+
+func (s *mediaService) removeFile(path string) {
+	if path == "" {
+		return
+	}
+	if err := s.storage.DeleteFile(path); err != nil && !os.IsNotExist(err) {
+		log.Printf("Dosya silinemedi %s: %v", path, err)
+	}
+}
@@ -0,0 +1,222 @@
+package usecases
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"file-uploader/internal/domain/dto"
+	"file-uploader/internal/domain/entities"
+	consts "file-uploader/pkg/constants"
+	"file-uploader/pkg/errors"
+)
+
+// Images ve videos ayrı tablolarda durur; GET /media/{id} id'yi önce images'ta, sonra videos'ta arar ve
+// kaydı ortak Media görünümüne çevirir. Varyant üretimi ve video boyutlandırma media_jobs'a yazılır.
+// Storage yolları yerine dosyaların content endpoint URL'leri döner
+
+// Content endpoint'leri: <path><id>/content[?variant=<varyant>]
+const (
+	MediaContentPath = "/api/v1/media/"
+	VideoContentPath = "/api/v1/video/"
+)
+
+func (s *mediaService) GetMediaDetail(ownerID, id string) (*dto.Media, error) {
+	image, err := s.mediaRepo.GetMediaByID(ownerID, id)
+	if err == nil {
+		return s.imageDetail(image)
+	}
+	// Yalnızca images'ta yoksa videos'a bakılır; DB hatası video aramasıyla 404'e dönüşmemeli
+	if !isNotFound(err) {
+		return nil, errors.ErrInternal(err)
+	}
+	video, err := s.videoRepo.GetVideoByID(ownerID, id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, errors.ErrNotFound(fmt.Errorf("media bulunamadı: %s", id))
+		}
+		return nil, errors.ErrInternal(err)
+	}
+	return s.videoDetail(video)
+}
+
+func (s *mediaService) imageDetail(image *dto.ImageDTO) (*dto.Media, error) {
+	variants, err := s.variantRepo.GetVariantsByMediaID(image.OwnerID, image.ID)
+	if err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+	jobs, err := s.jobRepo.ListByMediaID(image.ID)
+	if err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+
+	size := fileSize(s.storage, image.FilePath)
+	media := &entities.Media{
+		MediaID:      image.ID,
+		OwnerID:      image.OwnerID,
+		Type:         consts.MediaTypeImage,
+		Filename:     image.OriginalName,
+		FileType:     image.FileType,
+		FileSize:     size,
+		Status:       image.Status,
+		OriginalPath: image.FilePath,
+		SHA256:       image.SHA256,
+		Metadata:     entities.Metadata{Format: fileFormat(image.OriginalName), Size: size},
+		Jobs:         jobs,
+		CreatedAt:    image.CreatedAt,
+		UpdatedAt:    image.UpdatedAt,
+	}
+	return toMediaDTO(media, variants), nil
+}
+
+// Boyutlandırılan videonun FilePath'i çıktıyı gösterir; orijinal blob'dan bulunur, çıktı "resized" varyantı olur
+func (s *mediaService) videoDetail(video *dto.VideoDTO) (*dto.Media, error) {
+	jobs, err := s.jobRepo.ListByMediaID(video.VideoID)
+	if err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+
+	originalPath := video.FilePath
+	if video.SHA256 != "" {
+		if blob, err := s.blobRepo.Get(video.SHA256); err == nil {
+			originalPath = blob.FilePath
+		}
+	}
+	var variants []*dto.MediaVariant
+	if video.Status == consts.VideoStatusResized && video.FilePath != originalPath {
+		variants = append(variants, &dto.MediaVariant{
+			MediaID:     video.VideoID,
+			OwnerID:     video.OwnerID,
+			VariantName: consts.ContentVariantResized,
+			Width:       int(video.Width),
+			Height:      int(video.Height),
+			FilePath:    video.FilePath,
+		})
+	}
+
+	size := fileSize(s.storage, originalPath)
+	media := &entities.Media{
+		MediaID:      video.VideoID,
+		OwnerID:      video.OwnerID,
+		Type:         consts.MediaTypeVideo,
+		Filename:     video.OriginalName,
+		FileType:     video.FileType,
+		FileSize:     size,
+		Status:       video.Status,
+		OriginalPath: originalPath,
+		SHA256:       video.SHA256,
+		Metadata: entities.Metadata{
+			Width:  int(video.Width),
+			Height: int(video.Height),
+			Format: fileFormat(video.OriginalName),
+			Size:   size,
+		},
+		Jobs:      jobs,
+		CreatedAt: video.CreatedAt,
+		UpdatedAt: video.UpdatedAt,
+	}
+	return toMediaDTO(media, variants), nil
+}
+
+// İş kaydı açılamazsa işlem yine çalışır; media_jobs yalnızca geçmiş içindir
+func (s *mediaService) runJob(job *entities.MediaJob, run func() (string, error)) (string, error) {
+	job.Status = consts.StatusProcessing
+	recorded := true
+	if err := s.jobRepo.Create(job); err != nil {
+		log.Printf("UYARI: media %s için %s işi kaydedilemedi: %v", job.MediaID, job.Type, err)
+		recorded = false
+	}
+
+	outputPath, err := run()
+	if recorded {
+		status, message := consts.StatusCompleted, ""
+		if err != nil {
+			status, message = consts.StatusFailed, err.Error()
+		}
+		if finishErr := s.jobRepo.Finish(job.JobID, status, outputPath, message); finishErr != nil {
+			log.Printf("UYARI: media işi %s güncellenemedi: %v", job.JobID, finishErr)
+		}
+	}
+	return outputPath, err
+}
+
+func videoResizeJob(video *dto.VideoDTO, params map[string]string) *entities.MediaJob {
+	return &entities.MediaJob{
+		MediaID:   video.VideoID,
+		MediaType: consts.MediaTypeVideo,
+		OwnerID:   video.OwnerID,
+		Type:      consts.MediaJobResize,
+		Params:    params,
+	}
+}
+
+func toMediaDTO(media *entities.Media, variants []*dto.MediaVariant) *dto.Media {
+	response := &dto.Media{
+		MediaID:    media.MediaID,
+		OwnerID:    media.OwnerID,
+		Type:       media.Type,
+		Filename:   media.Filename,
+		FileType:   media.FileType,
+		FileSize:   media.FileSize,
+		Status:     media.Status,
+		ContentURL: contentURL(media.Type, media.MediaID, ""),
+		SHA256:     media.SHA256,
+		Variants:   make([]dto.MediaVariant, 0, len(variants)),
+		Jobs:       make([]dto.MediaJob, 0, len(media.Jobs)),
+		Metadata: dto.Metadata{
+			Width:    media.Metadata.Width,
+			Height:   media.Metadata.Height,
+			Format:   media.Metadata.Format,
+			Duration: media.Metadata.Duration,
+			Size:     media.Metadata.Size,
+		},
+		CreatedAt: media.CreatedAt,
+		UpdatedAt: media.UpdatedAt,
+	}
+	for _, variant := range variants {
+		item := *variant
+		item.FilePath = ""
+		item.ContentURL = contentURL(media.Type, media.MediaID, variant.VariantName)
+		response.Variants = append(response.Variants, item)
+	}
+	for _, job := range media.Jobs {
+		width, _ := strconv.Atoi(job.Params["width"])
+		height, _ := strconv.Atoi(job.Params["height"])
+		response.Jobs = append(response.Jobs, dto.MediaJob{
+			JobID:      job.JobID,
+			MediaID:    job.MediaID,
+			Type:       job.Type,
+			Status:     job.Status,
+			Params:     job.Params,
+			Width:      width,
+			Height:     height,
+			OutputPath: job.OutputPath,
+			Error:      job.Error,
+			CreatedAt:  job.CreatedAt,
+			UpdatedAt:  job.UpdatedAt,
+			FinishedAt: job.FinishedAt,
+		})
+	}
+	return response
+}
+
+// variant boşsa orijinal dosyanın URL'i döner
+func contentURL(mediaType, id, variant string) string {
+	base := MediaContentPath
+	if mediaType == consts.MediaTypeVideo {
+		base = VideoContentPath
+	}
+	contentURL := base + url.PathEscape(id) + "/content"
+	if variant != "" {
+		contentURL += "?variant=" + url.QueryEscape(variant)
+	}
+	return contentURL
+}
+
+// "photo.JPG" -> "jpg"
+func fileFormat(filename string) string {
+	return strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), "."))
+}
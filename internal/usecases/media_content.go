@@ -0,0 +1,126 @@
+package usecases
+
+import (
+	"fmt"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"file-uploader/internal/domain/dto"
+	consts "file-uploader/pkg/constants"
+	"file-uploader/pkg/errors"
+	"file-uploader/pkg/helper"
+)
+
+// Image için variant boşsa ya da "original" ise orijinal, değilse varyant adı (ör. "thumbnail" ya da tam varyant adı) sunulur
+func (s *mediaService) GetMediaContent(ownerID, id, variant string) (*dto.MediaContent, error) {
+	media, err := s.mediaRepo.GetMediaByID(ownerID, id)
+	if err != nil {
+		return nil, errors.ErrNotFound(fmt.Errorf("media bulunamadı: %s", id))
+	}
+	if variant == "" || variant == consts.ContentVariantOriginal {
+		return s.openContent(media.FilePath, media.OriginalName, media.FileType, media.SHA256)
+	}
+
+	variants, err := s.variantRepo.GetVariantsByMediaID(ownerID, id)
+	if err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+	for _, v := range variants {
+		if matchesVariant(v.VariantName, variant) {
+			return s.openContent(v.FilePath, variantFilename(media.OriginalName, v.VariantName), media.FileType, "")
+		}
+	}
+	return nil, errors.ErrNotFound(fmt.Errorf("media %s için %q varyantı yok", id, variant))
+}
+
+// Video için variant boşsa ya da "original" ise orijinal, "resized" ise boyutlandırılmış dosya sunulur
+func (s *mediaService) GetVideoContent(ownerID, id, variant string) (*dto.MediaContent, error) {
+	video, err := s.videoRepo.GetVideoByID(ownerID, id)
+	if err != nil {
+		return nil, errors.ErrNotFound(fmt.Errorf("video bulunamadı: %s", id))
+	}
+
+	switch variant {
+	case "", consts.ContentVariantOriginal:
+		// Boyutlandırılan videonun FilePath'i çıktıyı gösterir, orijinal blob'dan bulunur
+		path := video.FilePath
+		if video.SHA256 != "" {
+			if blob, err := s.blobRepo.Get(video.SHA256); err == nil {
+				path = blob.FilePath
+			}
+		}
+		if path != video.FilePath || video.Status != consts.VideoStatusResized {
+			return s.openContent(path, video.OriginalName, video.FileType, video.SHA256)
+		}
+		return nil, errors.ErrNotFound(fmt.Errorf("video %s için orijinal dosya bulunamadı", id))
+	case consts.ContentVariantResized:
+		if video.Status != consts.VideoStatusResized {
+			return nil, errors.ErrNotFound(fmt.Errorf("video %s boyutlandırılmamış", id))
+		}
+		name := variantFilename(video.OriginalName, fmt.Sprintf("%dx%d", video.Width, video.Height))
+		return s.openContent(video.FilePath, name, helper.GetMimeTypeFromExtension(video.FilePath), "")
+	default:
+		return nil, errors.ErrInvalidRequest(fmt.Errorf("video için variant %q desteklenmiyor (original | resized)", variant))
+	}
+}
+
+// Orijinal dosyalar içerik özetiyle, varyantlar boyut ve değişiklik zamanıyla ETag alır.
+// Dosya burada açılmaz; koşullu istekler ve geçersiz Range'ler Stat bilgisiyle yanıtlanır
+func (s *mediaService) openContent(path, filename, contentType, sha256 string) (*dto.MediaContent, error) {
+	if path == "" {
+		return nil, errors.ErrNotFound(fmt.Errorf("dosya yolu boş"))
+	}
+	info, err := s.storage.Stat(path)
+	if err != nil {
+		return nil, contentError(err)
+	}
+
+	content := &dto.MediaContent{
+		Open: func() (multipart.File, error) {
+			file, err := s.storage.Download(path)
+			if err != nil {
+				return nil, contentError(err)
+			}
+			return file, nil
+		},
+		Size:        info.Size(),
+		ModTime:     info.ModTime().UTC().Truncate(time.Second),
+		Filename:    filename,
+		ContentType: contentType,
+	}
+	if content.ContentType == "" {
+		content.ContentType = helper.GetMimeTypeFromExtension(path)
+	}
+	if sha256 != "" {
+		content.ETag = fmt.Sprintf("%q", sha256)
+	} else {
+		content.ETag = fmt.Sprintf(`"%x-%x"`, content.ModTime.Unix(), content.Size)
+	}
+	return content, nil
+}
+
+func contentError(err error) error {
+	if os.IsNotExist(err) {
+		return errors.ErrNotFound(err)
+	}
+	return errors.ErrInternal(err)
+}
+
+// Varyant adları <dosya>_<tip>_<genişlik>x<yükseklik> biçimindedir; tam ad ya da yalnızca tip ile eşleşir
+func matchesVariant(name, variant string) bool {
+	if name == variant {
+		return true
+	}
+	if i := strings.LastIndex(name, "_"); i >= 0 {
+		name = name[:i]
+	}
+	return strings.HasSuffix(name, "_"+variant)
+}
+
+func variantFilename(original, suffix string) string {
+	ext := filepath.Ext(original)
+	return fmt.Sprintf("%s_%s%s", strings.TrimSuffix(original, ext), suffix, ext)
+}
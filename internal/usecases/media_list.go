@@ -0,0 +1,155 @@
+package usecases
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"file-uploader/internal/domain/dto"
+	"file-uploader/internal/domain/repositories"
+	"file-uploader/pkg/errors"
+)
+
+// Listeleme keyset sayfalama ile yapılır: limit+1 kayıt okunur, fazladan kayıt varsa son döndürülen
+// kaydın sıralama değeri ve id'si next_cursor olarak döner. Çöp kutusundaki kayıtlar listelenmez
+
+// next_cursor içeriği; sıralama alanı ve yönü de tutulur, farklı sıralamayla kullanılan cursor reddedilir
+type mediaCursor struct {
+	Sort  string `json:"s"`
+	Desc  bool   `json:"d"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+func (s *mediaService) ListMedia(ownerID string, req *dto.MediaListRequestDTO) (*dto.MediaListResponse, error) {
+	filter, err := buildMediaListFilter(ownerID, req)
+	if err != nil {
+		return nil, err
+	}
+	limit := filter.Limit
+	filter.Limit++
+
+	media, total, err := s.mediaRepo.ListMedia(filter)
+	if err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+
+	response := &dto.MediaListResponse{Items: media, Total: total, Limit: limit}
+	if len(media) > limit {
+		response.Items = media[:limit]
+		last := media[limit-1]
+		response.NextCursor = encodeMediaCursor(filter, last.ID, last.OriginalName, last.CreatedAt, last.UpdatedAt)
+	}
+	return response, nil
+}
+
+func (s *mediaService) ListVideos(ownerID string, req *dto.MediaListRequestDTO) (*dto.VideoListResponse, error) {
+	filter, err := buildMediaListFilter(ownerID, req)
+	if err != nil {
+		return nil, err
+	}
+	limit := filter.Limit
+	filter.Limit++
+
+	videos, total, err := s.videoRepo.ListVideos(filter)
+	if err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+
+	response := &dto.VideoListResponse{Items: videos, Total: total, Limit: limit}
+	if len(videos) > limit {
+		response.Items = videos[:limit]
+		last := videos[limit-1]
+		response.NextCursor = encodeMediaCursor(filter, last.VideoID, last.OriginalName, last.CreatedAt, last.UpdatedAt)
+	}
+	return response, nil
+}
+
+func buildMediaListFilter(ownerID string, req *dto.MediaListRequestDTO) (repositories.MediaListFilter, error) {
+	_, limit := normalizePage(1, req.Limit)
+	filter := repositories.MediaListFilter{
+		OwnerID:    ownerID,
+		Status:     req.Status,
+		FileType:   req.FileType,
+		NamePrefix: req.NamePrefix,
+		Sort:       req.Sort,
+		Limit:      limit,
+	}
+
+	switch filter.Sort {
+	case "":
+		filter.Sort = "created_at"
+	case "created_at", "updated_at", "original_name":
+	default:
+		return filter, errors.ErrInvalidRequest(fmt.Errorf("sort created_at, updated_at ya da original_name olmalı"))
+	}
+	switch req.Order {
+	case "", "desc":
+		filter.Desc = true
+	case "asc":
+	default:
+		return filter, errors.ErrInvalidRequest(fmt.Errorf("order asc ya da desc olmalı"))
+	}
+
+	if req.CreatedAfter != "" {
+		after, err := time.Parse(time.RFC3339, req.CreatedAfter)
+		if err != nil {
+			return filter, errors.ErrInvalidRequest(fmt.Errorf("created_after: %w", err))
+		}
+		filter.CreatedAfter = after
+	}
+	if req.CreatedBefore != "" {
+		before, err := time.Parse(time.RFC3339, req.CreatedBefore)
+		if err != nil {
+			return filter, errors.ErrInvalidRequest(fmt.Errorf("created_before: %w", err))
+		}
+		filter.CreatedBefore = before
+	}
+
+	if req.Cursor != "" {
+		cursor, err := decodeMediaCursor(req.Cursor)
+		if err != nil {
+			return filter, errors.ErrInvalidRequest(fmt.Errorf("cursor geçersiz: %w", err))
+		}
+		if cursor.Sort != filter.Sort || cursor.Desc != filter.Desc {
+			return filter, errors.ErrInvalidRequest(fmt.Errorf("cursor farklı bir sıralama için üretilmiş"))
+		}
+		if cursor.Sort != "original_name" {
+			if _, err := time.Parse(time.RFC3339Nano, cursor.Value); err != nil {
+				return filter, errors.ErrInvalidRequest(fmt.Errorf("cursor geçersiz: %w", err))
+			}
+		}
+		filter.After = &repositories.MediaCursor{Value: cursor.Value, ID: cursor.ID}
+	}
+	return filter, nil
+}
+
+func encodeMediaCursor(filter repositories.MediaListFilter, id, originalName string, createdAt, updatedAt time.Time) string {
+	cursor := mediaCursor{Sort: filter.Sort, Desc: filter.Desc, ID: id}
+	switch filter.Sort {
+	case "original_name":
+		cursor.Value = originalName
+	case "updated_at":
+		cursor.Value = updatedAt.Format(time.RFC3339Nano)
+	default:
+		cursor.Value = createdAt.Format(time.RFC3339Nano)
+	}
+	encoded, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+func decodeMediaCursor(value string) (*mediaCursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	var cursor mediaCursor
+	if err := json.Unmarshal(decoded, &cursor); err != nil {
+		return nil, err
+	}
+	if cursor.ID == "" {
+		return nil, fmt.Errorf("id eksik")
+	}
+	return &cursor, nil
+}
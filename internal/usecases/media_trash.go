@@ -0,0 +1,145 @@
+package usecases
+
+import (
+	"log"
+	"time"
+
+	"file-uploader/internal/domain/dto"
+	"file-uploader/pkg/config"
+	"file-uploader/pkg/errors"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Çöp kutusu: silinen media/video kayıtları varyantlarıyla birlikte soft delete edilir, dosyalar storage'da kalır
+// ve kota kullanımından düşülmez. TRASH_RETENTION süresi dolunca PurgeDeleted kayıtları ve dosyaları kalıcı siler
+
+const trashPurgeBatch = 100
+
+func (s *mediaService) DeleteMedia(ownerID, id string) error {
+	if _, err := s.mediaRepo.GetMediaByID(ownerID, id); err != nil {
+		return errors.ErrNotFound(err)
+	}
+	if err := s.mediaRepo.SoftDeleteMedia(ownerID, id); err != nil {
+		return notFoundOrInternal(err)
+	}
+	// Varyantlar taşınamazsa media çöp kutusunda kalır; restore ikisini birlikte geri alır
+	if err := s.variantRepo.SoftDeleteVariantsByMediaID(ownerID, id); err != nil {
+		return errors.ErrInternal(err)
+	}
+	return nil
+}
+
+func (s *mediaService) DeleteVideo(ownerID, id string) error {
+	if err := s.videoRepo.SoftDeleteVideo(ownerID, id); err != nil {
+		return notFoundOrInternal(err)
+	}
+	return nil
+}
+
+func (s *mediaService) GetDeletedMedia(ownerID string) ([]*dto.ImageDTO, error) {
+	media, err := s.mediaRepo.GetDeletedMedia(ownerID)
+	if err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+	return media, nil
+}
+
+func (s *mediaService) GetDeletedVideos(ownerID string) ([]*dto.VideoDTO, error) {
+	videos, err := s.videoRepo.GetDeletedVideos(ownerID)
+	if err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+	return videos, nil
+}
+
+func (s *mediaService) RestoreMedia(ownerID, id string) error {
+	if err := s.mediaRepo.RestoreMedia(ownerID, id); err != nil {
+		return notFoundOrInternal(err)
+	}
+	if err := s.variantRepo.RestoreVariantsByMediaID(ownerID, id); err != nil {
+		return errors.ErrInternal(err)
+	}
+	return nil
+}
+
+func (s *mediaService) RestoreVideo(ownerID, id string) error {
+	if err := s.videoRepo.RestoreVideo(ownerID, id); err != nil {
+		return notFoundOrInternal(err)
+	}
+	return nil
+}
+
+// before'dan önce silinmiş kayıtlar kalıcı silinir; silinemeyen kayıt loglanıp bir sonraki çalışmaya bırakılır
+func (s *mediaService) PurgeDeleted(before time.Time) (int, error) {
+	purged := 0
+	for {
+		media, err := s.mediaRepo.GetMediaDeletedBefore(before, trashPurgeBatch)
+		if err != nil {
+			return purged, errors.ErrInternal(err)
+		}
+		done := 0
+		for _, m := range media {
+			if err := s.purgeMedia(m); err != nil {
+				log.Printf("UYARI: media %s çöp kutusundan silinemedi: %v", m.ID, err)
+				continue
+			}
+			done++
+		}
+		purged += done
+		if len(media) < trashPurgeBatch || done == 0 {
+			break
+		}
+	}
+
+	for {
+		videos, err := s.videoRepo.GetVideosDeletedBefore(before, trashPurgeBatch)
+		if err != nil {
+			return purged, errors.ErrInternal(err)
+		}
+		done := 0
+		for _, v := range videos {
+			if err := s.purgeVideo(v); err != nil {
+				log.Printf("UYARI: video %s çöp kutusundan silinemedi: %v", v.VideoID, err)
+				continue
+			}
+			done++
+		}
+		purged += done
+		if len(videos) < trashPurgeBatch || done == 0 {
+			break
+		}
+	}
+	return purged, nil
+}
+
+// Retention süresini dolduran çöp kutusu kayıtlarını periyodik olarak kalıcı siler
+func ScheduleTrashPurge(media MediaService, cfg config.TrashConfig) *cron.Cron {
+	c := cron.New(cron.WithSeconds())
+	if _, err := c.AddFunc(cfg.PurgeCron, func() {
+		log.Println("Running scheduled trash purge...")
+		purged, err := media.PurgeDeleted(time.Now().Add(-cfg.Retention))
+		if err != nil {
+			log.Printf("Error purging trash: %v", err)
+		}
+		if purged > 0 {
+			log.Printf("INFO: çöp kutusundan %d kayıt kalıcı olarak silindi", purged)
+		}
+	}); err != nil {
+		log.Printf("UYARI: çöp kutusu temizlik job'u eklenemedi (TRASH_PURGE_CRON=%q): %v", cfg.PurgeCron, err)
+	}
+	c.Start()
+	return c
+}
+
+func notFoundOrInternal(err error) error {
+	if isNotFound(err) {
+		return err
+	}
+	return errors.ErrInternal(err)
+}
+
+func isNotFound(err error) bool {
+	uploadErr, ok := err.(*errors.UploadError)
+	return ok && uploadErr.Code == "not_found"
+}
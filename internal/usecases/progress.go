@@ -0,0 +1,37 @@
+package usecases
+
+import (
+	"context"
+
+	"file-uploader/internal/domain/repositories"
+	"file-uploader/internal/infrastructure/events"
+	"file-uploader/pkg/errors"
+)
+
+// SSE/WebSocket abonelikleri: upload oturumu doğrulandıktan sonra olay yoluna bağlanır
+type ProgressService interface {
+	Subscribe(ctx context.Context, ownerID, uploadID string) (<-chan events.Event, func(), error)
+}
+
+type progressService struct {
+	sessions   repositories.UploadSessionStore
+	subscriber events.Subscriber
+}
+
+func NewProgressService(sessions repositories.UploadSessionStore, subscriber events.Subscriber) ProgressService {
+	return &progressService{
+		sessions:   sessions,
+		subscriber: subscriber,
+	}
+}
+
+func (s *progressService) Subscribe(ctx context.Context, ownerID, uploadID string) (<-chan events.Event, func(), error) {
+	if _, err := s.sessions.GetSession(ownerID, uploadID); err != nil {
+		return nil, nil, err
+	}
+	ch, cancel, err := s.subscriber.Subscribe(ctx, uploadID)
+	if err != nil {
+		return nil, nil, errors.ErrInternal(err)
+	}
+	return ch, cancel, nil
+}
@@ -0,0 +1,271 @@
+package usecases
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"file-uploader/internal/domain/dto"
+	"file-uploader/internal/domain/entities"
+	"file-uploader/internal/domain/repositories"
+	"file-uploader/pkg/config"
+	consts "file-uploader/pkg/constants"
+	"file-uploader/pkg/errors"
+	"file-uploader/pkg/helper"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Kota staging, orijinal ve varyant byte'larının toplamına uygulanır.
+// Boş ownerID (auth kapalı, sistem işlemleri) için kota uygulanmaz
+type QuotaService interface {
+	// Kullanım incoming byte eklendiğinde kotayı aşacaksa quota_exceeded döner; kullanımı değiştirmez
+	CheckQuota(ownerID string, incoming int64) error
+	// incoming byte kotayı aşmıyorsa kullanıma tek adımda eklenir, aşıyorsa quota_exceeded döner ve sayaç değişmez.
+	// Eşzamanlı istekler aynı boş alanı birlikte geçemez. İşlem sonradan başarısız olursa Track(-incoming) ile geri bırakılmalı
+	Reserve(ownerID, category, mediaType string, incoming int64) error
+	// Sayaç güncellemesi akışı bozmaz, hata loglanır; sapmalar reconcile ile düzelir
+	Track(ownerID, category, mediaType string, delta int64)
+	GetUsage(ownerID string) (*dto.UsageResponse, error)
+	SetQuota(ownerID string, maxBytes int64) (*dto.QuotaResponse, error)
+	// Sayaçları DB kayıtları ve diskteki dosya boyutlarıyla yeniden hesaplar
+	Reconcile() error
+}
+
+type quotaService struct {
+	usage   repositories.UsageRepository
+	storage repositories.StorageStrategy // reconcile'da dosya boyutları buradan okunur
+	cfg     config.QuotaConfig
+}
+
+func NewQuotaService(usage repositories.UsageRepository, storage repositories.StorageStrategy, cfg config.QuotaConfig) QuotaService {
+	return &quotaService{
+		usage:   usage,
+		storage: storage,
+		cfg:     cfg,
+	}
+}
+
+func (s *quotaService) CheckQuota(ownerID string, incoming int64) error {
+	if ownerID == "" {
+		return nil
+	}
+	limit, err := s.limit(ownerID)
+	if err != nil {
+		return err
+	}
+	if limit <= 0 {
+		return nil
+	}
+	used, err := s.used(ownerID)
+	if err != nil {
+		return err
+	}
+	if used+incoming > limit {
+		return errors.ErrQuotaExceeded(fmt.Errorf("kullanım %d + %d byte, kota %d byte", used, incoming, limit))
+	}
+	return nil
+}
+
+func (s *quotaService) Reserve(ownerID, category, mediaType string, incoming int64) error {
+	if ownerID == "" || incoming <= 0 {
+		s.Track(ownerID, category, mediaType, incoming)
+		return nil
+	}
+	limit, err := s.limit(ownerID)
+	if err != nil {
+		return err
+	}
+	if limit <= 0 {
+		s.Track(ownerID, category, mediaType, incoming)
+		return nil
+	}
+	reserved, used, err := s.usage.Reserve(ownerID, category, mediaType, incoming, limit)
+	if err != nil {
+		return errors.ErrInternal(err)
+	}
+	if !reserved {
+		return errors.ErrQuotaExceeded(fmt.Errorf("kullanım %d + %d byte, kota %d byte", used, incoming, limit))
+	}
+	return nil
+}
+
+func (s *quotaService) Track(ownerID, category, mediaType string, delta int64) {
+	if err := s.usage.Add(ownerID, category, mediaType, delta); err != nil {
+		log.Printf("UYARI: kullanım sayacı güncellenemedi (%s/%s/%s %+d): %v", ownerID, category, mediaType, delta, err)
+	}
+}
+
+func (s *quotaService) GetUsage(ownerID string) (*dto.UsageResponse, error) {
+	rows, err := s.usage.ListByOwner(ownerID)
+	if err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+	limit, err := s.limit(ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.UsageResponse{
+		OwnerID:    ownerID,
+		QuotaBytes: limit,
+		ByMediaType: map[string]dto.UsageBreakdown{
+			consts.MediaTypeImage: {},
+			consts.MediaTypeVideo: {},
+			consts.MediaTypeOther: {},
+		},
+	}
+	for _, row := range rows {
+		breakdown := response.ByMediaType[row.MediaType]
+		addUsage(&breakdown, row.Category, row.Bytes)
+		response.ByMediaType[row.MediaType] = breakdown
+		addUsage(&response.Totals, row.Category, row.Bytes)
+	}
+	response.UsedBytes = response.Totals.TotalBytes
+	if limit > 0 {
+		remaining := max(limit-response.UsedBytes, 0)
+		response.RemainingBytes = &remaining
+	}
+	return response, nil
+}
+
+func (s *quotaService) SetQuota(ownerID string, maxBytes int64) (*dto.QuotaResponse, error) {
+	if ownerID == "" {
+		return nil, errors.ErrInvalidRequest(fmt.Errorf("owner_id zorunlu"))
+	}
+	if maxBytes < 0 {
+		return nil, errors.ErrInvalidRequest(fmt.Errorf("max_bytes negatif olamaz"))
+	}
+	quota := &entities.TenantQuota{OwnerID: ownerID, MaxBytes: maxBytes}
+	if err := s.usage.SetQuota(quota); err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+	return &dto.QuotaResponse{
+		OwnerID:   quota.OwnerID,
+		MaxBytes:  quota.MaxBytes,
+		UpdatedAt: quota.UpdatedAt,
+	}, nil
+}
+
+func (s *quotaService) Reconcile() error {
+	recorded, err := s.usage.SumRecorded()
+	if err != nil {
+		return err
+	}
+	files, err := s.usage.ListStoredFiles()
+	if err != nil {
+		return err
+	}
+
+	type usageKey struct{ owner, category, mediaType string }
+	totals := make(map[usageKey]int64)
+	for _, row := range recorded {
+		totals[usageKey{row.OwnerID, row.Category, row.MediaType}] += row.Bytes
+	}
+	missing := 0
+	for _, file := range files {
+		size, err := s.storage.Size(file.FilePath)
+		if err != nil {
+			missing++
+			continue
+		}
+		totals[usageKey{file.OwnerID, file.Category, file.MediaType}] += size
+	}
+	if missing > 0 {
+		log.Printf("UYARI: reconcile sırasında %d dosya diskte bulunamadı, kullanıma eklenmedi", missing)
+	}
+
+	now := time.Now()
+	usage := make([]entities.StorageUsage, 0, len(totals))
+	owners := make(map[string]bool)
+	for key, bytes := range totals {
+		usage = append(usage, entities.StorageUsage{
+			OwnerID:   key.owner,
+			Category:  key.category,
+			MediaType: key.mediaType,
+			Bytes:     bytes,
+			UpdatedAt: now,
+		})
+		owners[key.owner] = true
+	}
+	if err := s.usage.ReplaceAll(usage); err != nil {
+		return err
+	}
+	log.Printf("Kullanım sayaçları eşitlendi: %d tenant, %d dosya", len(owners), len(files))
+	return nil
+}
+
+// Tenant'a özel kota yoksa QUOTA_DEFAULT_BYTES kullanılır
+func (s *quotaService) limit(ownerID string) (int64, error) {
+	quota, err := s.usage.GetQuota(ownerID)
+	if err == nil {
+		return quota.MaxBytes, nil
+	}
+	if uploadErr, ok := err.(*errors.UploadError); ok && uploadErr.Code == "not_found" {
+		return s.cfg.DefaultBytes, nil
+	}
+	return 0, errors.ErrInternal(err)
+}
+
+func (s *quotaService) used(ownerID string) (int64, error) {
+	rows, err := s.usage.ListByOwner(ownerID)
+	if err != nil {
+		return 0, errors.ErrInternal(err)
+	}
+	var total int64
+	for _, row := range rows {
+		total += row.Bytes
+	}
+	return total, nil
+}
+
+func addUsage(breakdown *dto.UsageBreakdown, category string, bytes int64) {
+	switch category {
+	case consts.UsageStaging:
+		breakdown.StagingBytes += bytes
+	case consts.UsageOriginal:
+		breakdown.OriginalBytes += bytes
+	case consts.UsageVariant:
+		breakdown.VariantBytes += bytes
+	}
+	breakdown.TotalBytes += bytes
+}
+
+// Dosya adının uzantısına göre kullanım kırılımındaki medya tipi
+func usageMediaType(filename string) string {
+	switch {
+	case helper.IsImageFile(filename):
+		return consts.MediaTypeImage
+	case helper.IsVideoFile(filename):
+		return consts.MediaTypeVideo
+	default:
+		return consts.MediaTypeOther
+	}
+}
+
+// Boyutu okunamayan dosya (silinmiş, paylaşılan blob taşınmış) 0 sayılır, reconcile düzeltir
+func fileSize(storage repositories.StorageStrategy, path string) int64 {
+	if path == "" {
+		return 0
+	}
+	size, err := storage.Size(path)
+	if err != nil {
+		return 0
+	}
+	return size
+}
+
+// Kullanım sayaçlarını periyodik olarak diskle eşitler; worker (ya da server içi worker'lar) çalıştırır
+func ScheduleUsageReconcile(quota QuotaService, cfg config.QuotaConfig) *cron.Cron {
+	c := cron.New(cron.WithSeconds())
+	if _, err := c.AddFunc(cfg.ReconcileCron, func() {
+		log.Println("Running scheduled usage reconcile...")
+		if err := quota.Reconcile(); err != nil {
+			log.Printf("Error reconciling storage usage: %v", err)
+		}
+	}); err != nil {
+		log.Printf("UYARI: kullanım reconcile job'u eklenemedi (QUOTA_RECONCILE_CRON=%q): %v", cfg.ReconcileCron, err)
+	}
+	c.Start()
+	return c
+}
@@ -0,0 +1,314 @@
+package usecases
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strings"
+	"time"
+
+	"file-uploader/internal/domain/dto"
+	"file-uploader/internal/domain/entities"
+	"file-uploader/internal/domain/repositories"
+	"file-uploader/pkg/config"
+	consts "file-uploader/pkg/constants"
+	"file-uploader/pkg/errors"
+	"file-uploader/pkg/helper"
+
+	"github.com/google/uuid"
+)
+
+// S3 part numarası sınırı
+const S3MaxPartNumber = 10000
+
+// ownerID, isteği imzalayan SigV4 access key'inin S3_GATEWAY_KEYS'te bağlı olduğu tenant'tır;
+// aynı tenant'ın REST API'si ve S3 gateway'i aynı kotayı ve oturumları paylaşır
+type S3GatewayService interface {
+	CreateMultipartUpload(ownerID, key, contentType string) (string, error)
+	UploadPart(ownerID, key, uploadID string, partNumber int, length int64, body io.Reader) (string, error)
+	CompleteMultipartUpload(ownerID, key, uploadID string, parts []dto.CompletedPart) (string, error)
+	AbortMultipartUpload(ownerID, key, uploadID string) error
+	ListParts(ownerID, key, uploadID string) ([]dto.S3PartInfo, error)
+}
+
+// S3 multipart istekleri upload oturumlarına çevrilir: her part, part numarasıyla aynı index'li chunk olarak yazılır
+// ve CompleteMultipartUpload mevcut merge kuyruğunu (CompleteUpload) tetikler
+type s3GatewayService struct {
+	repo          repositories.FileUploadRepository
+	sessions      repositories.UploadSessionStore
+	uploadService UploadService
+	quota         QuotaService
+	cfg           config.UploadConfig
+}
+
+func NewS3GatewayService(repo repositories.FileUploadRepository, sessions repositories.UploadSessionStore, uploadService UploadService, quota QuotaService, cfg config.UploadConfig) S3GatewayService {
+	return &s3GatewayService{
+		repo:          repo,
+		sessions:      sessions,
+		uploadService: uploadService,
+		quota:         quota,
+		cfg:           cfg,
+	}
+}
+
+func (s *s3GatewayService) CreateMultipartUpload(ownerID, key, contentType string) (string, error) {
+	filename := path.Base(key)
+	if key == "" || filename == "." || filename == "/" {
+		return "", errors.ErrInvalidRequest(fmt.Errorf("object key zorunlu"))
+	}
+	if contentType == "" || contentType == "binary/octet-stream" || contentType == "application/octet-stream" {
+		contentType = helper.GetMimeTypeFromExtension(filename)
+	}
+
+	session := &entities.Upload{
+		ID:        uuid.New().String(),
+		OwnerID:   ownerID,
+		Filename:  filename,
+		MimeType:  contentType,
+		Status:    consts.StatusInProgress,
+		ExpiresAt: time.Now().Add(s.cfg.SessionTTL),
+	}
+	if err := s.sessions.CreateSession(session); err != nil {
+		return "", errors.ErrInternal(err)
+	}
+	return session.ID, nil
+}
+
+func (s *s3GatewayService) UploadPart(ownerID, key, uploadID string, partNumber int, length int64, body io.Reader) (string, error) {
+	if partNumber < 1 || partNumber > S3MaxPartNumber {
+		return "", errors.ErrInvalidRequest(fmt.Errorf("partNumber 1 ile %d arasında olmalı", S3MaxPartNumber))
+	}
+	// Bildirilen boyut gövde okunmadan kontrol edilir, bildirilmediyse sınır okuma sırasında uygulanır
+	if length > s.cfg.MaxChunkSize {
+		return "", errors.ErrUploadTooLarge(fmt.Errorf("part boyutu %d, izin verilen en fazla %d", length, s.cfg.MaxChunkSize))
+	}
+	session, err := s.activeSession(s.sessions, ownerID, key, uploadID)
+	if err != nil {
+		return "", err
+	}
+
+	// Part belleğe alınmadan staging'e stream edilir, ETag için MD5 yazma sırasında hesaplanır
+	md := md5.New()
+	stagingPath, sha, size, err := s.repo.StageChunk(uploadID, session.Filename, partNumber, io.TeeReader(io.LimitReader(body, s.cfg.MaxChunkSize+1), md))
+	if err != nil {
+		return "", errors.ErrTmpFile(err)
+	}
+	discard := func() {
+		if err := s.repo.DiscardStagedChunk(uploadID, stagingPath); err != nil {
+			log.Printf("Staging dosyası silinemedi %s: %v", stagingPath, err)
+		}
+	}
+	if size > s.cfg.MaxChunkSize {
+		discard()
+		return "", errors.ErrUploadTooLarge(fmt.Errorf("part boyutu izin verilen en fazla %d byte'ı aşıyor", s.cfg.MaxChunkSize))
+	}
+	etag := hex.EncodeToString(md.Sum(nil))
+
+	// Kilit yalnızca bu upload'ın satırını tutar; farklı upload'ların ve aynı upload'ın farklı part'ları paralel stream edilir
+	var delta int64
+	reserved := false
+	err = s.sessions.LockSession(uploadID, func(store repositories.UploadSessionStore) error {
+		if session, err = s.activeSession(store, ownerID, key, uploadID); err != nil {
+			return err
+		}
+
+		// Aynı part numarası tekrar gönderilirse S3'teki gibi önceki part'ın üzerine yazılır;
+		// kullanıma yalnızca eski part ile arasındaki fark yansır
+		chunks, err := store.GetChunks(uploadID)
+		if err != nil {
+			return errors.ErrInternal(err)
+		}
+		delta = size
+		for _, chunk := range chunks {
+			if chunk.ChunkIndex == partNumber {
+				delta -= chunk.Size
+			}
+		}
+		// Part'ların toplamı /upload/init ve tus ile aynı dosya boyutu sınırına tabidir
+		if total := chunksSize(chunks) + delta; total > s.cfg.MaxFileSize {
+			return errors.ErrUploadTooLarge(fmt.Errorf("part'ların toplamı %d byte, izin verilen en fazla %d", total, s.cfg.MaxFileSize))
+		}
+		if err := s.quota.Reserve(session.OwnerID, consts.UsageStaging, usageMediaType(session.Filename), delta); err != nil {
+			return err
+		}
+		reserved = true
+
+		if err := s.repo.DeleteChunk(uploadID, session.Filename, partNumber); err != nil {
+			return errors.ErrChunkNotSave(err)
+		}
+		if err := s.repo.CommitStagedChunk(uploadID, session.Filename, partNumber, stagingPath); err != nil {
+			return errors.ErrChunkNotSave(err)
+		}
+		if err := store.RecordChunk(&entities.UploadChunk{
+			UploadID:   uploadID,
+			ChunkIndex: partNumber,
+			Filename:   session.Filename,
+			Size:       size,
+			Hash:       sha,
+			ETag:       etag,
+		}); err != nil {
+			return errors.ErrInternal(err)
+		}
+		return nil
+	})
+	// Commit edilen staging dosyası zaten taşınmıştır, silme işlemi yalnızca reddedilen part'ı temizler
+	discard()
+	if err != nil {
+		// Kota ayrıldıktan sonra part kaydedilemediyse ayrılan byte geri bırakılır
+		if reserved {
+			s.quota.Track(session.OwnerID, consts.UsageStaging, usageMediaType(session.Filename), -delta)
+		}
+		return "", err
+	}
+	return quoteETag(etag), nil
+}
+
+func (s *s3GatewayService) CompleteMultipartUpload(ownerID, key, uploadID string, parts []dto.CompletedPart) (string, error) {
+	if len(parts) == 0 {
+		return "", errors.ErrInvalidPart(fmt.Errorf("en az bir part gönderilmeli"))
+	}
+
+	// Part listesi, aynı upload'a paralel gelen UploadPart'larla çakışmaması için oturum satırı kilitliyken doğrulanır
+	var session *entities.Upload
+	var digests []byte
+	var removed, totalSize int64
+	err := s.sessions.LockSession(uploadID, func(store repositories.UploadSessionStore) error {
+		var err error
+		if session, err = s.activeSession(store, ownerID, key, uploadID); err != nil {
+			return err
+		}
+		chunks, err := store.GetChunks(uploadID)
+		if err != nil {
+			return errors.ErrInternal(err)
+		}
+		stored := make(map[int]*entities.UploadChunk, len(chunks))
+		for _, chunk := range chunks {
+			stored[chunk.ChunkIndex] = chunk
+		}
+
+		// Merge akışı 1..N chunk'ları birleştirdiği için part numaraları 1'den başlayıp boşluksuz ilerlemeli
+		digests = make([]byte, 0, len(parts)*md5.Size)
+		for i, part := range parts {
+			if part.PartNumber != i+1 {
+				return errors.ErrInvalidPartOrder(fmt.Errorf("part %d, beklenen %d", part.PartNumber, i+1))
+			}
+			chunk, ok := stored[part.PartNumber]
+			if !ok || chunk.ETag != strings.Trim(part.ETag, `"`) {
+				return errors.ErrInvalidPart(fmt.Errorf("part %d bulunamadı veya ETag uyuşmuyor", part.PartNumber))
+			}
+			digest, err := hex.DecodeString(chunk.ETag)
+			if err != nil {
+				return errors.ErrInternal(err)
+			}
+			digests = append(digests, digest...)
+			totalSize += chunk.Size
+		}
+
+		// Listede olmayan fazla part'lar merge'e girmemesi için dosya ve kayıtlarıyla silinir;
+		// aksi halde status, ListParts ve staging kullanımı onları saymaya devam eder
+		unused := make([]int, 0)
+		for index, chunk := range stored {
+			if index > len(parts) {
+				if err := s.repo.DeleteChunk(uploadID, session.Filename, index); err != nil {
+					log.Printf("Kullanılmayan part silinemedi %s #%d: %v", uploadID, index, err)
+				}
+				unused = append(unused, index)
+				removed += chunk.Size
+			}
+		}
+		if err := store.DeleteChunks(uploadID, unused); err != nil {
+			return errors.ErrInternal(err)
+		}
+		if err := store.SetTotalChunks(uploadID, len(parts)); err != nil {
+			return errors.ErrInternal(err)
+		}
+		// Boyut oturuma yazılır; media olmayan dosyaların kullanımı SumRecorded'da oturumdan okunur
+		if err := store.SetTotalSize(uploadID, totalSize); err != nil {
+			return errors.ErrInternal(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if removed > 0 {
+		s.quota.Track(session.OwnerID, consts.UsageStaging, usageMediaType(session.Filename), -removed)
+	}
+
+	// Merge kuyruğu oturum satırını güncellediği için kilit bırakıldıktan sonra tetiklenir
+	if _, err := s.uploadService.CompleteUpload(&dto.CompleteUploadRequestDTO{
+		UploadID:    uploadID,
+		OwnerID:     ownerID,
+		TotalChunks: len(parts),
+		Filename:    session.Filename,
+	}); err != nil {
+		return "", err
+	}
+	log.Printf("S3 multipart upload tamamlandı, merge kuyruğuna alındı: %s (%d part)", uploadID, len(parts))
+
+	// S3 multipart ETag: md5(part md5'lerinin birleşimi) + "-" + part sayısı
+	sum := md5.Sum(digests)
+	return quoteETag(fmt.Sprintf("%s-%d", hex.EncodeToString(sum[:]), len(parts))), nil
+}
+
+func (s *s3GatewayService) AbortMultipartUpload(ownerID, key, uploadID string) error {
+	if _, err := s.session(s.sessions, ownerID, key, uploadID); err != nil {
+		return err
+	}
+	_, err := s.uploadService.CancelUpload(&dto.CancelUploadRequestDTO{UploadID: uploadID, OwnerID: ownerID})
+	return err
+}
+
+func (s *s3GatewayService) ListParts(ownerID, key, uploadID string) ([]dto.S3PartInfo, error) {
+	if _, err := s.session(s.sessions, ownerID, key, uploadID); err != nil {
+		return nil, err
+	}
+	chunks, err := s.sessions.GetChunks(uploadID)
+	if err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+
+	parts := make([]dto.S3PartInfo, 0, len(chunks))
+	for _, chunk := range chunks {
+		parts = append(parts, dto.S3PartInfo{
+			PartNumber:   chunk.ChunkIndex,
+			LastModified: chunk.CreatedAt.UTC(),
+			ETag:         quoteETag(chunk.ETag),
+			Size:         chunk.Size,
+		})
+	}
+	return parts, nil
+}
+
+// Upload ID başka bir key'e ya da access key'e aitse veya iptal edildiyse S3'teki gibi NoSuchUpload döner
+func (s *s3GatewayService) session(store repositories.UploadSessionStore, ownerID, key, uploadID string) (*entities.Upload, error) {
+	session, err := store.GetSession(ownerID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Filename != path.Base(key) || session.Status == consts.StatusCancelled {
+		return nil, errors.ErrNotFound(fmt.Errorf("multipart upload bulunamadı: %s", uploadID))
+	}
+	return session, nil
+}
+
+func (s *s3GatewayService) activeSession(store repositories.UploadSessionStore, ownerID, key, uploadID string) (*entities.Upload, error) {
+	session, err := s.session(store, ownerID, key, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if isExpired(session) {
+		return nil, errors.ErrUploadExpired(fmt.Errorf("oturum %s tarihinde sona erdi", session.ExpiresAt.Format(time.RFC3339)))
+	}
+	if session.Status != consts.StatusInProgress {
+		return nil, errors.ErrUploadNotActive(fmt.Errorf("upload durumu: %s", session.Status))
+	}
+	return session, nil
+}
+
+func quoteETag(etag string) string {
+	return `"` + etag + `"`
+}
@@ -0,0 +1,134 @@
+package usecases
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"file-uploader/internal/domain/dto"
+	"file-uploader/internal/domain/repositories"
+	"file-uploader/internal/infrastructure/auth"
+	"file-uploader/pkg/config"
+	consts "file-uploader/pkg/constants"
+	"file-uploader/pkg/errors"
+)
+
+const (
+	SignedURLUpload   = "upload"
+	SignedURLDownload = "download"
+
+	// İmzalanabilen endpoint'ler; path imzaya dahil olduğu için URL başka bir route'ta kullanılamaz
+	SignedUploadPath   = "/api/v1/upload/chunk"
+	SignedDownloadPath = "/api/v1/download/"
+)
+
+type SignedURLService interface {
+	// URL isteği yapan tenant adına imzalanır, tenant'a ait olmayan upload/media için not_found döner
+	CreateSignedURL(ownerID string, req *dto.SignedURLRequestDTO) (*dto.SignedURLResponse, error)
+	// Download route'u için media (image) ya da video dosyasını açar; variant content endpoint'lerindeki gibidir
+	OpenDownload(ownerID, id, variant string) (*dto.MediaContent, error)
+}
+
+type signedURLService struct {
+	signer       *auth.URLSigner
+	sessions     repositories.UploadSessionStore
+	mediaService MediaService
+	cfg          config.SignedURLConfig
+	upload       config.UploadConfig
+}
+
+func NewSignedURLService(signer *auth.URLSigner, sessions repositories.UploadSessionStore, mediaService MediaService, cfg config.SignedURLConfig, upload config.UploadConfig) SignedURLService {
+	return &signedURLService{
+		signer:       signer,
+		sessions:     sessions,
+		mediaService: mediaService,
+		cfg:          cfg,
+		upload:       upload,
+	}
+}
+
+func (s *signedURLService) CreateSignedURL(ownerID string, req *dto.SignedURLRequestDTO) (*dto.SignedURLResponse, error) {
+	if !s.signer.Enabled() {
+		return nil, errors.ErrInvalidRequest(fmt.Errorf("imzalı URL'ler kapalı (SIGNED_URL_KEYS)"))
+	}
+	ttl := s.cfg.DefaultTTL
+	if req.ExpiresIn > 0 {
+		ttl = time.Duration(req.ExpiresIn) * time.Second
+	}
+	if req.ExpiresIn < 0 || ttl > s.cfg.MaxTTL {
+		return nil, errors.ErrInvalidRequest(fmt.Errorf("expires_in 1 ile %d saniye arasında olmalı", int64(s.cfg.MaxTTL/time.Second)))
+	}
+	if req.MaxSize < 0 {
+		return nil, errors.ErrInvalidRequest(fmt.Errorf("max_size negatif olamaz"))
+	}
+
+	claims := &auth.SignedURLClaims{
+		OwnerID:   ownerID,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	switch req.Operation {
+	case SignedURLUpload:
+		if req.UploadID == "" {
+			return nil, errors.ErrInvalidRequest(fmt.Errorf("upload için upload_id zorunlu"))
+		}
+		session, err := s.sessions.GetSession(ownerID, req.UploadID)
+		if err != nil {
+			return nil, err
+		}
+		if session.Status != consts.StatusInProgress {
+			return nil, errors.ErrUploadNotActive(fmt.Errorf("upload durumu: %s", session.Status))
+		}
+		// Tek bir chunk'tan büyük gövdeye izin verilmez
+		limit := s.upload.MaxChunkSize
+		if session.ChunkSize > 0 {
+			limit = session.ChunkSize
+		}
+		if req.MaxSize == 0 || req.MaxSize > limit {
+			req.MaxSize = limit
+		}
+		claims.Method = http.MethodPost
+		claims.Path = SignedUploadPath
+		claims.UploadID = session.ID
+		claims.MaxSize = req.MaxSize
+		claims.ContentType = req.ContentType
+	case SignedURLDownload:
+		if req.MediaID == "" {
+			return nil, errors.ErrInvalidRequest(fmt.Errorf("download için media_id zorunlu"))
+		}
+		if !s.isImage(ownerID, req.MediaID) {
+			if _, err := s.mediaService.GetVideoByID(ownerID, req.MediaID); err != nil {
+				return nil, errors.ErrNotFound(fmt.Errorf("media ya da video bulunamadı: %s", req.MediaID))
+			}
+		}
+		claims.Method = http.MethodGet
+		claims.Path = SignedDownloadPath + req.MediaID
+		claims.Variant = req.Variant
+	default:
+		return nil, errors.ErrInvalidRequest(fmt.Errorf("operation %q desteklenmiyor (upload | download)", req.Operation))
+	}
+
+	query, err := s.signer.Sign(claims)
+	if err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+	return &dto.SignedURLResponse{
+		URL:       claims.Path + "?" + query.Encode(),
+		Method:    claims.Method,
+		ExpiresAt: claims.ExpiresAt.UTC(),
+		KeyID:     claims.KeyID,
+		MaxSize:   claims.MaxSize,
+	}, nil
+}
+
+// ID önce image, sonra video kayıtlarında aranır
+func (s *signedURLService) OpenDownload(ownerID, id, variant string) (*dto.MediaContent, error) {
+	if s.isImage(ownerID, id) {
+		return s.mediaService.GetMediaContent(ownerID, id, variant)
+	}
+	return s.mediaService.GetVideoContent(ownerID, id, variant)
+}
+
+func (s *signedURLService) isImage(ownerID, id string) bool {
+	_, err := s.mediaService.GetMediaByID(ownerID, id)
+	return err == nil
+}
@@ -0,0 +1,298 @@
+package usecases
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"file-uploader/internal/domain/entities"
+	"file-uploader/internal/domain/repositories"
+	consts "file-uploader/pkg/constants"
+	"file-uploader/pkg/errors"
+)
+
+type StorageMigrationOptions struct {
+	ID           string // aynı ID ile tekrar çalıştırılan taşıma kaldığı yerden devam eder
+	Source       string // kayıt ve kontrol için storage tanımı (ör. "local:/data/uploads", "s3:bucket")
+	Destination  string
+	SourceBase   string // local kaynakta eski mutlak yolları key'e çevirmek için UPLOAD_DIR
+	Concurrency  int
+	DryRun       bool // hiçbir dosya kopyalanmaz, DB'ye yazılmaz; yalnızca plan döner
+	RetryFailed  bool
+	DeleteSource bool // doğrulanıp file_path'i değiştirilen dosya kaynaktan silinir
+}
+
+// Dry-run'da taşınacak dosya, gerçek çalıştırmada başarısız olan dosya
+type StorageMigrationPlan struct {
+	SourcePath      string
+	DestinationPath string
+	Size            int64
+	Error           string
+}
+
+type StorageMigrationReport struct {
+	Total    int
+	Migrated int
+	Failed   int
+	Bytes    int64
+	Plan     []StorageMigrationPlan
+}
+
+// Dosyaları bir StorageStrategy'den diğerine kopyalar. Her dosya hedefte SHA-256 ile doğrulandıktan sonra
+// ona referans veren tüm kayıtların file_path'i tek transaction'da hedefteki konuma çevrilir;
+// yarıda kesilen taşımada doğrulanmamış hiçbir kayıt hedefe işaret etmez
+type StorageMigrator struct {
+	repo        repositories.StorageMigrationRepository
+	source      repositories.StorageStrategy
+	destination repositories.StorageStrategy
+	opts        StorageMigrationOptions
+}
+
+func NewStorageMigrator(repo repositories.StorageMigrationRepository, source, destination repositories.StorageStrategy, opts StorageMigrationOptions) *StorageMigrator {
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+	return &StorageMigrator{
+		repo:        repo,
+		source:      source,
+		destination: destination,
+		opts:        opts,
+	}
+}
+
+// İlk çalıştırmada referans verilen tüm dosyalar taşımaya eklenir; sonraki çalıştırmalar yalnızca
+// bekleyen (RetryFailed ile başarısız olan) dosyaları işler. ctx iptal edilirse yeni dosya başlatılmaz
+func (m *StorageMigrator) Run(ctx context.Context) (*StorageMigrationReport, error) {
+	migration, err := m.repo.GetMigration(m.opts.ID)
+	if err != nil {
+		if uploadErr, ok := err.(*errors.UploadError); !ok || uploadErr.Code != "not_found" {
+			return nil, err
+		}
+		migration = nil
+	}
+	if migration != nil && (migration.Source != m.opts.Source || migration.Destination != m.opts.Destination) {
+		return nil, fmt.Errorf("%s taşıması %s -> %s için oluşturulmuş, farklı bir ID kullanın", migration.ID, migration.Source, migration.Destination)
+	}
+
+	if m.opts.DryRun {
+		return m.plan(migration)
+	}
+
+	if migration == nil {
+		paths, err := m.repo.ListReferencedPaths()
+		if err != nil {
+			return nil, fmt.Errorf("dosya listesi alınamadı: %w", err)
+		}
+		if err := m.repo.CreateMigration(&entities.StorageMigration{
+			ID:          m.opts.ID,
+			Source:      m.opts.Source,
+			Destination: m.opts.Destination,
+			Status:      consts.MigrationStatusRunning,
+		}); err != nil {
+			return nil, fmt.Errorf("taşıma kaydı oluşturulamadı: %w", err)
+		}
+		if err := m.repo.AddItems(m.opts.ID, paths); err != nil {
+			return nil, fmt.Errorf("taşınacak dosyalar kaydedilemedi: %w", err)
+		}
+		log.Printf("INFO: %s taşıması oluşturuldu, %d dosya", m.opts.ID, len(paths))
+	}
+
+	items, err := m.repo.ListPendingItems(m.opts.ID, m.opts.RetryFailed)
+	if err != nil {
+		return nil, err
+	}
+	report := &StorageMigrationReport{Total: len(items)}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	work := make(chan entities.StorageMigrationItem)
+	for i := 0; i < m.opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range work {
+				err := m.migrateItem(&item)
+				mu.Lock()
+				if err != nil {
+					report.Failed++
+					report.Plan = append(report.Plan, StorageMigrationPlan{SourcePath: item.SourcePath, Error: err.Error()})
+				} else {
+					report.Migrated++
+					report.Bytes += item.Size
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, item := range items {
+		select {
+		case <-ctx.Done():
+			log.Printf("UYARI: taşıma durduruldu, kalan dosyalar bir sonraki çalıştırmada işlenecek")
+			break feed
+		case work <- item:
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	summary, err := m.repo.Summary(m.opts.ID)
+	if err != nil {
+		return report, err
+	}
+	if summary.Pending == 0 && summary.Failed == 0 {
+		if err := m.repo.UpdateMigrationStatus(m.opts.ID, consts.MigrationStatusCompleted); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// Kopyala, hedefte doğrula, file_path'i değiştir; hata item'a yazılır ve dosya sonraki çalıştırmada tekrar denenir
+func (m *StorageMigrator) migrateItem(item *entities.StorageMigrationItem) error {
+	start := time.Now()
+	err := m.copyAndVerify(item)
+	if err == nil {
+		err = m.repo.SwitchPath(item)
+	}
+	if err != nil {
+		log.Printf("UYARI: %s taşınamadı: %v", item.SourcePath, err)
+		if markErr := m.repo.MarkItemFailed(item.MigrationID, item.SourcePath, err.Error()); markErr != nil {
+			log.Printf("UYARI: taşıma durumu yazılamadı %s: %v", item.SourcePath, markErr)
+		}
+		return err
+	}
+
+	if m.opts.DeleteSource {
+		if err := m.source.DeleteFile(item.SourcePath); err != nil && !os.IsNotExist(err) {
+			log.Printf("UYARI: kaynak dosya silinemedi %s: %v", item.SourcePath, err)
+		}
+	}
+	log.Printf("INFO: %s -> %s (%d byte, %s)", item.SourcePath, item.DestinationPath, item.Size, time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+func (m *StorageMigrator) copyAndVerify(item *entities.StorageMigrationItem) error {
+	file, err := m.source.Download(item.SourcePath)
+	if err != nil {
+		return fmt.Errorf("kaynak dosya açılamadı: %w", err)
+	}
+	defer file.Close()
+
+	sourceHash, size, err := hashReader(file)
+	if err != nil {
+		return fmt.Errorf("kaynak dosya okunamadı: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("kaynak dosya başa alınamadı: %w", err)
+	}
+
+	location, err := m.destination.Save(file, storageObjectKey(item.SourcePath, m.opts.SourceBase))
+	if err != nil {
+		return fmt.Errorf("hedefe yazılamadı: %w", err)
+	}
+
+	// Hedefteki kopya tekrar okunarak doğrulanır; uyuşmazsa bozuk kopya silinir
+	localPath, cleanup, err := m.destination.LocalPath(location)
+	if err != nil {
+		return fmt.Errorf("hedefteki dosya okunamadı: %w", err)
+	}
+	defer cleanup()
+	copied, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("hedefteki dosya okunamadı: %w", err)
+	}
+	destinationHash, destinationSize, err := hashReader(copied)
+	copied.Close()
+	if err != nil {
+		return fmt.Errorf("hedefteki dosya okunamadı: %w", err)
+	}
+	if destinationHash != sourceHash || destinationSize != size {
+		if err := m.destination.DeleteFile(location); err != nil {
+			log.Printf("UYARI: doğrulanamayan kopya silinemedi %s: %v", location, err)
+		}
+		return fmt.Errorf("checksum uyuşmazlığı: kaynak %s (%d byte), hedef %s (%d byte)", sourceHash, size, destinationHash, destinationSize)
+	}
+
+	item.DestinationPath = location
+	item.SHA256 = sourceHash
+	item.Size = size
+	return nil
+}
+
+// Taşımanın yapacaklarını listeler; kaynakta bulunamayan dosyalar hata ile işaretlenir
+func (m *StorageMigrator) plan(migration *entities.StorageMigration) (*StorageMigrationReport, error) {
+	var paths []string
+	if migration == nil {
+		all, err := m.repo.ListReferencedPaths()
+		if err != nil {
+			return nil, fmt.Errorf("dosya listesi alınamadı: %w", err)
+		}
+		paths = all
+	} else {
+		items, err := m.repo.ListPendingItems(migration.ID, m.opts.RetryFailed)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			paths = append(paths, item.SourcePath)
+		}
+	}
+
+	report := &StorageMigrationReport{Total: len(paths)}
+	for _, sourcePath := range paths {
+		step := StorageMigrationPlan{
+			SourcePath:      sourcePath,
+			DestinationPath: storageObjectKey(sourcePath, m.opts.SourceBase),
+		}
+		size, err := m.source.Size(sourcePath)
+		if err != nil {
+			step.Error = err.Error()
+			report.Failed++
+		} else {
+			step.Size = size
+			report.Bytes += size
+		}
+		report.Plan = append(report.Plan, step)
+	}
+	return report, nil
+}
+
+func hashReader(r io.Reader) (string, int64, error) {
+	h := sha256.New()
+	size, err := io.Copy(h, r)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
+
+// Mantıksal key'ler olduğu gibi kalır. Key'lere geçmeden önce yazılmış yerel yollar
+// (mutlak, ./uploads/..., uploads/...) UPLOAD_DIR'e göre key'e çevrilir
+func storageObjectKey(location, sourceBase string) string {
+	if filepath.IsAbs(location) {
+		if sourceBase != "" {
+			if rel, err := filepath.Rel(sourceBase, location); err == nil && !strings.HasPrefix(rel, "..") {
+				return filepath.ToSlash(rel)
+			}
+		}
+		slashed := filepath.ToSlash(location)
+		if i := strings.LastIndex(slashed, "/uploads/"); i >= 0 {
+			return slashed[i+len("/uploads/"):]
+		}
+		return path.Join("other", path.Base(slashed))
+	}
+	key := strings.TrimPrefix(filepath.ToSlash(location), "./")
+	key = strings.TrimPrefix(key, "uploads/")
+	return strings.TrimPrefix(path.Clean("/"+key), "/")
+}
@@ -0,0 +1,392 @@
+package usecases
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"path/filepath"
+	"time"
+
+	"file-uploader/internal/domain/dto"
+	"file-uploader/internal/domain/entities"
+	"file-uploader/internal/domain/repositories"
+	"file-uploader/pkg/config"
+	consts "file-uploader/pkg/constants"
+	"file-uploader/pkg/errors"
+	"file-uploader/pkg/helper"
+
+	"github.com/google/uuid"
+)
+
+// tus 1.0 desteklenen checksum algoritmaları
+var TusChecksumAlgorithms = []string{"sha1", "sha256", "md5"}
+
+type TusService interface {
+	CreateUpload(ownerID, filename, mimeType string, length int64) (*dto.TusUploadInfo, error)
+	GetUpload(ownerID, uploadID string) (*dto.TusUploadInfo, error)
+	// length, Content-Length'tir (bilinmiyorsa -1); gövde staging'e stream edilir
+	AppendChunk(ownerID, uploadID string, offset, length int64, body io.Reader, checksumAlgorithm, checksum string) (*dto.TusUploadInfo, error)
+	TerminateUpload(ownerID, uploadID string) error
+	MaxSize() int64
+}
+
+// tus PATCH istekleri staging üzerinden sıradaki chunk olarak temp klasörüne yazılır, son PATCH'ten sonra mevcut merge akışı (CompleteUpload) tetiklenir
+type tusService struct {
+	repo          repositories.FileUploadRepository
+	sessions      repositories.UploadSessionStore
+	uploadService UploadService
+	quota         QuotaService
+	cfg           config.UploadConfig
+}
+
+func NewTusService(repo repositories.FileUploadRepository, sessions repositories.UploadSessionStore, uploadService UploadService, quota QuotaService, cfg config.UploadConfig) TusService {
+	return &tusService{
+		repo:          repo,
+		sessions:      sessions,
+		uploadService: uploadService,
+		quota:         quota,
+		cfg:           cfg,
+	}
+}
+
+func (s *tusService) MaxSize() int64 {
+	return s.cfg.MaxFileSize
+}
+
+func (s *tusService) CreateUpload(ownerID, filename, mimeType string, length int64) (*dto.TusUploadInfo, error) {
+	safeFilename := filepath.Base(filename)
+	if filename == "" || safeFilename == "." || safeFilename == string(filepath.Separator) {
+		return nil, errors.ErrInvalidRequest(fmt.Errorf("Upload-Metadata içinde filename zorunlu"))
+	}
+	if length <= 0 {
+		return nil, errors.ErrInvalidRequest(fmt.Errorf("Upload-Length 0'dan büyük olmalı"))
+	}
+	if length > s.cfg.MaxFileSize {
+		return nil, errors.ErrUploadTooLarge(fmt.Errorf("Upload-Length %d, izin verilen en fazla %d", length, s.cfg.MaxFileSize))
+	}
+	if mimeType == "" {
+		mimeType = helper.GetMimeTypeFromExtension(safeFilename)
+	}
+
+	session := &entities.Upload{
+		ID:        uuid.New().String(),
+		OwnerID:   ownerID,
+		Filename:  safeFilename,
+		TotalSize: length,
+		MimeType:  mimeType,
+		Status:    consts.StatusInProgress,
+		ExpiresAt: time.Now().Add(s.cfg.SessionTTL),
+	}
+	if err := s.sessions.CreateSession(session); err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+	return toTusUploadInfo(session, 0), nil
+}
+
+func (s *tusService) GetUpload(ownerID, uploadID string) (*dto.TusUploadInfo, error) {
+	session, err := s.sessions.GetSession(ownerID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status == consts.StatusCancelled {
+		return nil, errors.ErrNotFound(fmt.Errorf("upload sonlandırıldı: %s", uploadID))
+	}
+	if isExpired(session) {
+		return nil, errors.ErrUploadExpired(fmt.Errorf("oturum %s tarihinde sona erdi", session.ExpiresAt.Format(time.RFC3339)))
+	}
+
+	chunks, err := s.sessions.GetChunks(uploadID)
+	if err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+	return toTusUploadInfo(session, chunksSize(chunks)), nil
+}
+
+func (s *tusService) AppendChunk(ownerID, uploadID string, offset, length int64, body io.Reader, checksumAlgorithm, checksum string) (*dto.TusUploadInfo, error) {
+	// Gövde okunmadan önce oturum, offset, checksum algoritması ve Content-Length kontrol edilir
+	session, err := s.appendableSession(s.sessions, ownerID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	chunks, err := s.sessions.GetChunks(uploadID)
+	if err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+	current := chunksSize(chunks)
+	if offset != current {
+		return nil, errors.ErrOffsetMismatch(fmt.Errorf("Upload-Offset %d, sunucudaki offset %d", offset, current))
+	}
+	remaining := session.TotalSize - current
+	// Tüm byte'lar alınmış ama merge kuyruğa alınamamışsa boş PATCH hand-off'u yeniden dener
+	if remaining == 0 {
+		if length > 0 {
+			return nil, errors.ErrUploadTooLarge(fmt.Errorf("upload'ın tüm byte'ları alındı, PATCH gövdesi kabul edilmez"))
+		}
+		if err := s.complete(session, len(chunks)); err != nil {
+			return nil, err
+		}
+		session.Status = consts.StatusMerging
+		return toTusUploadInfo(session, current), nil
+	}
+	// Tek PATCH kalan byte'lardan büyük olamaz; daha büyük gövdeler okunmadan reddedilir
+	if length > remaining {
+		return nil, errors.ErrUploadTooLarge(fmt.Errorf("PATCH gövdesi %d byte, en fazla %d byte kabul edilir", length, remaining))
+	}
+	var checksumHash hash.Hash
+	if checksumAlgorithm != "" {
+		if checksumHash, err = tusChecksumHash(checksumAlgorithm); err != nil {
+			return nil, err
+		}
+		body = io.TeeReader(body, checksumHash)
+	}
+
+	// Gövde belleğe alınmadan MaxChunkSize'lık parçalar halinde staging'e stream edilir; Content-Length gönderilmediyse
+	// sınır okuma sırasında uygulanır. Checksum yoksa her parça okunduğu anda kaydedilir ve bağlantı koparsa o ana kadar
+	// alınan byte'lar korunur (tus 1.0); checksum varsa gövdenin tamamı doğrulanmadan hiçbir parça kaydedilmez
+	reader := &interruptibleReader{r: io.LimitReader(body, remaining+1)}
+	var staged, pending []*stagedChunk
+	// Commit edilen staging dosyaları zaten taşınmıştır, silme işlemi yalnızca kaydedilmeyen parçaları temizler
+	defer func() {
+		for _, part := range staged {
+			if err := s.repo.DiscardStagedChunk(uploadID, part.path); err != nil {
+				log.Printf("Staging dosyası silinemedi %s: %v", part.path, err)
+			}
+		}
+	}()
+
+	commit := func(part *stagedChunk) error {
+		var chunkIndex int
+		session, chunkIndex, err = s.commitChunk(ownerID, uploadID, current, part)
+		if err != nil {
+			return err
+		}
+		current += part.size
+		// Merge kuyruğu oturum satırını güncellediği için kilit bırakıldıktan sonra tetiklenir
+		if current == session.TotalSize {
+			if err := s.complete(session, chunkIndex); err != nil {
+				s.rollbackChunk(session, chunkIndex, part.size)
+				return err
+			}
+			session.Status = consts.StatusMerging
+		}
+		return nil
+	}
+
+	var received int64
+	for {
+		stagingPath, sha, size, err := s.repo.StageChunk(uploadID, session.Filename, len(chunks)+len(staged)+1, io.LimitReader(reader, s.cfg.MaxChunkSize))
+		if err != nil {
+			return nil, errors.ErrTmpFile(err)
+		}
+		part := &stagedChunk{path: stagingPath, sha: sha, size: size}
+		staged = append(staged, part)
+		received += size
+		if received > remaining {
+			return nil, errors.ErrUploadTooLarge(fmt.Errorf("PATCH gövdesi %d byte sınırını aşıyor", remaining))
+		}
+		if size > 0 {
+			if checksumHash == nil {
+				if err := commit(part); err != nil {
+					return nil, err
+				}
+			} else {
+				pending = append(pending, part)
+			}
+		}
+		if size < s.cfg.MaxChunkSize {
+			break
+		}
+	}
+	if reader.err != nil {
+		// Checksum'sız gövdenin okunan kısmı kaydedildi; client HEAD ile yeni offset'i alıp kalan byte'larla devam eder
+		return nil, errors.ErrTmpFile(fmt.Errorf("PATCH gövdesi %d byte'ta kesildi: %w", received, reader.err))
+	}
+	if checksumHash != nil {
+		if base64.StdEncoding.EncodeToString(checksumHash.Sum(nil)) != checksum {
+			return nil, errors.ErrChecksumMismatch(fmt.Errorf("%s checksum uyuşmuyor", checksumAlgorithm))
+		}
+		for _, part := range pending {
+			if err := commit(part); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return toTusUploadInfo(session, current), nil
+}
+
+type stagedChunk struct {
+	path string
+	sha  string
+	size int64
+}
+
+// Aynı upload'a gelen paralel PATCH'lerden yalnızca biri offset'i ilerletebilir; kilit yalnızca bu upload'ın satırını tutar
+func (s *tusService) commitChunk(ownerID, uploadID string, offset int64, part *stagedChunk) (*entities.Upload, int, error) {
+	var session *entities.Upload
+	var chunkIndex int
+	reserved := false
+	err := s.sessions.LockSession(uploadID, func(store repositories.UploadSessionStore) error {
+		var err error
+		if session, err = s.appendableSession(store, ownerID, uploadID); err != nil {
+			return err
+		}
+		chunks, err := store.GetChunks(uploadID)
+		if err != nil {
+			return errors.ErrInternal(err)
+		}
+		if current := chunksSize(chunks); offset != current {
+			return errors.ErrOffsetMismatch(fmt.Errorf("Upload-Offset %d, sunucudaki offset %d", offset, current))
+		}
+		if err := s.quota.Reserve(session.OwnerID, consts.UsageStaging, usageMediaType(session.Filename), part.size); err != nil {
+			return err
+		}
+		reserved = true
+
+		chunkIndex = len(chunks) + 1
+		// Önceki yarım kalmış denemeden kalan dosya varsa üzerine yazılabilmesi için silinir
+		if err := s.repo.DeleteChunk(uploadID, session.Filename, chunkIndex); err != nil {
+			return errors.ErrChunkNotSave(err)
+		}
+		if err := s.repo.CommitStagedChunk(uploadID, session.Filename, chunkIndex, part.path); err != nil {
+			return errors.ErrChunkNotSave(err)
+		}
+		if err := store.RecordChunk(&entities.UploadChunk{
+			UploadID:   uploadID,
+			ChunkIndex: chunkIndex,
+			Filename:   session.Filename,
+			Size:       part.size,
+			Hash:       part.sha,
+		}); err != nil {
+			return errors.ErrInternal(err)
+		}
+		return nil
+	})
+	if err != nil {
+		// Kota ayrıldıktan sonra chunk kaydedilemediyse ayrılan byte geri bırakılır
+		if reserved {
+			s.quota.Track(session.OwnerID, consts.UsageStaging, usageMediaType(session.Filename), -part.size)
+		}
+		return nil, 0, err
+	}
+	return session, chunkIndex, nil
+}
+
+// Merge kuyruğa alınamazsa son chunk geri alınır, offset upload uzunluğunun altına düşer ve client son parçayı yeniden
+// gönderir. Geri alma da başarısız olursa offset == length'te boş PATCH hand-off'u yeniden dener
+func (s *tusService) rollbackChunk(session *entities.Upload, chunkIndex int, size int64) {
+	err := s.sessions.LockSession(session.ID, func(store repositories.UploadSessionStore) error {
+		current, err := store.GetSession("", session.ID)
+		if err != nil {
+			return err
+		}
+		// Paralel bir istek merge'i kuyruğa almışsa chunk'a dokunulmaz
+		if current.Status != consts.StatusInProgress {
+			return fmt.Errorf("upload durumu: %s", current.Status)
+		}
+		if err := store.DeleteChunks(session.ID, []int{chunkIndex}); err != nil {
+			return err
+		}
+		return s.repo.DeleteChunk(session.ID, session.Filename, chunkIndex)
+	})
+	if err != nil {
+		log.Printf("UYARI: tus upload %s son chunk'ı geri alınamadı: %v", session.ID, err)
+		return
+	}
+	s.quota.Track(session.OwnerID, consts.UsageStaging, usageMediaType(session.Filename), -size)
+}
+
+// Bağlantı koparsa okuma hatası EOF'a çevrilir, böylece o ana kadar gelen byte'lar staging'de kalır
+type interruptibleReader struct {
+	r   io.Reader
+	err error
+}
+
+func (r *interruptibleReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, io.EOF
+	}
+	n, err := r.r.Read(p)
+	if err != nil && err != io.EOF {
+		r.err = err
+		return n, io.EOF
+	}
+	return n, err
+}
+
+// PATCH yalnızca tenant'a ait, aktif ve süresi dolmamış upload'lara kabul edilir
+func (s *tusService) appendableSession(store repositories.UploadSessionStore, ownerID, uploadID string) (*entities.Upload, error) {
+	session, err := store.GetSession(ownerID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if isExpired(session) {
+		return nil, errors.ErrUploadExpired(fmt.Errorf("oturum %s tarihinde sona erdi", session.ExpiresAt.Format(time.RFC3339)))
+	}
+	if session.Status != consts.StatusInProgress {
+		return nil, errors.ErrUploadNotActive(fmt.Errorf("upload durumu: %s", session.Status))
+	}
+	return session, nil
+}
+
+// Tüm byte'lar alındığında chunk'lar mevcut merge kuyruğuna gönderilir
+func (s *tusService) complete(session *entities.Upload, totalChunks int) error {
+	if err := s.sessions.SetTotalChunks(session.ID, totalChunks); err != nil {
+		return errors.ErrInternal(err)
+	}
+	if _, err := s.uploadService.CompleteUpload(&dto.CompleteUploadRequestDTO{
+		UploadID:    session.ID,
+		OwnerID:     session.OwnerID,
+		TotalChunks: totalChunks,
+		Filename:    session.Filename,
+	}); err != nil {
+		return err
+	}
+	log.Printf("tus upload tamamlandı, merge kuyruğuna alındı: %s (%d chunk)", session.ID, totalChunks)
+	return nil
+}
+
+func (s *tusService) TerminateUpload(ownerID, uploadID string) error {
+	if _, err := s.sessions.GetSession(ownerID, uploadID); err != nil {
+		return err
+	}
+	_, err := s.uploadService.CancelUpload(&dto.CancelUploadRequestDTO{UploadID: uploadID, OwnerID: ownerID})
+	return err
+}
+
+func tusChecksumHash(algorithm string) (hash.Hash, error) {
+	switch algorithm {
+	case "sha1":
+		return sha1.New(), nil
+	case "sha256":
+		return sha256.New(), nil
+	case "md5":
+		return md5.New(), nil
+	}
+	return nil, errors.ErrInvalidRequest(fmt.Errorf("desteklenmeyen checksum algoritması: %s", algorithm))
+}
+
+func chunksSize(chunks []*entities.UploadChunk) int64 {
+	var total int64
+	for _, chunk := range chunks {
+		total += chunk.Size
+	}
+	return total
+}
+
+func toTusUploadInfo(session *entities.Upload, offset int64) *dto.TusUploadInfo {
+	return &dto.TusUploadInfo{
+		UploadID:  session.ID,
+		Filename:  session.Filename,
+		MimeType:  session.MimeType,
+		Offset:    offset,
+		Length:    session.TotalSize,
+		Status:    session.Status,
+		ExpiresAt: session.ExpiresAt,
+	}
+}
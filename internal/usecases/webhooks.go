@@ -0,0 +1,335 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	mrand "math/rand"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"file-uploader/internal/domain/dto"
+	"file-uploader/internal/domain/entities"
+	"file-uploader/internal/domain/repositories"
+	"file-uploader/internal/infrastructure/webhook"
+	"file-uploader/pkg/config"
+	consts "file-uploader/pkg/constants"
+	"file-uploader/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// Abone olunabilecek olaylar; "*" hepsini kapsar
+var webhookEventTypes = map[string]bool{
+	entities.EventUploadCompleted: true,
+	entities.EventUploadFailed:    true,
+	entities.EventMediaProcessed:  true,
+	entities.EventVideoResized:    true,
+}
+
+type WebhookService interface {
+	CreateSubscription(req *dto.WebhookCreateRequestDTO) (*dto.WebhookResponse, error)
+	ListSubscriptions() (*dto.WebhookListResponse, error)
+	GetSubscription(id string) (*dto.WebhookResponse, error)
+	UpdateSubscription(id string, req *dto.WebhookUpdateRequestDTO) (*dto.WebhookResponse, error)
+	DeleteSubscription(id string) error
+	ListDeliveries(id string, req *dto.WebhookDeliveryListRequestDTO) (*dto.WebhookDeliveryListResponse, error)
+}
+
+type webhookService struct {
+	repo repositories.WebhookRepository
+}
+
+func NewWebhookService(repo repositories.WebhookRepository) WebhookService {
+	return &webhookService{
+		repo: repo,
+	}
+}
+
+func (s *webhookService) CreateSubscription(req *dto.WebhookCreateRequestDTO) (*dto.WebhookResponse, error) {
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+	events, err := normalizeWebhookEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		buf := make([]byte, 32)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, errors.ErrInternal(err)
+		}
+		secret = "whsec_" + hex.EncodeToString(buf)
+	}
+
+	sub := &entities.WebhookSubscription{
+		ID:          uuid.New().String(),
+		URL:         req.URL,
+		Secret:      secret,
+		Events:      events,
+		Description: req.Description,
+		Active:      true,
+	}
+	if err := s.repo.CreateSubscription(sub); err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+
+	resp := toWebhookResponse(sub)
+	resp.Secret = sub.Secret
+	return &resp, nil
+}
+
+func (s *webhookService) ListSubscriptions() (*dto.WebhookListResponse, error) {
+	subs, err := s.repo.ListSubscriptions()
+	if err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+	items := make([]dto.WebhookResponse, 0, len(subs))
+	for i := range subs {
+		items = append(items, toWebhookResponse(&subs[i]))
+	}
+	return &dto.WebhookListResponse{Items: items}, nil
+}
+
+func (s *webhookService) GetSubscription(id string) (*dto.WebhookResponse, error) {
+	sub, err := s.repo.GetSubscription(id)
+	if err != nil {
+		return nil, err
+	}
+	resp := toWebhookResponse(sub)
+	return &resp, nil
+}
+
+func (s *webhookService) UpdateSubscription(id string, req *dto.WebhookUpdateRequestDTO) (*dto.WebhookResponse, error) {
+	sub, err := s.repo.GetSubscription(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		if err := validateWebhookURL(*req.URL); err != nil {
+			return nil, err
+		}
+		sub.URL = *req.URL
+	}
+	if req.Events != nil {
+		events, err := normalizeWebhookEvents(req.Events)
+		if err != nil {
+			return nil, err
+		}
+		sub.Events = events
+	}
+	if req.Description != nil {
+		sub.Description = *req.Description
+	}
+	if req.Active != nil {
+		sub.Active = *req.Active
+	}
+
+	if err := s.repo.UpdateSubscription(sub); err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+	resp := toWebhookResponse(sub)
+	return &resp, nil
+}
+
+func (s *webhookService) DeleteSubscription(id string) error {
+	return s.repo.DeleteSubscription(id)
+}
+
+func (s *webhookService) ListDeliveries(id string, req *dto.WebhookDeliveryListRequestDTO) (*dto.WebhookDeliveryListResponse, error) {
+	if _, err := s.repo.GetSubscription(id); err != nil {
+		return nil, err
+	}
+	switch req.Status {
+	case "", consts.DeliveryStatusPending, consts.DeliveryStatusRetrying, consts.DeliveryStatusSucceeded, consts.DeliveryStatusFailed:
+	default:
+		return nil, errors.ErrInvalidRequest(fmt.Errorf("geçersiz status: %s", req.Status))
+	}
+	page, limit := normalizePage(req.Page, req.Limit)
+
+	deliveries, total, err := s.repo.ListDeliveries(id, req.Status, (page-1)*limit, limit)
+	if err != nil {
+		return nil, errors.ErrInternal(err)
+	}
+
+	items := make([]dto.WebhookDeliveryItem, 0, len(deliveries))
+	for _, d := range deliveries {
+		item := dto.WebhookDeliveryItem{
+			ID:             d.ID,
+			EventID:        d.EventID,
+			EventType:      d.EventType,
+			Status:         d.Status,
+			Attempts:       d.Attempts,
+			LastStatusCode: d.LastStatusCode,
+			LastError:      d.LastError,
+			CreatedAt:      d.CreatedAt,
+			DeliveredAt:    d.DeliveredAt,
+		}
+		if d.Status == consts.DeliveryStatusPending || d.Status == consts.DeliveryStatusRetrying {
+			next := d.NextAttemptAt
+			item.NextAttemptAt = &next
+		}
+		items = append(items, item)
+	}
+	return &dto.WebhookDeliveryListResponse{Items: items, Page: page, Limit: limit, Total: total}, nil
+}
+
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.ErrInvalidRequest(fmt.Errorf("geçersiz webhook url: %q", raw))
+	}
+	return nil
+}
+
+func normalizeWebhookEvents(events []string) (string, error) {
+	if len(events) == 0 {
+		return "", errors.ErrInvalidRequest(fmt.Errorf("en az bir olay gerekli"))
+	}
+	seen := make(map[string]bool, len(events))
+	for _, e := range events {
+		e = strings.TrimSpace(e)
+		if e == "*" {
+			return "*", nil
+		}
+		if !webhookEventTypes[e] {
+			return "", errors.ErrInvalidRequest(fmt.Errorf("bilinmeyen olay: %s", e))
+		}
+		seen[e] = true
+	}
+	list := make([]string, 0, len(seen))
+	for e := range seen {
+		list = append(list, e)
+	}
+	sort.Strings(list)
+	return strings.Join(list, ","), nil
+}
+
+func toWebhookResponse(sub *entities.WebhookSubscription) dto.WebhookResponse {
+	return dto.WebhookResponse{
+		ID:          sub.ID,
+		URL:         sub.URL,
+		Events:      strings.Split(sub.Events, ","),
+		Description: sub.Description,
+		Active:      sub.Active,
+		CreatedAt:   sub.CreatedAt,
+		UpdatedAt:   sub.UpdatedAt,
+	}
+}
+
+// Başarısız teslimatların bir sonraki deneme zamanı: 30s, 1m, 2m, ... en fazla 1 saat, ±%20 jitter
+func webhookBackoff(attempt int) time.Duration {
+	delay := 30 * time.Second
+	for i := 1; i < attempt && delay < time.Hour; i++ {
+		delay *= 2
+	}
+	if delay > time.Hour {
+		delay = time.Hour
+	}
+	jitter := time.Duration(mrand.Int63n(int64(delay)/5*2)) - delay/5
+	return delay + jitter
+}
+
+// Outbox'taki olayları abonelere dağıtır ve teslimatları gönderir; cmd/worker içinde
+// (in-process worker açıksa server içinde) çalışır
+type WebhookDispatcher struct {
+	outbox       repositories.OutboxRepository
+	webhooks     repositories.WebhookRepository
+	sender       *webhook.Sender
+	maxAttempts  int
+	timeout      time.Duration
+	pollInterval time.Duration
+}
+
+func NewWebhookDispatcher(outbox repositories.OutboxRepository, webhooks repositories.WebhookRepository, cfg config.WebhookConfig) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		outbox:       outbox,
+		webhooks:     webhooks,
+		sender:       webhook.NewSender(cfg.Timeout),
+		maxAttempts:  cfg.MaxAttempts,
+		timeout:      cfg.Timeout,
+		pollInterval: cfg.PollInterval,
+	}
+}
+
+// ctx iptal edilene kadar çalışır
+func (d *WebhookDispatcher) Start(ctx context.Context) {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.outbox.FanOut(100); err != nil {
+			log.Printf("Outbox olayları dağıtılamadı: %v", err)
+		}
+		d.deliverDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *WebhookDispatcher) deliverDue(ctx context.Context) {
+	// Lease, tüm partinin sırayla gönderilmesine yetecek kadar uzun tutulur
+	const batch = 20
+	deliveries, err := d.outbox.ClaimDeliveries(batch, d.timeout*batch+time.Minute)
+	if err != nil {
+		log.Printf("Webhook teslimatları alınamadı: %v", err)
+		return
+	}
+
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			return
+		}
+		d.deliver(ctx, delivery)
+	}
+}
+
+func (d *WebhookDispatcher) deliver(ctx context.Context, delivery entities.WebhookDelivery) {
+	attempt := delivery.Attempts + 1
+
+	sub, err := d.webhooks.GetSubscription(delivery.SubscriptionID)
+	if err != nil || !sub.Active {
+		// Abonelik silinmiş ya da pasif: teslimat kalıcı olarak düşer
+		if err := d.outbox.MarkAttemptFailed(delivery.ID, 0, "abonelik aktif değil", nil); err != nil {
+			log.Printf("Webhook teslimatı güncellenemedi (%d): %v", delivery.ID, err)
+		}
+		return
+	}
+
+	statusCode, sendErr := d.sender.Send(ctx, webhook.Request{
+		URL:        sub.URL,
+		Secret:     sub.Secret,
+		EventType:  delivery.EventType,
+		DeliveryID: delivery.ID,
+		Body:       []byte(delivery.Body),
+	})
+	if sendErr == nil {
+		if err := d.outbox.MarkDelivered(delivery.ID, statusCode); err != nil {
+			log.Printf("Webhook teslimatı güncellenemedi (%d): %v", delivery.ID, err)
+		}
+		return
+	}
+
+	var next *time.Time
+	if attempt < d.maxAttempts {
+		at := time.Now().Add(webhookBackoff(attempt))
+		next = &at
+		log.Printf("Webhook teslim edilemedi, tekrar denenecek (delivery=%d, deneme=%d): %v", delivery.ID, attempt, sendErr)
+	} else {
+		log.Printf("Webhook teslimatı kalıcı olarak başarısız (delivery=%d, deneme=%d): %v", delivery.ID, attempt, sendErr)
+	}
+	if err := d.outbox.MarkAttemptFailed(delivery.ID, statusCode, sendErr.Error(), next); err != nil {
+		log.Printf("Webhook teslimatı güncellenemedi (%d): %v", delivery.ID, err)
+	}
+}
@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE upload_chunks (
+    upload_id VARCHAR(255) NOT NULL REFERENCES upload_sessions(id) ON DELETE CASCADE,
+    chunk_index INTEGER NOT NULL,
+    filename VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL DEFAULT 0,
+    hash VARCHAR(64),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (upload_id, chunk_index)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS upload_chunks;
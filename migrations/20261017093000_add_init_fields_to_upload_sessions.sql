@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE upload_sessions
+    ADD COLUMN total_size BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN chunk_size BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN mime_type VARCHAR(100),
+    ADD COLUMN file_hash VARCHAR(64),
+    ADD COLUMN expires_at TIMESTAMP WITH TIME ZONE;
+
+-- +goose Down
+ALTER TABLE upload_sessions
+    DROP COLUMN IF EXISTS total_size,
+    DROP COLUMN IF EXISTS chunk_size,
+    DROP COLUMN IF EXISTS mime_type,
+    DROP COLUMN IF EXISTS file_hash,
+    DROP COLUMN IF EXISTS expires_at;
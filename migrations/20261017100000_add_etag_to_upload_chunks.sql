@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE upload_chunks
+    ADD COLUMN etag VARCHAR(32);
+
+-- +goose Down
+ALTER TABLE upload_chunks
+    DROP COLUMN IF EXISTS etag;
@@ -0,0 +1,21 @@
+-- +goose Up
+ALTER TABLE upload_sessions
+    ADD COLUMN file_md5 VARCHAR(32),
+    ADD COLUMN file_crc32c VARCHAR(8);
+
+ALTER TABLE images
+    ADD COLUMN sha256 VARCHAR(64);
+
+ALTER TABLE videos
+    ADD COLUMN sha256 VARCHAR(64);
+
+-- +goose Down
+ALTER TABLE videos
+    DROP COLUMN IF EXISTS sha256;
+
+ALTER TABLE images
+    DROP COLUMN IF EXISTS sha256;
+
+ALTER TABLE upload_sessions
+    DROP COLUMN IF EXISTS file_md5,
+    DROP COLUMN IF EXISTS file_crc32c;
@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE blobs (
+    sha256 VARCHAR(64) PRIMARY KEY,
+    file_path VARCHAR(500) NOT NULL,
+    size BIGINT NOT NULL DEFAULT 0,
+    ref_count INTEGER NOT NULL DEFAULT 0,
+    origin_id VARCHAR(36),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_images_sha256 ON images (sha256);
+CREATE INDEX idx_videos_sha256 ON videos (sha256);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_videos_sha256;
+DROP INDEX IF EXISTS idx_images_sha256;
+DROP TABLE IF EXISTS blobs;
@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE chunk_pool (
+    sha256 VARCHAR(64) PRIMARY KEY,
+    size BIGINT NOT NULL,
+    file_path VARCHAR(500) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    last_used_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_chunk_pool_last_used_at ON chunk_pool (last_used_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_chunk_pool_last_used_at;
+DROP TABLE IF EXISTS chunk_pool;
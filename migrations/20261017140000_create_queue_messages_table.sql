@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE queue_messages (
+    id BIGSERIAL PRIMARY KEY,
+    queue VARCHAR(100) NOT NULL,
+    body BYTEA NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    available_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    locked_by VARCHAR(255),
+    locked_until TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_queue_messages_available ON queue_messages (queue, available_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_queue_messages_available;
+DROP TABLE IF EXISTS queue_messages;
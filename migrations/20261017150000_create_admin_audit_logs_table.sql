@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE admin_audit_logs (
+    id SERIAL PRIMARY KEY,
+    action VARCHAR(50) NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    criteria TEXT,
+    target_ids TEXT,
+    affected INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_admin_audit_logs_action ON admin_audit_logs (action);
+CREATE INDEX idx_failed_jobs_type_status_created ON failed_jobs (job_type, job_status, created_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_failed_jobs_type_status_created;
+DROP INDEX IF EXISTS idx_admin_audit_logs_action;
+DROP TABLE IF EXISTS admin_audit_logs;
@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE jobs (
+    id VARCHAR(36) PRIMARY KEY,
+    upload_id VARCHAR(255) NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_jobs_upload_id ON jobs (upload_id, created_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_jobs_upload_id;
+DROP TABLE IF EXISTS jobs;
@@ -0,0 +1,39 @@
+-- +goose Up
+CREATE TABLE api_keys (
+    id VARCHAR(36) PRIMARY KEY,
+    owner_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255),
+    prefix VARCHAR(16) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    admin BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_api_keys_owner_id ON api_keys (owner_id);
+
+-- Mevcut kayıtlar owner_id'siz (boş) kalır, yalnızca admin / kapsamsız erişimle görülebilir
+ALTER TABLE upload_sessions ADD COLUMN owner_id VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE images ADD COLUMN owner_id VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE videos ADD COLUMN owner_id VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE media_variants ADD COLUMN owner_id VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX idx_upload_sessions_owner_id ON upload_sessions (owner_id);
+CREATE INDEX idx_images_owner_id ON images (owner_id);
+CREATE INDEX idx_videos_owner_id ON videos (owner_id);
+CREATE INDEX idx_media_variants_owner_id ON media_variants (owner_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_media_variants_owner_id;
+DROP INDEX IF EXISTS idx_videos_owner_id;
+DROP INDEX IF EXISTS idx_images_owner_id;
+DROP INDEX IF EXISTS idx_upload_sessions_owner_id;
+
+ALTER TABLE media_variants DROP COLUMN IF EXISTS owner_id;
+ALTER TABLE videos DROP COLUMN IF EXISTS owner_id;
+ALTER TABLE images DROP COLUMN IF EXISTS owner_id;
+ALTER TABLE upload_sessions DROP COLUMN IF EXISTS owner_id;
+
+DROP INDEX IF EXISTS idx_api_keys_owner_id;
+DROP TABLE IF EXISTS api_keys;
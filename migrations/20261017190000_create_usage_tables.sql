@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE storage_usage (
+    owner_id VARCHAR(255) NOT NULL,
+    category VARCHAR(20) NOT NULL,
+    media_type VARCHAR(20) NOT NULL,
+    bytes BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (owner_id, category, media_type)
+);
+
+CREATE TABLE tenant_quotas (
+    owner_id VARCHAR(255) PRIMARY KEY,
+    max_bytes BIGINT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS tenant_quotas;
+DROP TABLE IF EXISTS storage_usage;
@@ -0,0 +1,30 @@
+-- +goose Up
+CREATE TABLE storage_migrations (
+    id VARCHAR(100) PRIMARY KEY,
+    source VARCHAR(500) NOT NULL,
+    destination VARCHAR(500) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE storage_migration_items (
+    migration_id VARCHAR(100) NOT NULL REFERENCES storage_migrations (id) ON DELETE CASCADE,
+    source_path VARCHAR(500) NOT NULL,
+    destination_path VARCHAR(500),
+    sha256 VARCHAR(64),
+    size BIGINT NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (migration_id, source_path)
+);
+
+CREATE INDEX idx_storage_migration_items_status ON storage_migration_items (migration_id, status);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_storage_migration_items_status;
+DROP TABLE IF EXISTS storage_migration_items;
+DROP TABLE IF EXISTS storage_migrations;
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE encrypted_objects (
+    backend VARCHAR(200) NOT NULL,
+    location VARCHAR(500) NOT NULL,
+    key_id VARCHAR(100) NOT NULL,
+    wrapped_key BYTEA NOT NULL,
+    size BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (backend, location)
+);
+
+CREATE INDEX idx_encrypted_objects_key_id ON encrypted_objects (key_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_encrypted_objects_key_id;
+DROP TABLE IF EXISTS encrypted_objects;
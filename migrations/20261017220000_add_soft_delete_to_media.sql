@@ -0,0 +1,14 @@
+-- +goose Up
+ALTER TABLE videos ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE media_variants ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_videos_deleted_at ON videos (deleted_at);
+CREATE INDEX idx_media_variants_deleted_at ON media_variants (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_images_deleted_at ON images (deleted_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_images_deleted_at;
+DROP INDEX IF EXISTS idx_media_variants_deleted_at;
+DROP INDEX IF EXISTS idx_videos_deleted_at;
+ALTER TABLE media_variants DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE videos DROP COLUMN IF EXISTS deleted_at;
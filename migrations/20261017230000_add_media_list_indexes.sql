@@ -0,0 +1,25 @@
+-- +goose Up
+-- GET /media ve /video: tenant içinde sıralama alanı + id ile keyset sayfalama, çöp kutusundakiler hariç
+CREATE INDEX idx_images_owner_created ON images (owner_id, created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_images_owner_updated ON images (owner_id, updated_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_images_owner_name ON images (owner_id, original_name text_pattern_ops) WHERE deleted_at IS NULL;
+CREATE INDEX idx_images_owner_status ON images (owner_id, status, created_at) WHERE deleted_at IS NULL;
+CREATE INDEX idx_images_owner_file_type ON images (owner_id, file_type text_pattern_ops) WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_videos_owner_created ON videos (owner_id, created_at, video_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_videos_owner_updated ON videos (owner_id, updated_at, video_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_videos_owner_name ON videos (owner_id, original_name text_pattern_ops) WHERE deleted_at IS NULL;
+CREATE INDEX idx_videos_owner_status ON videos (owner_id, status, created_at) WHERE deleted_at IS NULL;
+CREATE INDEX idx_videos_owner_file_type ON videos (owner_id, file_type text_pattern_ops) WHERE deleted_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_videos_owner_file_type;
+DROP INDEX IF EXISTS idx_videos_owner_status;
+DROP INDEX IF EXISTS idx_videos_owner_name;
+DROP INDEX IF EXISTS idx_videos_owner_updated;
+DROP INDEX IF EXISTS idx_videos_owner_created;
+DROP INDEX IF EXISTS idx_images_owner_file_type;
+DROP INDEX IF EXISTS idx_images_owner_status;
+DROP INDEX IF EXISTS idx_images_owner_name;
+DROP INDEX IF EXISTS idx_images_owner_updated;
+DROP INDEX IF EXISTS idx_images_owner_created;
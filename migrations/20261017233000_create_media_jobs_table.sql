@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE media_jobs (
+    job_id VARCHAR(36) PRIMARY KEY,
+    media_id VARCHAR(36) NOT NULL,
+    media_type VARCHAR(10) NOT NULL,
+    owner_id VARCHAR(255),
+    type VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    params JSONB,
+    output_path TEXT,
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_media_jobs_media_id ON media_jobs (media_id, created_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_media_jobs_media_id;
+DROP TABLE IF EXISTS media_jobs;
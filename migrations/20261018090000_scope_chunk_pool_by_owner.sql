@@ -0,0 +1,11 @@
+-- +goose Up
+-- Havuzdaki chunk'lar yalnızca aynı tenant'ın upload'larına bağlanır; mevcut kayıtlar owner_id'siz (kapsamsız) kalır
+ALTER TABLE chunk_pool ADD COLUMN owner_id VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE chunk_pool DROP CONSTRAINT chunk_pool_pkey;
+ALTER TABLE chunk_pool ADD PRIMARY KEY (owner_id, sha256);
+
+-- +goose Down
+DELETE FROM chunk_pool WHERE owner_id <> '';
+ALTER TABLE chunk_pool DROP CONSTRAINT chunk_pool_pkey;
+ALTER TABLE chunk_pool ADD PRIMARY KEY (sha256);
+ALTER TABLE chunk_pool DROP COLUMN IF EXISTS owner_id;
@@ -5,12 +5,25 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Upload   UploadConfig
-	Database DatabaseConfig
+	Server     ServerConfig
+	Upload     UploadConfig
+	Database   DatabaseConfig
+	S3Gateway  S3GatewayConfig
+	Queue      QueueConfig
+	Events     EventsConfig
+	Webhook    WebhookConfig
+	Auth       AuthConfig
+	Quota      QuotaConfig
+	SignedURL  SignedURLConfig
+	Content    ContentConfig
+	Storage    StorageConfig
+	Encryption EncryptionConfig
+	Trash      TrashConfig
 }
 
 type ServerConfig struct {
@@ -19,10 +32,101 @@ type ServerConfig struct {
 }
 
 type UploadConfig struct {
-	TempDir     string
-	UploadsDir  string
-	MaxFileSize int64 // bytes
-	ChunkSize   int64 // bytes
+	TempDir      string
+	UploadsDir   string
+	MaxFileSize  int64         // bytes
+	ChunkSize    int64         // bytes
+	MaxChunkSize int64         // bytes, init isteğinde izin verilen en büyük chunk
+	SessionTTL   time.Duration // init ile açılan oturumun geçerlilik süresi
+	ChunkPoolDir string        // upload'lar arasında paylaşılan chunk havuzu
+	ChunkPoolTTL time.Duration // bu süre boyunca kullanılmayan havuz chunk'ları silinir
+}
+
+// S3 uyumlu multipart upload API'si için SigV4 anahtarları
+type S3GatewayConfig struct {
+	Region     string
+	Bucket     string
+	AccessKeys map[string]S3Credential // access key -> secret key ve tenant
+}
+
+// İstekler access key'in bağlı olduğu tenant adına yapılır (upload oturumları, kota, chunk havuzu)
+type S3Credential struct {
+	SecretKey string
+	OwnerID   string
+}
+
+type QueueConfig struct {
+	Backend           string        // redis | postgres | memory
+	VisibilityTimeout time.Duration // onaylanmayan iş bu süre sonunda başka bir worker'a verilir
+	Workers           int           // cmd/worker içinde paralel çalışan worker sayısı
+	InProcessWorkers  int           // server içinde çalışan worker sayısı (0: ayrı cmd/worker kullanılır)
+}
+
+type EventsConfig struct {
+	Backend string // redis | memory (varsayılan: kuyruk redis ise redis, değilse memory)
+}
+
+type WebhookConfig struct {
+	MaxAttempts  int           // bu denemeden sonra teslimat kalıcı olarak başarısız sayılır
+	Timeout      time.Duration // tek bir HTTP isteğinin zaman aşımı
+	PollInterval time.Duration // dispatcher'ın outbox ve teslimat tablolarını tarama aralığı
+}
+
+// API key ve JWT (HS256 paylaşılan secret ya da RS256 yerel JWKS dosyası) doğrulaması
+type AuthConfig struct {
+	Enabled     bool   // false ise tüm istekler kapsamsız kabul edilir (yalnızca geliştirme)
+	JWTSecret   string // HS256 imza anahtarı
+	JWKSFile    string // RS256 public key'lerini içeren JWKS dosyası
+	Issuer      string // boş değilse iss claim'i eşleşmeli
+	Audience    string // boş değilse aud claim'i bu değeri içermeli
+	TenantClaim string // tenant ID'sinin okunduğu claim, yoksa sub kullanılır
+}
+
+// Tenant başına depolama kotası; staging, orijinal ve varyant byte'larının toplamı sınırlanır
+type QuotaConfig struct {
+	DefaultBytes  int64  // tenant_quotas'ta kaydı olmayan tenant'ların kotası (0: sınırsız)
+	ReconcileCron string // sayaçların diskteki gerçek boyutlarla eşitlendiği cron ifadesi (saniyeli)
+}
+
+// Tarayıcıların kalıcı kimlik bilgisi taşımadan upload/download yapabilmesi için HMAC imzalı URL'ler.
+// Anahtar rotasyonu: yeni anahtar Keys'e eklenip ActiveKey yapılır, eski anahtar süresi dolan URL'ler için bir süre daha tutulur
+type SignedURLConfig struct {
+	Keys       map[string]string // key ID -> secret, hepsi doğrulamada kabul edilir
+	ActiveKey  string            // yeni URL'lerin imzalandığı key ID
+	DefaultTTL time.Duration     // expires_in verilmezse URL'nin geçerlilik süresi
+	MaxTTL     time.Duration     // istenebilecek en uzun geçerlilik süresi
+}
+
+// Media/video content endpoint'lerinin HTTP cache davranışı
+type ContentConfig struct {
+	CacheControl string // CDN arkasında ör. "public, max-age=31536000, immutable"
+}
+
+// Merge edilen dosyaların, varyantların ve videoların yazıldığı backend
+type StorageConfig struct {
+	Backend     string // local | s3
+	S3Bucket    string
+	S3Region    string
+	S3Endpoint  string // MinIO gibi S3 uyumlu servisler için (boş: AWS)
+	S3AccessKey string // boşsa AWS varsayılan kimlik zinciri kullanılır
+	S3SecretKey string
+	S3PathStyle bool // bucket host yerine path'te (MinIO için gerekli)
+}
+
+// Storage'a yazılan dosyaların AES-256-GCM ile şifrelenmesi. Her dosyanın kendi veri anahtarı vardır,
+// veri anahtarları master key ile sarılıp DB'de tutulur. Rotasyon: yeni master key eklenip ActiveKey yapılır,
+// storagectl rotate-keys çalıştırılır, eski anahtarla sarılmış kayıt kalmayınca eski anahtar kaldırılır
+type EncryptionConfig struct {
+	Enabled   bool              // false ise yeni dosyalar şifrelenmez, şifreli dosyalar anahtarlar verildiği sürece okunur
+	Keys      map[string]string // key ID -> base64 32 byte master key
+	KeyFile   string            // her satırı "key_id:base64" olan dosya, Keys ile birleştirilir
+	ActiveKey string            // yeni veri anahtarlarının sarıldığı key ID
+}
+
+// Silinen media/video kayıtları retention süresi boyunca çöp kutusunda kalır ve geri alınabilir
+type TrashConfig struct {
+	Retention time.Duration // bu süreden önce silinmiş kayıtlar ve dosyaları kalıcı silinir
+	PurgeCron string        // kalıcı silme job'unun cron ifadesi (saniyeli)
 }
 
 type DatabaseConfig struct {
@@ -40,10 +144,14 @@ func LoadConfig() *Config {
 			Host: getEnv("SERVER_HOST", "localhost"),
 		},
 		Upload: UploadConfig{
-			TempDir:     getEnv("UPLOAD_TEMP_DIR", "temp_uploads"),
-			UploadsDir:  getEnv("UPLOAD_DIR", "uploads"),
-			MaxFileSize: getEnvAsInt64("UPLOAD_MAX_FILE_SIZE", 5*1024*1024*1024), // 5GB
-			ChunkSize:   getEnvAsInt64("UPLOAD_CHUNK_SIZE", 10*1024*1024),        // 10MB
+			TempDir:      getEnv("UPLOAD_TEMP_DIR", "temp_uploads"),
+			UploadsDir:   getEnv("UPLOAD_DIR", "uploads"),
+			MaxFileSize:  getEnvAsInt64("UPLOAD_MAX_FILE_SIZE", 5*1024*1024*1024), // 5GB
+			ChunkSize:    getEnvAsInt64("UPLOAD_CHUNK_SIZE", 10*1024*1024),        // 10MB
+			MaxChunkSize: getEnvAsInt64("UPLOAD_MAX_CHUNK_SIZE", 100*1024*1024),   // 100MB
+			SessionTTL:   getEnvAsDuration("UPLOAD_SESSION_TTL", 24*time.Hour),
+			ChunkPoolDir: getEnv("UPLOAD_CHUNK_POOL_DIR", "chunk_pool"),
+			ChunkPoolTTL: getEnvAsDuration("UPLOAD_CHUNK_POOL_TTL", 7*24*time.Hour),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -52,7 +160,69 @@ func LoadConfig() *Config {
 			Password: getEnv("DB_PASSWORD", ""),
 			DBName:   getEnv("DB_NAME", "file_uploader"),
 		},
+		S3Gateway: S3GatewayConfig{
+			Region:     getEnv("S3_GATEWAY_REGION", "us-east-1"),
+			Bucket:     getEnv("S3_GATEWAY_BUCKET", "uploads"),
+			AccessKeys: getS3Credentials("S3_GATEWAY_KEYS"),
+		},
+		Queue: QueueConfig{
+			Backend:           strings.ToLower(getEnv("QUEUE_BACKEND", "redis")),
+			VisibilityTimeout: getEnvAsDuration("QUEUE_VISIBILITY_TIMEOUT", 5*time.Minute),
+			Workers:           int(getEnvAsInt64("QUEUE_WORKERS", 1)),
+			InProcessWorkers:  int(getEnvAsInt64("QUEUE_INPROCESS_WORKERS", 0)),
+		},
+		Webhook: WebhookConfig{
+			MaxAttempts:  int(getEnvAsInt64("WEBHOOK_MAX_ATTEMPTS", 8)),
+			Timeout:      getEnvAsDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+			PollInterval: getEnvAsDuration("WEBHOOK_POLL_INTERVAL", 2*time.Second),
+		},
+		Auth: AuthConfig{
+			Enabled:     getEnvAsBool("AUTH_ENABLED", true),
+			JWTSecret:   getEnv("AUTH_JWT_SECRET", ""),
+			JWKSFile:    getEnv("AUTH_JWKS_FILE", ""),
+			Issuer:      getEnv("AUTH_JWT_ISSUER", ""),
+			Audience:    getEnv("AUTH_JWT_AUDIENCE", ""),
+			TenantClaim: getEnv("AUTH_TENANT_CLAIM", "tenant_id"),
+		},
+		Quota: QuotaConfig{
+			DefaultBytes:  getEnvAsInt64("QUOTA_DEFAULT_BYTES", 0),
+			ReconcileCron: getEnv("QUOTA_RECONCILE_CRON", "0 15 * * * *"), // her saat 15. dakikada
+		},
+		SignedURL: SignedURLConfig{
+			Keys:       getEnvAsMap("SIGNED_URL_KEYS"),
+			ActiveKey:  getEnv("SIGNED_URL_ACTIVE_KEY", ""),
+			DefaultTTL: getEnvAsDuration("SIGNED_URL_DEFAULT_TTL", 15*time.Minute),
+			MaxTTL:     getEnvAsDuration("SIGNED_URL_MAX_TTL", 24*time.Hour),
+		},
+		Content: ContentConfig{
+			CacheControl: getEnv("CONTENT_CACHE_CONTROL", "private, max-age=86400"),
+		},
+		Storage: StorageConfig{
+			Backend:     strings.ToLower(getEnv("STORAGE_BACKEND", "local")),
+			S3Bucket:    getEnv("STORAGE_S3_BUCKET", ""),
+			S3Region:    getEnv("STORAGE_S3_REGION", "us-east-1"),
+			S3Endpoint:  getEnv("STORAGE_S3_ENDPOINT", ""),
+			S3AccessKey: getEnv("STORAGE_S3_ACCESS_KEY", ""),
+			S3SecretKey: getEnv("STORAGE_S3_SECRET_KEY", ""),
+			S3PathStyle: getEnvAsBool("STORAGE_S3_PATH_STYLE", false),
+		},
+		Encryption: EncryptionConfig{
+			Enabled:   getEnvAsBool("STORAGE_ENCRYPTION_ENABLED", false),
+			Keys:      getEnvAsMap("STORAGE_ENCRYPTION_KEYS"),
+			KeyFile:   getEnv("STORAGE_ENCRYPTION_KEYFILE", ""),
+			ActiveKey: getEnv("STORAGE_ENCRYPTION_ACTIVE_KEY", ""),
+		},
+		Trash: TrashConfig{
+			Retention: getEnvAsDuration("TRASH_RETENTION", 30*24*time.Hour),
+			PurgeCron: getEnv("TRASH_PURGE_CRON", "0 30 3 * * *"), // her gün 03:30
+		},
+	}
+
+	defaultEventsBackend := "memory"
+	if config.Queue.Backend == "redis" {
+		defaultEventsBackend = "redis"
 	}
+	config.Events.Backend = strings.ToLower(getEnv("EVENTS_BACKEND", defaultEventsBackend))
 
 	// Proje kökü:
 	projectRoot, err := findProjectRoot()
@@ -63,6 +233,7 @@ func LoadConfig() *Config {
 	// Klasörleri proje köküne göre oluşturmak için:
 	config.Upload.TempDir = filepath.Join(projectRoot, "cmd", "server", config.Upload.TempDir)
 	config.Upload.UploadsDir = filepath.Join(projectRoot, "cmd", "server", config.Upload.UploadsDir)
+	config.Upload.ChunkPoolDir = filepath.Join(projectRoot, "cmd", "server", config.Upload.ChunkPoolDir)
 
 	if err := os.MkdirAll(config.Upload.TempDir, 0755); err != nil {
 		panic(err)
@@ -70,6 +241,9 @@ func LoadConfig() *Config {
 	if err := os.MkdirAll(config.Upload.UploadsDir, 0755); err != nil {
 		panic(err)
 	}
+	if err := os.MkdirAll(config.Upload.ChunkPoolDir, 0755); err != nil {
+		panic(err)
+	}
 
 	return config
 }
@@ -110,18 +284,50 @@ func getEnvAsInt64(key string, defaultValue int64) int64 {
 	return defaultValue
 }
 
-func EnsureDirs() {
-	dirs := []string{
-		"./uploads/media/original",
-		"./uploads/media/variants",
-		"./uploads/other",
-		"./uploads/videos/original",
-		"./uploads/videos/resized",
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
 	}
+	return defaultValue
+}
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// "key1:value1,key2:value2" formatındaki env değerini map'e çevirir
+// access_key:secret_key:tenant_id üçlüleri virgülle ayrılır; tenant'ı olmayan anahtarlar kullanılmaz
+func getS3Credentials(key string) map[string]S3Credential {
+	result := make(map[string]S3Credential)
+	for _, entry := range strings.Split(os.Getenv(key), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			log.Printf("UYARI: %s içindeki %q girdisi access_key:secret_key:tenant_id biçiminde değil, yok sayıldı", key, parts[0])
+			continue
+		}
+		result[parts[0]] = S3Credential{SecretKey: parts[1], OwnerID: parts[2]}
+	}
+	return result
+}
 
-	for _, dir := range dirs {
-		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
-			log.Fatalf("klasörler oluşturulamadı %s: %v", dir, err)
+func getEnvAsMap(key string) map[string]string {
+	result := make(map[string]string)
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if ok && k != "" {
+			result[k] = v
 		}
 	}
+	return result
 }
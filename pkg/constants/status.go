@@ -7,10 +7,65 @@ const (
 	StatusProcessed    = "processed"
 	StatusUploaded     = "uploaded"
 	StatusInProgress   = "in_progress"
+	StatusMerging      = "merging"
+	StatusRetrying     = "retrying" // merge eksik chunk'lar için otomatik retry bekliyor, chunk kabul edilir
 	StatusOK           = "ok"
 	StatusCancelled    = "cancelled"
+	StatusExpired      = "expired"
 	StatusQueued       = "queued"
 	StatusPending      = "pending"
 	VideoStatusResized = "resized"
 	MaxRetryJobs       = 3
 )
+
+// Kuyruk işlerinin (jobs tablosu) durumları
+const (
+	JobStatusQueued    = "queued"
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+	JobStatusRetrying  = "retrying"
+)
+
+// Webhook teslimat durumları
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusRetrying  = "retrying"
+	DeliveryStatusSucceeded = "succeeded"
+	DeliveryStatusFailed    = "failed"
+)
+
+// Depolama kullanımı kategorileri (storage_usage.category)
+const (
+	UsageStaging  = "staging"  // merge bekleyen chunk'lar
+	UsageOriginal = "original" // birleştirilmiş dosyalar
+	UsageVariant  = "variant"  // resize edilmiş image/video çıktıları
+)
+
+// Kullanım kırılımında kullanılan medya tipleri
+const (
+	MediaTypeImage = "image"
+	MediaTypeVideo = "video"
+	MediaTypeOther = "other"
+)
+
+// Content endpoint'lerinde ?variant= ile seçilen dosya
+const (
+	ContentVariantOriginal = "original" // varsayılan
+	ContentVariantResized  = "resized"  // yalnızca video, boyutlandırılmış çıktı
+)
+
+// Storage taşıma (storagectl migrate) durumları
+const (
+	MigrationStatusRunning   = "running"
+	MigrationStatusCompleted = "completed"
+	MigrationItemPending     = "pending"
+	MigrationItemDone        = "done"
+	MigrationItemFailed      = "failed"
+)
+
+// media_jobs iş tipleri; durumlar StatusProcessing, StatusCompleted ve StatusFailed'dır
+const (
+	MediaJobVariant = "variant" // image varyantı (media_sizes'taki her boyut için bir iş)
+	MediaJobResize  = "resize"  // video boyutlandırma (ffmpeg ile yeniden kodlama)
+)
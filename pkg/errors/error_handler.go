@@ -22,8 +22,18 @@ func HandleError(c *fiber.Ctx, err error) error {
 		switch ue.Code {
 		case "not_found":
 			status = fiber.StatusNotFound
-		case "chunk_not_open", "invalid_chunk":
+		case "chunk_not_open", "invalid_chunk", "invalid_request", "chunk_size_mismatch", "filename_mismatch", "invalid_part", "invalid_part_order", "checksum_mismatch":
 			status = fiber.StatusBadRequest
+		case "upload_not_active", "offset_mismatch":
+			status = fiber.StatusConflict
+		case "upload_too_large", "quota_exceeded":
+			status = fiber.StatusRequestEntityTooLarge
+		case "upload_expired":
+			status = fiber.StatusGone
+		case "unauthorized":
+			status = fiber.StatusUnauthorized
+		case "forbidden":
+			status = fiber.StatusForbidden
 		default:
 			status = fiber.StatusInternalServerError
 		}
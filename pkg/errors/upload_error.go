@@ -46,6 +46,45 @@ var (
 	ErrChunksNotMerged = func(err error) *UploadError {
 		return &UploadError{Code: "chunks_not_merged", Message: "Chunklar birleştirilemedi", Err: err}
 	}
+	ErrUploadNotActive = func(err error) *UploadError {
+		return &UploadError{Code: "upload_not_active", Message: "Upload oturumu aktif değil", Err: err}
+	}
+	ErrInvalidRequest = func(err error) *UploadError {
+		return &UploadError{Code: "invalid_request", Message: "Geçersiz istek", Err: err}
+	}
+	ErrUploadExpired = func(err error) *UploadError {
+		return &UploadError{Code: "upload_expired", Message: "Upload oturumunun süresi doldu", Err: err}
+	}
+	ErrChunkSizeMismatch = func(err error) *UploadError {
+		return &UploadError{Code: "chunk_size_mismatch", Message: "Chunk boyutu oturumla uyuşmuyor", Err: err}
+	}
+	ErrFilenameMismatch = func(err error) *UploadError {
+		return &UploadError{Code: "filename_mismatch", Message: "Dosya adı oturumla uyuşmuyor", Err: err}
+	}
+	ErrOffsetMismatch = func(err error) *UploadError {
+		return &UploadError{Code: "offset_mismatch", Message: "Upload offset uyuşmuyor", Err: err}
+	}
+	ErrChecksumMismatch = func(err error) *UploadError {
+		return &UploadError{Code: "checksum_mismatch", Message: "Checksum doğrulaması başarısız", Err: err}
+	}
+	ErrUploadTooLarge = func(err error) *UploadError {
+		return &UploadError{Code: "upload_too_large", Message: "Upload boyutu sınırı aşıldı", Err: err}
+	}
+	ErrInvalidPart = func(err error) *UploadError {
+		return &UploadError{Code: "invalid_part", Message: "Part bulunamadı veya ETag uyuşmuyor", Err: err}
+	}
+	ErrInvalidPartOrder = func(err error) *UploadError {
+		return &UploadError{Code: "invalid_part_order", Message: "Part listesi sıralı değil", Err: err}
+	}
+	ErrUnauthorized = func(err error) *UploadError {
+		return &UploadError{Code: "unauthorized", Message: "Kimlik doğrulaması gerekli", Err: err}
+	}
+	ErrForbidden = func(err error) *UploadError {
+		return &UploadError{Code: "forbidden", Message: "Bu işlem için yetkiniz yok", Err: err}
+	}
+	ErrQuotaExceeded = func(err error) *UploadError {
+		return &UploadError{Code: "quota_exceeded", Message: "Depolama kotası aşıldı", Err: err}
+	}
 	ErrMissingChunk = func(err error) *UploadError {
 		return &UploadError{
 			Code:    "missing_chunk",
@@ -0,0 +1,63 @@
+package file
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"strings"
+)
+
+// Dosyanın hex formatındaki özetleri, boş alanlar doğrulamada atlanır
+type Digest struct {
+	SHA256 string `json:"sha256,omitempty"`
+	MD5    string `json:"md5,omitempty"`
+	CRC32C string `json:"crc32c,omitempty"`
+}
+
+// Beklenen özetler (boş olmayanlar) hesaplananlarla karşılaştırılır
+func (d Digest) Verify(actual Digest) error {
+	checks := []struct{ name, expected, actual string }{
+		{"sha256", d.SHA256, actual.SHA256},
+		{"md5", d.MD5, actual.MD5},
+		{"crc32c", d.CRC32C, actual.CRC32C},
+	}
+	for _, c := range checks {
+		if c.expected != "" && !strings.EqualFold(c.expected, c.actual) {
+			return fmt.Errorf("%s uyuşmuyor: beklenen %s, hesaplanan %s", c.name, c.expected, c.actual)
+		}
+	}
+	return nil
+}
+
+// Merge sırasında yazılan byte'lardan SHA-256, MD5 ve CRC32C'yi tek geçişte hesaplar
+type DigestWriter struct {
+	sha256 hash.Hash
+	md5    hash.Hash
+	crc32c hash.Hash32
+}
+
+func NewDigestWriter() *DigestWriter {
+	return &DigestWriter{
+		sha256: sha256.New(),
+		md5:    md5.New(),
+		crc32c: crc32.New(crc32.MakeTable(crc32.Castagnoli)),
+	}
+}
+
+func (w *DigestWriter) Write(p []byte) (int, error) {
+	w.sha256.Write(p)
+	w.md5.Write(p)
+	w.crc32c.Write(p)
+	return len(p), nil
+}
+
+func (w *DigestWriter) Digest() Digest {
+	return Digest{
+		SHA256: hex.EncodeToString(w.sha256.Sum(nil)),
+		MD5:    hex.EncodeToString(w.md5.Sum(nil)),
+		CRC32C: hex.EncodeToString(w.crc32c.Sum(nil)),
+	}
+}
@@ -0,0 +1,30 @@
+package file
+
+import (
+	"fmt"
+	"path"
+)
+
+// Storage'daki mantıksal object key'leri. Tüm backend'ler aynı düzeni kullanır;
+// local storage key'i UPLOAD_DIR altına, S3 bucket içine yazar
+
+// Orijinal dosya türüne göre media/original, videos/original ya da other altına yazılır
+func OriginalKey(filename string) string {
+	switch {
+	case IsImageFile(filename):
+		return path.Join("media/original", filename)
+	case IsVideoFile(filename):
+		return path.Join("videos/original", filename)
+	default:
+		return path.Join("other", filename)
+	}
+}
+
+// Varyantlar media ID klasöründe tutulur, media silinince klasör birlikte kalkar
+func VariantKey(mediaID, filename string) string {
+	return path.Join("media/variants", mediaID, filename)
+}
+
+func ResizedVideoKey(videoID string, width, height int64, ext string) string {
+	return path.Join("videos/resized", fmt.Sprintf("%s_%dx%d%s", videoID, width, height, ext))
+}
@@ -0,0 +1,270 @@
+package sigv4
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	Algorithm        = "AWS4-HMAC-SHA256"
+	TimeFormat       = "20060102T150405Z"
+	UnsignedPayload  = "UNSIGNED-PAYLOAD"
+	StreamingSigned  = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD"
+	StreamingTrailer = "STREAMING-UNSIGNED-PAYLOAD-TRAILER"
+	emptySHA256      = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// Authorization: AWS4-HMAC-SHA256 Credential=AKID/20250101/us-east-1/s3/aws4_request, SignedHeaders=host;x-amz-date, Signature=...
+type Authorization struct {
+	AccessKey     string
+	Date          string // YYYYMMDD
+	Region        string
+	Service       string
+	SignedHeaders []string
+	Signature     string
+}
+
+func (a *Authorization) Scope() string {
+	return strings.Join([]string{a.Date, a.Region, a.Service, "aws4_request"}, "/")
+}
+
+func ParseAuthorization(header string) (*Authorization, error) {
+	if !strings.HasPrefix(header, Algorithm+" ") {
+		return nil, fmt.Errorf("desteklenmeyen imza algoritması")
+	}
+
+	auth := &Authorization{}
+	for _, field := range strings.Split(strings.TrimPrefix(header, Algorithm+" "), ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(field), "=")
+		if !ok {
+			return nil, fmt.Errorf("authorization alanı geçersiz: %s", field)
+		}
+		switch key {
+		case "Credential":
+			parts := strings.Split(value, "/")
+			if len(parts) != 5 || parts[4] != "aws4_request" {
+				return nil, fmt.Errorf("credential scope geçersiz")
+			}
+			auth.AccessKey, auth.Date, auth.Region, auth.Service = parts[0], parts[1], parts[2], parts[3]
+		case "SignedHeaders":
+			auth.SignedHeaders = strings.Split(value, ";")
+		case "Signature":
+			auth.Signature = value
+		}
+	}
+
+	if auth.AccessKey == "" || len(auth.SignedHeaders) == 0 || auth.Signature == "" {
+		return nil, fmt.Errorf("authorization header eksik")
+	}
+	return auth, nil
+}
+
+// S3 için path segmentleri istemcinin gönderdiği (bir kez encode edilmiş) haliyle kullanılır
+func CanonicalRequest(method, rawPath, rawQuery string, header func(string) string, signedHeaders []string, payloadHash string) string {
+	var headers strings.Builder
+	for _, name := range signedHeaders {
+		headers.WriteString(name)
+		headers.WriteByte(':')
+		headers.WriteString(strings.Join(strings.Fields(header(name)), " "))
+		headers.WriteByte('\n')
+	}
+
+	if rawPath == "" {
+		rawPath = "/"
+	}
+
+	return strings.Join([]string{
+		method,
+		rawPath,
+		CanonicalQuery(rawQuery),
+		headers.String(),
+		strings.Join(signedHeaders, ";"),
+		payloadHash,
+	}, "\n")
+}
+
+func CanonicalQuery(rawQuery string) string {
+	values, _ := url.ParseQuery(rawQuery)
+	pairs := make([]string, 0, len(values))
+	for key, vals := range values {
+		for _, value := range vals {
+			pairs = append(pairs, Escape(key)+"="+Escape(value))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// RFC 3986 unreserved karakterler dışında her şey %XX olarak encode edilir
+func Escape(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func StringToSign(amzDate, scope, canonicalRequest string) string {
+	return strings.Join([]string{Algorithm, amzDate, scope, HashHex([]byte(canonicalRequest))}, "\n")
+}
+
+func SigningKey(secret, date, region, service string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secret), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	return hmacSHA256(key, "aws4_request")
+}
+
+func Sign(signingKey []byte, stringToSign string) string {
+	return hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+}
+
+var (
+	ErrSignatureMismatch   = errors.New("chunk imzası doğrulanamadı")
+	ErrPayloadHashMismatch = errors.New("gövde hash'i x-amz-content-sha256 ile uyuşmuyor")
+	ErrMalformedChunk      = errors.New("aws-chunked gövde geçersiz")
+)
+
+// aws-chunked gövdeyi belleğe almadan, okundukça çözer. İmzalı chunk'lar için her chunk'ın SHA-256'sı
+// verify ile doğrulanır; doğrulanamayan chunk'tan sonra okuma hata döner
+func NewChunkedReader(r io.Reader, verify func(chunkHash, signature string) error) io.Reader {
+	return &chunkedReader{r: bufio.NewReader(r), verify: verify, hash: sha256.New()}
+}
+
+type chunkedReader struct {
+	r         *bufio.Reader
+	verify    func(chunkHash, signature string) error
+	hash      hash.Hash
+	signature string
+	remaining int64
+	err       error
+}
+
+func (c *chunkedReader) Read(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	if c.remaining == 0 {
+		if c.err = c.readHeader(); c.err != nil {
+			return 0, c.err
+		}
+	}
+
+	if int64(len(p)) > c.remaining {
+		p = p[:c.remaining]
+	}
+	n, err := c.r.Read(p)
+	c.hash.Write(p[:n])
+	c.remaining -= int64(n)
+	switch {
+	case c.remaining == 0:
+		c.err = c.finishChunk()
+	case err == io.EOF:
+		c.err = fmt.Errorf("%w: chunk beklenenden kısa", ErrMalformedChunk)
+	default:
+		c.err = err
+	}
+	return n, c.err
+}
+
+// Chunk başlığı: <boyut hex>[;chunk-signature=<imza>]\r\n
+func (c *chunkedReader) readHeader() error {
+	line, err := c.r.ReadSlice('\n')
+	if err != nil || !bytes.HasSuffix(line, []byte("\r\n")) {
+		return fmt.Errorf("%w: chunk başlığı okunamadı", ErrMalformedChunk)
+	}
+	sizeHex, extension, _ := strings.Cut(string(line[:len(line)-2]), ";")
+	size, err := strconv.ParseInt(strings.TrimSpace(sizeHex), 16, 64)
+	if err != nil || size < 0 {
+		return fmt.Errorf("%w: chunk boyutu geçersiz", ErrMalformedChunk)
+	}
+
+	c.signature = strings.TrimPrefix(extension, "chunk-signature=")
+	c.hash.Reset()
+	if size == 0 {
+		// Son chunk'tan sonra gelen trailer header'lar (checksum vb.) yok sayılır
+		if err := c.verifyChunk(); err != nil {
+			return err
+		}
+		return io.EOF
+	}
+	c.remaining = size
+	return nil
+}
+
+func (c *chunkedReader) finishChunk() error {
+	if err := c.verifyChunk(); err != nil {
+		return err
+	}
+	var crlf [2]byte
+	if _, err := io.ReadFull(c.r, crlf[:]); err != nil || string(crlf[:]) != "\r\n" {
+		return fmt.Errorf("%w: chunk sonu geçersiz", ErrMalformedChunk)
+	}
+	return nil
+}
+
+func (c *chunkedReader) verifyChunk() error {
+	if c.verify == nil {
+		return nil
+	}
+	return c.verify(hex.EncodeToString(c.hash.Sum(nil)), c.signature)
+}
+
+// Gövde sonuna gelindiğinde SHA-256'sı x-amz-content-sha256 ile karşılaştırılır, uyuşmazsa son okuma hata döner
+func NewPayloadReader(r io.Reader, payloadHash string) io.Reader {
+	return &payloadReader{r: r, hash: sha256.New(), expected: payloadHash}
+}
+
+type payloadReader struct {
+	r        io.Reader
+	hash     hash.Hash
+	expected string
+}
+
+func (p *payloadReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.hash.Write(b[:n])
+	if err == io.EOF && hex.EncodeToString(p.hash.Sum(nil)) != p.expected {
+		return n, ErrPayloadHashMismatch
+	}
+	return n, err
+}
+
+// STREAMING-AWS4-HMAC-SHA256-PAYLOAD chunk imzası, bir önceki imzaya zincirlenir
+func ChunkStringToSign(amzDate, scope, previousSignature, chunkHash string) string {
+	return strings.Join([]string{
+		Algorithm + "-PAYLOAD",
+		amzDate,
+		scope,
+		previousSignature,
+		emptySHA256,
+		chunkHash,
+	}, "\n")
+}
+
+func HashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}